@@ -0,0 +1,209 @@
+package collector
+
+import (
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// CallFrame is a node of the call tree of a transaction: the transaction
+// itself at the root, then every call and creation it made, in execution
+// order. A frame that never got to run, because the call depth was exceeded
+// or the caller lacked the value, is still listed and marked unsuccessful.
+type CallFrame struct {
+	Type        string         `json:"type"`        // one of the Message* constants other than MessageSuicide
+	CallLayer   uint64         `json:"calllayer"`   // SODA frame id, as in the call stack
+	Depth       uint64         `json:"depth"`       // nesting depth, 0 for the transaction
+	Caller      common.Address `json:"caller"`      // msg.sender seen by the code
+	Callee      common.Address `json:"callee"`      // account whose storage and balance the code uses
+	CodeAddress common.Address `json:"codeaddress"` // account whose code runs, differs from Callee for CALLCODE and DELEGATECALL
+	Value       Word           `json:"value"`       // wei moved, always zero for DELEGATECALL and STATICCALL
+	Input       []byte         `json:"input"`       // call data, or the init code of a creation
+	Output      []byte         `json:"output"`      // return data, or the code returned by a creation
+	Gas         uint64         `json:"gas"`         // gas handed to the frame
+	GasUsed     uint64         `json:"gasused"`
+	Success     bool           `json:"success"`  // whether the frame returned without error
+	Reverted    bool           `json:"reverted"` // whether a failing ancestor undid the frame
+	Children    []*CallFrame   `json:"children,omitempty"`
+
+	Failure      string `json:"failure"`      // cause of a failed frame, one of the Failure* constants
+	RevertReason string `json:"revertreason"` // Error(string) message of a reverted frame
+
+	GasSelf       uint64 `json:"gasself"`       // gas used by the frame's own instructions, children excluded
+	RefundAdded   uint64 `json:"refundadded"`   // refunds granted by the frame's own instructions, zero unless committed
+	RefundRemoved uint64 `json:"refundremoved"` // refunds withdrawn by the frame's own instructions, zero unless committed
+}
+
+// Committed reports whether the effects of the frame survived, i.e. it
+// succeeded and no ancestor failed.
+func (f *CallFrame) Committed() bool {
+	return f.Success && !f.Reverted
+}
+
+// Walk calls fn for the frame and all its descendants in execution order.
+// Children of a frame are skipped if fn returns false for it.
+func (f *CallFrame) Walk(fn func(f *CallFrame) bool) {
+	if !fn(f) {
+		return
+	}
+	for _, child := range f.Children {
+		child.Walk(fn)
+	}
+}
+
+// SendCallTreeEvent wraps the call tree rooted at the frame into an envelope
+// for dispatch.
+func (f *CallFrame) SendCallTreeEvent() *Event {
+	return &Event{Option: "CALLTREE", CallTree: f}
+}
+
+// callFrameRLP is the RLP form of a call frame. It is only needed to decode
+// a frame without children into a nil slice, as the other codecs do.
+type callFrameRLP struct {
+	Type        string
+	CallLayer   uint64
+	Depth       uint64
+	Caller      common.Address
+	Callee      common.Address
+	CodeAddress common.Address
+	Value       Word
+	Input       []byte
+	Output      []byte
+	Gas         uint64
+	GasUsed     uint64
+	Success     bool
+	Reverted    bool
+	Children    []*CallFrame
+
+	Failure      string
+	RevertReason string
+
+	GasSelf       uint64
+	RefundAdded   uint64
+	RefundRemoved uint64
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (f *CallFrame) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, (*callFrameRLP)(f))
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (f *CallFrame) DecodeRLP(s *rlp.Stream) error {
+	if err := s.Decode((*callFrameRLP)(f)); err != nil {
+		return err
+	}
+	if len(f.Children) == 0 {
+		f.Children = nil
+	}
+	return nil
+}
+
+// CallTree builds the call tree of a transaction while it executes. The
+// interpreter opens a frame when a message starts and closes it when the
+// message returns, so at any point the tree holds every frame entered so
+// far. The Reverted flag of a frame is only final once the transaction ended.
+// The zero value is an empty tree.
+type CallTree struct {
+	root  *CallFrame
+	stack []*CallFrame // open frames from the root to the current one
+
+	transfers []*BalanceTransfer
+	movers    []*CallFrame // frame that made each transfer
+
+	tokens      []*TokenTransfer
+	tokenFrames []*CallFrame // frame that made each token call or log
+}
+
+// Reset empties the tree for a new transaction.
+func (t *CallTree) Reset() {
+	t.root, t.stack = nil, t.stack[:0]
+	t.transfers, t.movers = nil, nil
+	t.tokens, t.tokenFrames = nil, nil
+}
+
+// Enter opens a frame as a child of the current one, or as the root if no
+// frame is open.
+func (t *CallTree) Enter(f *CallFrame) {
+	f.Depth = uint64(len(t.stack))
+	if parent := t.Current(); parent != nil {
+		parent.Children = append(parent.Children, f)
+	} else {
+		t.root = f
+	}
+	t.stack = append(t.stack, f)
+}
+
+// Exit closes the current frame with its outcome, failure being the cause
+// of a failed frame or empty. A failed frame marks all its descendants as
+// reverted.
+func (t *CallTree) Exit(output []byte, gasUsed uint64, failure string) {
+	f := t.Current()
+	if f == nil {
+		return
+	}
+	t.stack = t.stack[:len(t.stack)-1]
+	f.Output, f.GasUsed, f.Success = output, gasUsed, failure == ""
+	f.Failure, f.RevertReason = failure, failureReason(failure, output)
+	f.GasSelf = gasUsed
+	for _, child := range f.Children {
+		if child.GasUsed < f.GasSelf {
+			f.GasSelf -= child.GasUsed
+		} else {
+			f.GasSelf = 0
+		}
+	}
+	if !f.Success {
+		// The refund counter is reverted with the state of the frame.
+		f.RefundAdded, f.RefundRemoved = 0, 0
+		for _, child := range f.Children {
+			child.Walk(func(f *CallFrame) bool {
+				f.Reverted = true
+				f.RefundAdded, f.RefundRemoved = 0, 0
+				return true
+			})
+		}
+	}
+}
+
+// Root returns the frame of the transaction, nil before it started.
+func (t *CallTree) Root() *CallFrame {
+	return t.root
+}
+
+// Current returns the innermost open frame, nil outside of execution.
+func (t *CallTree) Current() *CallFrame {
+	if len(t.stack) == 0 {
+		return nil
+	}
+	return t.stack[len(t.stack)-1]
+}
+
+// Stack returns the open frames from the root to the current one. The slice
+// is only valid until the next frame is entered or left.
+func (t *CallTree) Stack() []*CallFrame {
+	return t.stack
+}
+
+// Transfer records ether moved by the current frame. It is ignored outside
+// of execution.
+func (t *CallTree) Transfer(tr *BalanceTransfer) {
+	f := t.Current()
+	if f == nil {
+		return
+	}
+	tr.CallLayer = f.CallLayer
+	t.transfers = append(t.transfers, tr)
+	t.movers = append(t.movers, f)
+}
+
+// Transfers returns the transfers recorded so far in execution order, with
+// Reverted set for those whose frame failed or was reverted by a failing
+// ancestor.
+func (t *CallTree) Transfers() []*BalanceTransfer {
+	for i, tr := range t.transfers {
+		tr.Reverted = !t.movers[i].Committed()
+	}
+	return t.transfers
+}
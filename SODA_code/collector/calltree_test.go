@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCallTree(t *testing.T) {
+	var (
+		tree CallTree
+		a    = common.HexToAddress("0x0a")
+		b    = common.HexToAddress("0x0b")
+		c    = common.HexToAddress("0x0c")
+	)
+	if tree.Root() != nil || tree.Current() != nil {
+		t.Fatal("zero tree not empty")
+	}
+	// a calls b, which calls c and fails afterwards, then a calls c.
+	tree.Enter(&CallFrame{Type: MessageCall, CallLayer: 1, Callee: a})
+	tree.Enter(&CallFrame{Type: MessageCall, CallLayer: 2, Callee: b})
+	tree.Transfer(&BalanceTransfer{Reason: TransferCall, To: b})
+	tree.Current().RefundAdded = 15000
+	tree.Enter(&CallFrame{Type: MessageStaticCall, CallLayer: 3, Callee: c})
+	tree.Current().RefundRemoved = 4800
+	if have := len(tree.Stack()); have != 3 {
+		t.Fatalf("stack size mismatch: have %d, want 3", have)
+	}
+	tree.Exit([]byte{1}, 100, "")
+	tree.Exit(errorData("not owner"), 500, FailureRevert)
+	tree.Enter(&CallFrame{Type: MessageCall, CallLayer: 4, Callee: c})
+	if have := tree.Current().Depth; have != 1 {
+		t.Errorf("depth mismatch: have %d, want 1", have)
+	}
+	tree.Transfer(&BalanceTransfer{Reason: TransferSuicide, From: c})
+	tree.Current().RefundAdded = 24000
+	tree.Exit(nil, 50, "")
+	tree.Exit(nil, 1000, "")
+	tree.Transfer(&BalanceTransfer{Reason: TransferCall})
+
+	root := tree.Root()
+	if tree.Current() != nil || root == nil || root.Callee != a {
+		t.Fatalf("unexpected tree state: root %+v, current %+v", root, tree.Current())
+	}
+	var (
+		layers    []uint64
+		committed []bool
+	)
+	root.Walk(func(f *CallFrame) bool {
+		layers = append(layers, f.CallLayer)
+		committed = append(committed, f.Committed())
+		return true
+	})
+	wantLayers, wantCommitted := []uint64{1, 2, 3, 4}, []bool{true, false, false, true}
+	for i := range wantLayers {
+		if i >= len(layers) || layers[i] != wantLayers[i] || committed[i] != wantCommitted[i] {
+			t.Fatalf("walk mismatch: have layers %v committed %v, want %v %v", layers, committed, wantLayers, wantCommitted)
+		}
+	}
+	if inner := root.Children[0].Children[0]; !inner.Success || !inner.Reverted || inner.GasUsed != 100 {
+		t.Errorf("unexpected frame reverted by its parent: %+v", inner)
+	}
+	if failed := root.Children[0]; failed.Failure != FailureRevert || failed.RevertReason != "not owner" {
+		t.Errorf("unexpected failure: %q %q", failed.Failure, failed.RevertReason)
+	}
+	if failed, inner := root.Children[0], root.Children[0].Children[0]; failed.RefundAdded != 0 || inner.RefundRemoved != 0 {
+		t.Errorf("refunds of reverted frames kept: %d added, %d removed", failed.RefundAdded, inner.RefundRemoved)
+	}
+	if committed := root.Children[1]; committed.RefundAdded != 24000 {
+		t.Errorf("refund of committed frame mismatch: have %d, want 24000", committed.RefundAdded)
+	}
+	transfers := tree.Transfers()
+	if len(transfers) != 2 {
+		t.Fatalf("have %d transfers, want 2", len(transfers))
+	}
+	if tr := transfers[0]; tr.To != b || tr.CallLayer != 2 || !tr.Reverted {
+		t.Errorf("unexpected transfer of the failed frame: %+v", tr)
+	}
+	if tr := transfers[1]; tr.From != c || tr.CallLayer != 4 || tr.Reverted {
+		t.Errorf("unexpected transfer of the committed frame: %+v", tr)
+	}
+
+	tree.Reset()
+	if tree.Root() != nil || len(tree.Stack()) != 0 || len(tree.Transfers()) != 0 {
+		t.Error("reset tree not empty")
+	}
+}
@@ -0,0 +1,401 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Events can leave the process in three lossless encodings, all carrying
+// SchemaVersion:
+//
+//   - JSON: the tagged fields of the event types plus a "version" member.
+//     Words, hashes and addresses are 0x-prefixed hex, byte slices base64.
+//   - RLP: the list [version, option, kind, payload] where payload is the
+//     list of the payload fields in declaration order, or an empty list for
+//     flag events.
+//   - Protobuf: the Event message of events.proto, see proto.go.
+
+// versionError is returned when decoding an event of an unknown schema version.
+func versionError(version uint64) error {
+	return fmt.Errorf("collector: unsupported event schema version %d, want %d", version, SchemaVersion)
+}
+
+// payload returns the payload of the event, nil for a flag event.
+func (ev *Event) payload() interface{} {
+	switch ev.Kind() {
+	case KindIns:
+		return ev.Ins
+	case KindTxStart:
+		return ev.TxStart
+	case KindTxEnd:
+		return ev.TxEnd
+	case KindMessage:
+		return ev.Message
+	case KindBlock:
+		return ev.Block
+	case KindCallTree:
+		return ev.CallTree
+	case KindStateDiff:
+		return ev.StateDiff
+	case KindTransfer:
+		return ev.Transfer
+	case KindTokenTransfer:
+		return ev.TokenTransfer
+	case KindPrecompile:
+		return ev.Precompile
+	case KindCreation:
+		return ev.Creation
+	case KindBranch:
+		return ev.Branch
+	case KindBasicBlock:
+		return ev.BasicBlock
+	case KindMemory:
+		return ev.Memory
+	}
+	return nil
+}
+
+// setPayload allocates an empty payload of the given kind, drops any other
+// and returns the new payload, nil for a flag event.
+func (ev *Event) setPayload(kind Kind) (interface{}, error) {
+	*ev = Event{Option: ev.Option}
+	switch kind {
+	case KindFlag:
+		return nil, nil
+	case KindIns:
+		ev.Ins = new(InsEvent)
+	case KindTxStart:
+		ev.TxStart = new(TxStartEvent)
+	case KindTxEnd:
+		ev.TxEnd = new(TxEndEvent)
+	case KindMessage:
+		ev.Message = new(MessageEvent)
+	case KindBlock:
+		ev.Block = new(BlockEvent)
+	case KindCallTree:
+		ev.CallTree = new(CallFrame)
+	case KindStateDiff:
+		ev.StateDiff = new(StateDiff)
+	case KindTransfer:
+		ev.Transfer = new(BalanceTransfer)
+	case KindTokenTransfer:
+		ev.TokenTransfer = new(TokenTransfer)
+	case KindPrecompile:
+		ev.Precompile = new(PrecompileCall)
+	case KindCreation:
+		ev.Creation = new(ContractCreation)
+	case KindBranch:
+		ev.Branch = new(Branch)
+	case KindBasicBlock:
+		ev.BasicBlock = new(BasicBlock)
+	case KindMemory:
+		ev.Memory = new(MemoryAccess)
+	default:
+		return nil, fmt.Errorf("collector: unknown event kind %d", kind)
+	}
+	return ev.payload(), nil
+}
+
+// jsonEvent is the JSON form of an event.
+type jsonEvent struct {
+	Version uint64 `json:"version"`
+	Option  string `json:"option"`
+
+	Ins           *InsEvent         `json:"ins,omitempty"`
+	TxStart       *TxStartEvent     `json:"txstart,omitempty"`
+	TxEnd         *TxEndEvent       `json:"txend,omitempty"`
+	Message       *MessageEvent     `json:"message,omitempty"`
+	Block         *BlockEvent       `json:"block,omitempty"`
+	CallTree      *CallFrame        `json:"calltree,omitempty"`
+	StateDiff     *StateDiff        `json:"statediff,omitempty"`
+	Transfer      *BalanceTransfer  `json:"transfer,omitempty"`
+	TokenTransfer *TokenTransfer    `json:"tokentransfer,omitempty"`
+	Precompile    *PrecompileCall   `json:"precompile,omitempty"`
+	Creation      *ContractCreation `json:"creation,omitempty"`
+	Branch        *Branch           `json:"branch,omitempty"`
+	BasicBlock    *BasicBlock       `json:"basicblock,omitempty"`
+	Memory        *MemoryAccess     `json:"memory,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (ev *Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jsonEvent{
+		Version:       SchemaVersion,
+		Option:        ev.Option,
+		Ins:           ev.Ins,
+		TxStart:       ev.TxStart,
+		TxEnd:         ev.TxEnd,
+		Message:       ev.Message,
+		Block:         ev.Block,
+		CallTree:      ev.CallTree,
+		StateDiff:     ev.StateDiff,
+		Transfer:      ev.Transfer,
+		TokenTransfer: ev.TokenTransfer,
+		Precompile:    ev.Precompile,
+		Creation:      ev.Creation,
+		Branch:        ev.Branch,
+		BasicBlock:    ev.BasicBlock,
+		Memory:        ev.Memory,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (ev *Event) UnmarshalJSON(input []byte) error {
+	return ev.unmarshalJSON(input, true)
+}
+
+// UnmarshalAnyJSON decodes the JSON form of an event of any schema version.
+// Members this version doesn't know are ignored and missing ones stay zero,
+// so an event of an older or newer schema loses the fields they don't share.
+func (ev *Event) UnmarshalAnyJSON(input []byte) error {
+	return ev.unmarshalJSON(input, false)
+}
+
+func (ev *Event) unmarshalJSON(input []byte, strict bool) error {
+	var dec jsonEvent
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if strict && dec.Version != SchemaVersion {
+		return versionError(dec.Version)
+	}
+	*ev = Event{
+		Option:        dec.Option,
+		Ins:           dec.Ins,
+		TxStart:       dec.TxStart,
+		TxEnd:         dec.TxEnd,
+		Message:       dec.Message,
+		Block:         dec.Block,
+		CallTree:      dec.CallTree,
+		StateDiff:     dec.StateDiff,
+		Transfer:      dec.Transfer,
+		TokenTransfer: dec.TokenTransfer,
+		Precompile:    dec.Precompile,
+		Creation:      dec.Creation,
+		Branch:        dec.Branch,
+		BasicBlock:    dec.BasicBlock,
+		Memory:        dec.Memory,
+	}
+	return nil
+}
+
+// rlpEvent is the RLP form of an event.
+type rlpEvent struct {
+	Version uint64
+	Option  string
+	Kind    Kind
+	Payload rlp.RawValue
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (ev *Event) EncodeRLP(w io.Writer) error {
+	enc := rlpEvent{Version: SchemaVersion, Option: ev.Option, Kind: ev.Kind(), Payload: rlp.EmptyList}
+	if payload := ev.payload(); payload != nil {
+		blob, err := rlp.EncodeToBytes(payload)
+		if err != nil {
+			return err
+		}
+		enc.Payload = blob
+	}
+	return rlp.Encode(w, &enc)
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (ev *Event) DecodeRLP(s *rlp.Stream) error {
+	var dec rlpEvent
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	if dec.Version != SchemaVersion {
+		return versionError(dec.Version)
+	}
+	ev.Option = dec.Option
+	payload, err := ev.setPayload(dec.Kind)
+	if err != nil || payload == nil {
+		return err
+	}
+	if err := rlp.DecodeBytes(dec.Payload, payload); err != nil {
+		return err
+	}
+	// RLP decodes a call without arguments into an empty slice, the other
+	// codecs into a nil one.
+	if call := ev.decoded(); call != nil && len(call.Args) == 0 {
+		call.Args = nil
+	}
+	return nil
+}
+
+// decoded returns the decoded call or log carried by the event, if any.
+func (ev *Event) decoded() *DecodedCall {
+	switch {
+	case ev.Ins != nil:
+		return ev.Ins.Decoded
+	case ev.TxStart != nil:
+		return ev.TxStart.Decoded
+	case ev.Message != nil:
+		return ev.Message.Decoded
+	case ev.Precompile != nil:
+		return ev.Precompile.Decoded
+	}
+	return nil
+}
+
+// insRLP is the RLP form of an instruction event. RLP has no signed integers,
+// so the call layer, which is never negative, is kept unsigned.
+type insRLP struct {
+	OpName    string
+	Pc        uint64
+	PcNext    uint64
+	CallLayer uint64
+
+	From         common.Address
+	To           common.Address
+	CallContract common.Address
+	Value        Word
+
+	FrameType      string
+	CodeAddress    common.Address
+	StorageAddress common.Address
+	Sender         common.Address
+	CallValue      Word
+
+	Args      []Word
+	ArgTaint  []Taint
+	Result    Word
+	HasResult bool
+
+	RetArgs    []byte
+	InputData  []byte
+	ByteCode   []byte
+	MemoryData []byte
+
+	PreValue     common.Hash
+	CurrentValue common.Hash
+	Slot         *SlotPath `rlp:"nil"`
+
+	AllocatedGas uint64
+	RealGasUsed  uint64
+
+	GasBefore    uint64
+	StaticGas    uint64
+	DynamicGas   uint64
+	GasForwarded uint64
+	GasReturned  uint64
+	RefundBefore uint64
+	RefundAfter  uint64
+
+	InternalErr         string
+	IsInternalSucceeded bool
+	IsCallValid         bool
+	Failure             string
+	RevertReason        string
+
+	Decoded *DecodedCall `rlp:"nil"`
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (e *InsEvent) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &insRLP{
+		OpName:              e.OpName,
+		Pc:                  e.Pc,
+		PcNext:              e.PcNext,
+		CallLayer:           uint64(e.CallLayer),
+		From:                e.From,
+		To:                  e.To,
+		CallContract:        e.CallContract,
+		Value:               e.Value,
+		FrameType:           e.FrameType,
+		CodeAddress:         e.CodeAddress,
+		StorageAddress:      e.StorageAddress,
+		Sender:              e.Sender,
+		CallValue:           e.CallValue,
+		Args:                e.Args,
+		ArgTaint:            e.ArgTaint,
+		Result:              e.Result,
+		HasResult:           e.HasResult,
+		RetArgs:             e.RetArgs,
+		InputData:           e.InputData,
+		ByteCode:            e.ByteCode,
+		MemoryData:          e.MemoryData,
+		PreValue:            e.PreValue,
+		CurrentValue:        e.CurrentValue,
+		Slot:                e.Slot,
+		AllocatedGas:        e.AllocatedGas,
+		RealGasUsed:         e.RealGasUsed,
+		GasBefore:           e.GasBefore,
+		StaticGas:           e.StaticGas,
+		DynamicGas:          e.DynamicGas,
+		GasForwarded:        e.GasForwarded,
+		GasReturned:         e.GasReturned,
+		RefundBefore:        e.RefundBefore,
+		RefundAfter:         e.RefundAfter,
+		InternalErr:         e.InternalErr,
+		IsInternalSucceeded: e.IsInternalSucceeded,
+		IsCallValid:         e.IsCallValid,
+		Failure:             e.Failure,
+		RevertReason:        e.RevertReason,
+		Decoded:             e.Decoded,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (e *InsEvent) DecodeRLP(s *rlp.Stream) error {
+	var dec insRLP
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	*e = InsEvent{
+		OpName:              dec.OpName,
+		Pc:                  dec.Pc,
+		PcNext:              dec.PcNext,
+		CallLayer:           int(dec.CallLayer),
+		From:                dec.From,
+		To:                  dec.To,
+		CallContract:        dec.CallContract,
+		Value:               dec.Value,
+		FrameType:           dec.FrameType,
+		CodeAddress:         dec.CodeAddress,
+		StorageAddress:      dec.StorageAddress,
+		Sender:              dec.Sender,
+		CallValue:           dec.CallValue,
+		Args:                dec.Args,
+		ArgTaint:            dec.ArgTaint,
+		Result:              dec.Result,
+		HasResult:           dec.HasResult,
+		RetArgs:             dec.RetArgs,
+		InputData:           dec.InputData,
+		ByteCode:            dec.ByteCode,
+		MemoryData:          dec.MemoryData,
+		PreValue:            dec.PreValue,
+		CurrentValue:        dec.CurrentValue,
+		Slot:                dec.Slot,
+		AllocatedGas:        dec.AllocatedGas,
+		RealGasUsed:         dec.RealGasUsed,
+		GasBefore:           dec.GasBefore,
+		StaticGas:           dec.StaticGas,
+		DynamicGas:          dec.DynamicGas,
+		GasForwarded:        dec.GasForwarded,
+		GasReturned:         dec.GasReturned,
+		RefundBefore:        dec.RefundBefore,
+		RefundAfter:         dec.RefundAfter,
+		InternalErr:         dec.InternalErr,
+		IsInternalSucceeded: dec.IsInternalSucceeded,
+		IsCallValid:         dec.IsCallValid,
+		Failure:             dec.Failure,
+		RevertReason:        dec.RevertReason,
+		Decoded:             dec.Decoded,
+	}
+	if len(e.ArgTaint) == 0 {
+		e.ArgTaint = nil
+	}
+	for p := e.Slot; p != nil; p = p.Base {
+		if len(p.Key) == 0 {
+			p.Key = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// testEvents returns one event of every kind with all fields set, so any
+// field dropped by a codec shows up in the round trip.
+func testEvents() []*Event {
+	var (
+		alice = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+		bob   = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+		hash  = common.HexToHash("0x5e1f")
+		large = BigToWord(new(big.Int).Lsh(big.NewInt(1), 255))
+		call  = &DecodedCall{Signature: "transfer(address,uint256)", Name: "transfer", Args: []DecodedArg{
+			{Name: "to", Type: "address", Value: bob.Hex()}, {Type: "uint256", Value: "5"},
+		}}
+	)
+	return []*Event{
+		FlagEvent("TXSTART"),
+		{Option: "CALLSTART", Ins: &InsEvent{
+			OpName: "CALLSTART", Pc: 7, PcNext: 8, CallLayer: 2,
+			From: alice, To: bob, CallContract: bob, Value: Uint64ToWord(3),
+			FrameType: MessageDelegateCall, CodeAddress: bob, StorageAddress: alice, Sender: bob, CallValue: large,
+			Args: []Word{{}, AddressToWord(bob), large}, Result: Uint64ToWord(1), HasResult: true,
+			ArgTaint: []Taint{0, TaintCallData, TaintTimestamp | TaintReturnData},
+			RetArgs:  []byte{1}, InputData: []byte{2}, ByteCode: []byte{3}, MemoryData: []byte{4},
+			PreValue: hash, CurrentValue: common.HexToHash("0x01"),
+			Slot:         &SlotPath{Kind: SlotMapping, Slot: Word(hash), Key: alice.Bytes(), Index: 1, Base: &SlotPath{Kind: SlotFixed, Slot: Uint64ToWord(2)}},
+			AllocatedGas: 2300, RealGasUsed: 700,
+			GasBefore: 9000, StaticGas: 700, DynamicGas: 9, GasForwarded: 2300, GasReturned: 100, RefundBefore: 15000, RefundAfter: 30000,
+			InternalErr: "out of gas", IsInternalSucceeded: true, IsCallValid: true,
+			Failure: FailureOutOfGas,
+			Decoded: call,
+		}},
+		(&TxStartEvent{
+			TxHash: hash, BlockNumber: 1920000, BlockTime: 1469020840,
+			From: alice, To: bob, Create: true, Value: large, GasPrice: Uint64ToWord(20e9),
+			GasLimit: 90000, Nonce: 5, Input: []byte{0xa9, 0x05}, Code: []byte{0x60}, Decoded: call,
+		}).SendTxStartEvent(),
+		(&TxEndEvent{
+			TxHash: hash, GasUsed: 21000, Create: true,
+			Contract: bob, DeployCode: []byte{0x60, 0x80}, RuntimeCode: []byte{0x00},
+			Failure: FailureRevert, RevertReason: "not owner",
+			From: alice, To: bob, Value: large, Input: []byte{0xa9},
+			BlockNumber: 7, TxIndex: 2, Status: 1, PostState: hash.Bytes(), CumulativeGasUsed: 42000, Bloom: make([]byte, 256),
+			Logs: []*Log{{Address: bob, Topics: []common.Hash{hash, alice.Hash()}, Data: []byte{5}, Index: 3, Decoded: call}},
+		}).SendTxEndEvent(),
+		(&MessageEvent{
+			Type: MessageCall, Pc: 1, CallLayer: 3, From: alice, To: bob, Value: large,
+			Input: []byte{0xff}, Code: []byte{0x5b}, Failure: FailureDepth, Salt: Uint64ToWord(9),
+			Decoded: &DecodedCall{Signature: "withdraw()", Name: "withdraw"},
+		}).SendMessageEvent(),
+		(&BlockEvent{
+			Number: 1, ParentHash: hash, UncleHash: hash, Coinbase: alice, StateRoot: hash,
+			TxHashRoot: hash, ReceiptHash: hash, Bloom: make([]byte, 256), Difficulty: large,
+			GasLimit: 8e6, GasUsed: 21000, Time: 10, Extra: []byte("soda"), MixDigest: hash, Nonce: 42,
+		}).SendBlockEvent(),
+		(&CallFrame{
+			Type: MessageCall, CallLayer: 1, Caller: alice, Callee: bob, CodeAddress: bob, Value: large,
+			Input: []byte{0x01}, Output: []byte{0x02}, Gas: 90000, GasUsed: 30000, Success: true,
+			GasSelf: 2000, RefundAdded: 15000, RefundRemoved: 4800,
+			Children: []*CallFrame{{
+				Type: MessageDelegateCall, CallLayer: 2, Depth: 1, Caller: alice, Callee: bob, CodeAddress: alice,
+				Input: []byte{0x03}, Output: []byte{0x04}, Gas: 60000, GasUsed: 60000, Reverted: true,
+				Failure: FailureRevert, RevertReason: "paused",
+			}},
+		}).SendCallTreeEvent(),
+		(&StateDiff{TxHash: hash, Accounts: []*AccountDiff{{
+			Address: bob, Created: true, Suicided: true,
+			BalanceChanged: true, PreBalance: Uint64ToWord(1), PostBalance: large, BalanceFrame: 1,
+			NonceChanged: true, PreNonce: 0, PostNonce: 1, NonceFrame: 2,
+			CodeChanged: true, PreCode: []byte{0x01}, PostCode: []byte{0x60, 0x00}, CodeFrame: 2,
+			Storage: []*StorageDiff{{Key: hash, Pre: common.HexToHash("0x01"), Post: hash, Frame: 3}},
+		}}}).SendStateDiffEvent(),
+		(&BalanceTransfer{
+			Reason: TransferSuicide, TxHash: hash, BlockNumber: 7, From: alice, To: bob,
+			Amount: large, CallLayer: 3, Reverted: true,
+		}).SendBalanceTransferEvent(),
+		(&TokenTransfer{
+			Action: TokenTransferred, Standard: TokenERC20, Token: bob, TxHash: hash, CallLayer: 2,
+			Method: "transferFrom", From: alice, To: bob, Amount: large, FromCall: true, FromLog: true,
+			Consistent: true, Slots: []*StorageDiff{{Key: hash, Post: hash, Frame: 2}}, Reverted: true,
+		}).SendTokenTransferEvent(),
+		(&PrecompileCall{
+			Name: PrecompileEcrecover, Address: common.BytesToAddress([]byte{1}), TxHash: hash, CallLayer: 3,
+			Caller: alice, Input: make([]byte, 128), Output: bob.Hash().Bytes(), Gas: 3000, Failure: FailureOutOfGas,
+			Decoded: &DecodedCall{Signature: "ecrecover(bytes32)", Name: "ecrecover", Args: []DecodedArg{{Name: "hash", Type: "bytes32", Value: hash.Hex()}}},
+			Signer:  bob, HighS: true,
+		}).SendPrecompileEvent(),
+		(&ContractCreation{
+			Type: CreatedByCreate2, TxHash: hash, BlockNumber: 7, Creator: bob, Deployer: alice, Address: alice,
+			Salt: Uint64ToWord(9), InitCodeHash: hash, RuntimeCode: []byte{0x00}, Value: large, Depth: 1, CallLayer: 2,
+			Failure: FailureRevert,
+		}).SendContractCreatedEvent(),
+		(&Branch{
+			Op: "JUMPI", TxHash: hash, CallLayer: 2, CodeAddress: bob, CodeHash: hash, Pc: 10,
+			Dest: large, Condition: Uint64ToWord(1), Taken: true, Next: 20,
+		}).SendBranchEvent(),
+		(&BasicBlock{TxHash: hash, CallLayer: 2, CodeAddress: bob, CodeHash: hash, Start: 20, End: 31}).SendBasicBlockEvent(),
+		(&MemoryAccess{
+			Op: "CALL", Direction: MemoryWrite, TxHash: hash, CallLayer: 2, CodeAddress: bob, Pc: 40,
+			Offset: 64, Length: 3, Data: []byte{1, 2, 3},
+		}).SendMemoryEvent(),
+	}
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	codecs := map[string]func(*Event) (*Event, error){
+		"json": func(ev *Event) (*Event, error) {
+			blob, err := json.Marshal(ev)
+			if err != nil {
+				return nil, err
+			}
+			dec := new(Event)
+			return dec, json.Unmarshal(blob, dec)
+		},
+		"rlp": func(ev *Event) (*Event, error) {
+			blob, err := rlp.EncodeToBytes(ev)
+			if err != nil {
+				return nil, err
+			}
+			dec := new(Event)
+			return dec, rlp.DecodeBytes(blob, dec)
+		},
+		"proto": func(ev *Event) (*Event, error) {
+			blob, err := ev.MarshalProto()
+			if err != nil {
+				return nil, err
+			}
+			dec := new(Event)
+			return dec, dec.UnmarshalProto(blob)
+		},
+	}
+	for name, roundTrip := range codecs {
+		for _, ev := range testEvents() {
+			dec, err := roundTrip(ev)
+			if err != nil {
+				t.Errorf("%s %s: %v", name, ev.Option, err)
+				continue
+			}
+			if !reflect.DeepEqual(dec, ev) {
+				t.Errorf("%s %s: round trip mismatch\nhave %+v\nwant %+v", name, ev.Option, dec, ev)
+			}
+			if dec.Kind() != ev.Kind() {
+				t.Errorf("%s %s: kind mismatch: have %d, want %d", name, ev.Option, dec.Kind(), ev.Kind())
+			}
+		}
+	}
+}
+
+func TestEventVersion(t *testing.T) {
+	ev := FlagEvent("TXEND")
+
+	blob, _ := json.Marshal(ev)
+	version := []byte(fmt.Sprintf(`"version":%d`, SchemaVersion))
+	if !bytes.Contains(blob, version) {
+		t.Errorf("json encoding lacks the schema version: %s", blob)
+	}
+	blob = bytes.Replace(blob, version, []byte(fmt.Sprintf(`"version":%d`, SchemaVersion+1)), 1)
+	if err := json.Unmarshal(blob, new(Event)); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("json: expected version error, got %v", err)
+	}
+	blob = []byte(fmt.Sprintf(`{"version":%d,"option":"SSTORE","ins":{"pc":7,"added":true},"added":{}}`, SchemaVersion+1))
+	var dec Event
+	if err := dec.UnmarshalAnyJSON(blob); err != nil {
+		t.Errorf("json: any version: %v", err)
+	} else if dec.Option != "SSTORE" || dec.Ins == nil || dec.Ins.Pc != 7 {
+		t.Errorf("json: any version: unexpected event %+v", dec)
+	}
+
+	blob, _ = rlp.EncodeToBytes(&rlpEvent{Version: SchemaVersion + 1, Option: "TXEND", Payload: rlp.EmptyList})
+	if err := rlp.DecodeBytes(blob, new(Event)); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("rlp: expected version error, got %v", err)
+	}
+
+	var w protoWriter
+	w.uint(1, SchemaVersion+1)
+	if err := new(Event).UnmarshalProto(w); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("proto: expected version error, got %v", err)
+	}
+}
+
+func TestEventCompat(t *testing.T) {
+	tx := (&TxStartEvent{From: common.HexToAddress("0x01"), To: common.HexToAddress("0x02"), Value: Uint64ToWord(10), Input: []byte{1}}).SendTxStartEvent()
+	if have := tx.Compat().TransInfo; have.CallType != "CALL" || have.Value != "10" || have.CallLayer != 1 || !bytes.Equal(have.CallInfo.InputData, []byte{1}) {
+		t.Errorf("unexpected EXTERNALINFOSTART view: %+v", have)
+	}
+	msg := (&MessageEvent{Type: MessageDelegateCall, CallLayer: 2, Value: Uint64ToWord(10)}).SendMessageEvent()
+	if msg.Option != "TRANS_DELEGATECALL" {
+		t.Errorf("unexpected message option %q", msg.Option)
+	}
+	if have := msg.Compat().TransInfo; have.Op != "TRANS_DELEGATECALL" || have.Value != "" || have.CallLayer != 2 || have.CallType != "CALL" {
+		t.Errorf("unexpected TRANS_DELEGATECALL view: %+v", have)
+	}
+	create := (&MessageEvent{Type: MessageCreate, To: common.HexToAddress("0x03"), Code: []byte{0x60}}).SendMessageEvent()
+	if have := create.Compat().TransInfo; have.CallType != "CREATE" || have.CreateInfo.ContractAddr != have.To || have.Value != "0" {
+		t.Errorf("unexpected TRANS_CREATE view: %+v", have)
+	}
+	block := (&BlockEvent{Number: 7}).SendBlockEvent()
+	if have := block.Compat().BlockInfo; have.Op != "Block7" || have.Number != "7" {
+		t.Errorf("unexpected BLOCK_INFO view: %+v", have)
+	}
+}
+
+func TestInsEventCompat(t *testing.T) {
+	minusEight := BigToWord(big.NewInt(-8))
+	tests := []struct {
+		op       string
+		args     []Word
+		wantArgs []string
+	}{
+		{"ADD", []Word{Uint64ToWord(1), Uint64ToWord(2)}, []string{"1", "2"}},
+		// SAR shows the value it shifts signed, SHR doesn't.
+		{"SAR", []Word{Uint64ToWord(1), minusEight}, []string{"1", "-8"}},
+		{"SAR", []Word{Uint64ToWord(1), Uint64ToWord(8)}, []string{"1", "8"}},
+		{"SHR", []Word{Uint64ToWord(1), minusEight}, []string{"1", minusEight.String()}},
+		{"SSTORE", []Word{Uint64ToWord(1), Uint64ToWord(2)}, []string{Uint64ToWord(1).Hex(), "2"}},
+	}
+	for i, tt := range tests {
+		e := NewInsEvent()
+		e.OpName = tt.op
+		e.AddWords(tt.args...)
+		e.Result, e.HasResult = minusEight, true
+		ic := e.Compat()
+		if !reflect.DeepEqual(ic.OpInOut.OpArgs, tt.wantArgs) {
+			t.Errorf("test %d (%s): have args %v, want %v", i, tt.op, ic.OpInOut.OpArgs, tt.wantArgs)
+		}
+		// Results were always printed unsigned.
+		if ic.OpInOut.OpResult != minusEight.String() {
+			t.Errorf("test %d (%s): have result %s", i, tt.op, ic.OpInOut.OpResult)
+		}
+	}
+}
+
+func TestTaintString(t *testing.T) {
+	if have := (TaintOrigin | TaintStorage).String(); have != "ORIGIN|STORAGE" {
+		t.Errorf("unexpected taint name %q", have)
+	}
+	if have := Taint(0).String(); have != "" {
+		t.Errorf("unexpected name of a clean value %q", have)
+	}
+}
@@ -15,7 +15,7 @@ type InsCollector struct {
 	Pc                  uint64   	      `json:"pc"`                  //pc
 	PcNext              string   	      `json:"pcnext"`              //next PC
 	CallLayer			int   		      `json:"calllayer"`		   //call layer
-	AccountValue        AccountValueInfo  `json:"calllayer"`           //from-to-value
+	AccountValue        AccountValueInfo  `json:"accountvalue"`           //from-to-value
 	OpInOut             OpInOutInfo       `json:"opinout"`             //input and output of opcode
 	StoreValue          SstoreValueInfo   `json:"storevalue"`          //SSTORE PreValue/CurrentValue
 	Gas                 GasInfo   	      `json:"gas"`                 //pre-allocated gas and read used gas
@@ -61,7 +61,7 @@ type AccountValueInfo struct{
 
 // transactions information
 type TransCollector struct {
-	Op 					string 			`json:"trans_txhash"`
+	Op 					string 			`json:"trans_op"`
 	TxHash       		string 			`json:"trans_txhash"`
 	BlockNumber  		string 			`json:"trans_blocknumber"`
 	BlockTime			string 			`json:"trans_blocktime"`
@@ -0,0 +1,42 @@
+package collector
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Branch describes an executed JUMP or JUMPI. It is sent as BRANCH once the
+// jump moved the pc, before the instruction event.
+type Branch struct {
+	Op          string         `json:"op"` // JUMP or JUMPI
+	TxHash      common.Hash    `json:"txhash"`
+	CallLayer   uint64         `json:"calllayer"`
+	CodeAddress common.Address `json:"codeaddress"` // account the executing code was loaded from
+	CodeHash    common.Hash    `json:"codehash"`
+	Pc          uint64         `json:"pc"`
+	Dest        Word           `json:"dest"`      // jump target, unchecked if a JUMPI isn't taken
+	Condition   Word           `json:"condition"` // condition of a JUMPI
+	Taken       bool           `json:"taken"`     // whether the jump was taken, always true for JUMP
+	Next        uint64         `json:"next"`      // pc executed next
+}
+
+// SendBranchEvent wraps the branch into an envelope for dispatch.
+func (b *Branch) SendBranchEvent() *Event {
+	return &Event{Option: "BRANCH", Branch: b}
+}
+
+// BasicBlock describes the entry into a basic block: straight-line code that
+// starts at the beginning of the code, at a JUMPDEST or after a JUMPI, and
+// ends at the next JUMP, JUMPI or halting instruction, or before the next
+// JUMPDEST. Blocks are identified by CodeHash and Start. It is sent as
+// BASICBLOCK before the first instruction of the block runs.
+type BasicBlock struct {
+	TxHash      common.Hash    `json:"txhash"`
+	CallLayer   uint64         `json:"calllayer"`
+	CodeAddress common.Address `json:"codeaddress"`
+	CodeHash    common.Hash    `json:"codehash"`
+	Start       uint64         `json:"start"` // pc of the first instruction
+	End         uint64         `json:"end"`   // pc of the last instruction
+}
+
+// SendBasicBlockEvent wraps the block into an envelope for dispatch.
+func (b *BasicBlock) SendBasicBlockEvent() *Event {
+	return &Event{Option: "BASICBLOCK", BasicBlock: b}
+}
@@ -0,0 +1,35 @@
+package collector
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Creation paths of a ContractCreation.
+const (
+	CreatedByTx      = "TX"      // deployed by a contract creation transaction
+	CreatedByCreate  = "CREATE"  // deployed by the CREATE instruction
+	CreatedByCreate2 = "CREATE2" // deployed by the CREATE2 instruction
+)
+
+// ContractCreation describes an attempt to deploy a contract, whichever path
+// it took. It is sent as CONTRACT_CREATED when the creation returned, so a
+// contract deployed by init code is reported before its creator.
+type ContractCreation struct {
+	Type         string         `json:"type"` // one of the CreatedBy* constants
+	TxHash       common.Hash    `json:"txhash"`
+	BlockNumber  uint64         `json:"blocknumber"`
+	Creator      common.Address `json:"creator"`  // account running the creation, the sender for a transaction
+	Deployer     common.Address `json:"deployer"` // externally owned account that sent the transaction
+	Address      common.Address `json:"address"`  // address of the created contract
+	Salt         Word           `json:"salt"`     // salt of a CREATE2
+	InitCodeHash common.Hash    `json:"initcodehash"`
+	RuntimeCode  []byte         `json:"runtimecode"` // code stored at Address, empty if the creation failed
+	Value        Word           `json:"value"`       // endowment in wei
+	Depth        uint64         `json:"depth"`       // EVM depth of the creation, 0 for a transaction
+	CallLayer    uint64         `json:"calllayer"`   // frame running the init code
+	Success      bool           `json:"success"`
+	Failure      string         `json:"failure"` // cause of a failed creation, one of the Failure* constants
+}
+
+// SendContractCreatedEvent wraps the creation into an envelope for dispatch.
+func (c *ContractCreation) SendContractCreatedEvent() *Event {
+	return &Event{Option: "CONTRACT_CREATED", Creation: c}
+}
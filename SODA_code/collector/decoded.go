@@ -0,0 +1,32 @@
+package collector
+
+import "strings"
+
+// DecodedCall is call data or a log decoded against a known method or event
+// signature. The plugin manager attaches it to the events it dispatches when
+// it knows the signature.
+type DecodedCall struct {
+	Signature string       `json:"signature"` // canonical signature, e.g. transfer(address,uint256)
+	Name      string       `json:"name"`
+	Args      []DecodedArg `json:"args"`
+}
+
+// DecodedArg is a decoded argument.
+type DecodedArg struct {
+	Name  string `json:"name"` // empty unless the signature came from an ABI
+	Type  string `json:"type"`
+	Value string `json:"value"` // addresses, hashes and bytes in hex, integers in decimal
+}
+
+// String renders the call as name(type name: value, ...).
+func (c *DecodedCall) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = arg.Type
+		if arg.Name != "" {
+			args[i] += " " + arg.Name
+		}
+		args[i] += ": " + arg.Value
+	}
+	return c.Name + "(" + strings.Join(args, ", ") + ")"
+}
@@ -0,0 +1,270 @@
+package collector
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// InsEvent is the binary form of an instruction event. Stack arguments and
+// results are kept as 32-byte words, accounts as addresses and storage values
+// as hashes, so producing an event never formats anything. Consumers that
+// still expect the decimal and hex strings of InsCollector can get them from
+// Compat.
+type InsEvent struct {
+	OpName    string `json:"opname"`
+	Pc        uint64 `json:"pc"`
+	PcNext    uint64 `json:"pcnext"`
+	CallLayer int    `json:"calllayer"`
+
+	From         common.Address `json:"from"`         // sender of a call or create
+	To           common.Address `json:"to"`           // receiver of a call, create or selfdestruct
+	CallContract common.Address `json:"callcontract"` // code address of the frame, or the callee of a call or create
+	Value        Word           `json:"value"`        // ether moved by the instruction
+
+	// Frame running the instruction. Under DELEGATECALL and CALLCODE the code
+	// of CodeAddress runs on the storage and balance of StorageAddress.
+	FrameType      string         `json:"frametype"`      // one of the Message* constants
+	CodeAddress    common.Address `json:"codeaddress"`    // account the executing code was loaded from
+	StorageAddress common.Address `json:"storageaddress"` // account whose storage the frame reads and writes (ADDRESS)
+	Sender         common.Address `json:"sender"`         // msg.sender of the frame (CALLER)
+	CallValue      Word           `json:"callvalue"`      // msg.value of the frame (CALLVALUE)
+
+	Args      []Word  `json:"args"`      // stack arguments in pop order
+	ArgTaint  []Taint `json:"argtaint"`  // taint of the consumed stack items in pop order, if tracked
+	Result    Word    `json:"result"`    // value pushed by the instruction
+	HasResult bool    `json:"hasresult"` // whether Result was set
+
+	RetArgs    []byte `json:"retargs"`
+	InputData  []byte `json:"inputdata"`
+	ByteCode   []byte `json:"bytecode"`
+	MemoryData []byte `json:"memorydata"`
+
+	PreValue     common.Hash `json:"prevalue"`       // SSTORE slot value before the write
+	CurrentValue common.Hash `json:"currentvalue"`   // SSTORE slot value after the write
+	Slot         *SlotPath   `json:"slot,omitempty"` // SLOAD or SSTORE slot explained by the keccak256 preimages of the transaction
+
+	AllocatedGas uint64 `json:"allocatedgas"` // gas handed to a callee or created contract, as GasForwarded
+	RealGasUsed  uint64 `json:"realgasused"`  // cost of the instruction, the gas consumed by the callee for the end of a call or create
+
+	GasBefore    uint64 `json:"gasbefore"`    // gas left before the instruction
+	StaticGas    uint64 `json:"staticgas"`    // constant cost
+	DynamicGas   uint64 `json:"dynamicgas"`   // dynamic cost, memory expansion included and gas forwarded excluded
+	GasForwarded uint64 `json:"gasforwarded"` // gas handed to a callee or created contract, stipend included
+	GasReturned  uint64 `json:"gasreturned"`  // gas the callee or created contract left unused
+	RefundBefore uint64 `json:"refundbefore"` // refund counter before the instruction
+	RefundAfter  uint64 `json:"refundafter"`  // refund counter after the instruction, its callee included
+
+	InternalErr         string `json:"internalerr"`
+	IsInternalSucceeded bool   `json:"isinternalsucceeded"`
+	IsCallValid         bool   `json:"iscallvalid"`
+	Failure             string `json:"failure"`      // cause of a failed call or creation, one of the Failure* constants
+	RevertReason        string `json:"revertreason"` // Error(string) message of a reverted call or creation
+
+	Decoded *DecodedCall `json:"decoded,omitempty"` // log of a LOG instruction, if its event is known
+}
+
+// NewInsEvent returns an empty instruction event.
+func NewInsEvent() *InsEvent {
+	return &InsEvent{IsInternalSucceeded: true}
+}
+
+// AddArgs appends stack values to the argument list.
+func (e *InsEvent) AddArgs(args ...*big.Int) {
+	for _, arg := range args {
+		e.Args = append(e.Args, BigToWord(arg))
+	}
+}
+
+// AddWords appends already converted words to the argument list.
+func (e *InsEvent) AddWords(args ...Word) {
+	e.Args = append(e.Args, args...)
+}
+
+// SetResult records the value pushed by the instruction.
+func (e *InsEvent) SetResult(res *big.Int) {
+	e.Result, e.HasResult = BigToWord(res), true
+}
+
+// Arg returns the i'th argument, or a zero word if there is none.
+func (e *InsEvent) Arg(i int) Word {
+	if i < 0 || i >= len(e.Args) {
+		return Word{}
+	}
+	return e.Args[i]
+}
+
+// Compat renders the event into the legacy string collector. Words are
+// printed in decimal except for the arguments the legacy collector printed
+// as hashes or addresses (storage slots, log topics and call targets) and the
+// value shifted by SAR, which it printed signed.
+func (e *InsEvent) Compat() *InsCollector {
+	ic := NewCollector()
+	ic.OpName = e.OpName
+	ic.Pc = e.Pc
+	ic.PcNext = strconv.FormatUint(e.PcNext, 10)
+	ic.CallLayer = e.CallLayer
+
+	ic.AccountValue.FromAddr = addressString(e.From)
+	ic.AccountValue.ToAddr = addressString(e.To)
+	ic.AccountValue.CallContract = addressString(e.CallContract)
+	if e.From != (common.Address{}) {
+		ic.AccountValue.Value = e.Value.String()
+	}
+
+	for i, arg := range e.Args {
+		ic.OpInOut.OpArgs = append(ic.OpInOut.OpArgs, renderArg(e.OpName, i, arg))
+	}
+	if e.HasResult {
+		ic.OpInOut.OpResult = e.Result.String()
+	}
+	ic.OpInOut.RetArgs = e.RetArgs
+	ic.OpInOut.InputData = e.InputData
+	ic.OpInOut.ByteCode = e.ByteCode
+	ic.OpInOut.MemoryData = e.MemoryData
+
+	if e.OpName == "SSTORE" {
+		ic.StoreValue.PreValue = e.PreValue.Big().String()
+		ic.StoreValue.CurrentValue = e.CurrentValue.Big().String()
+	}
+	if e.AllocatedGas != 0 {
+		ic.Gas.AllocatedGas = strconv.FormatUint(e.AllocatedGas, 10)
+	}
+	ic.Gas.RealGasUsed = e.RealGasUsed
+
+	ic.CheckErr.InternalErr = e.InternalErr
+	ic.CheckErr.IsInternalSucceeded = e.IsInternalSucceeded
+	ic.CheckErr.IsCallValid = e.IsCallValid
+	return ic
+}
+
+// SendInsEvent wraps the instruction event into an envelope for dispatch.
+func (e *InsEvent) SendInsEvent() *Event {
+	return &Event{Option: e.OpName, Ins: e}
+}
+
+// addressString renders an address the way the legacy collector did, leaving
+// unset addresses empty.
+func addressString(addr common.Address) string {
+	if addr == (common.Address{}) {
+		return ""
+	}
+	return addr.String()
+}
+
+// renderArg formats the i'th argument of op the way the legacy collector did.
+func renderArg(op string, i int, arg Word) string {
+	switch op {
+	case "SSTORE":
+		if i == 0 {
+			return arg.Hex()
+		}
+	case "LOG1", "LOG2", "LOG3", "LOG4":
+		if i >= 2 {
+			return arg.Hex()
+		}
+	case "EXTCODECOPY":
+		if i == 0 {
+			return arg.Address().String()
+		}
+	case "DELEGATECALLSTART", "STATICCALLSTART":
+		if i == 1 {
+			return arg.Address().String()
+		}
+	case "SAR":
+		if i == 1 {
+			return math.S256(arg.Big()).String()
+		}
+	}
+	return arg.String()
+}
+
+// Event is the envelope handed to plugins. Option names the event and at most
+// one of the payload fields is set, as reported by Kind; flag events such as
+// TXSTART carry none. The encodings of an event are defined in codec.go.
+type Event struct {
+	Option        string            `json:"option"`
+	Ins           *InsEvent         `json:"ins,omitempty"`
+	TxStart       *TxStartEvent     `json:"txstart,omitempty"`
+	TxEnd         *TxEndEvent       `json:"txend,omitempty"`
+	Message       *MessageEvent     `json:"message,omitempty"`
+	Block         *BlockEvent       `json:"block,omitempty"`
+	CallTree      *CallFrame        `json:"calltree,omitempty"`
+	StateDiff     *StateDiff        `json:"statediff,omitempty"`
+	Transfer      *BalanceTransfer  `json:"transfer,omitempty"`
+	TokenTransfer *TokenTransfer    `json:"tokentransfer,omitempty"`
+	Precompile    *PrecompileCall   `json:"precompile,omitempty"`
+	Creation      *ContractCreation `json:"creation,omitempty"`
+	Branch        *Branch           `json:"branch,omitempty"`
+	BasicBlock    *BasicBlock       `json:"basicblock,omitempty"`
+	Memory        *MemoryAccess     `json:"memory,omitempty"`
+
+	compat *AllCollector // legacy view, rendered on first use
+}
+
+// FlagEvent returns a payload-less event.
+func FlagEvent(op string) *Event {
+	return &Event{Option: op}
+}
+
+// Kind returns the kind of payload carried by the event.
+func (ev *Event) Kind() Kind {
+	switch {
+	case ev.Ins != nil:
+		return KindIns
+	case ev.TxStart != nil:
+		return KindTxStart
+	case ev.TxEnd != nil:
+		return KindTxEnd
+	case ev.Message != nil:
+		return KindMessage
+	case ev.Block != nil:
+		return KindBlock
+	case ev.CallTree != nil:
+		return KindCallTree
+	case ev.StateDiff != nil:
+		return KindStateDiff
+	case ev.Transfer != nil:
+		return KindTransfer
+	case ev.TokenTransfer != nil:
+		return KindTokenTransfer
+	case ev.Precompile != nil:
+		return KindPrecompile
+	case ev.Creation != nil:
+		return KindCreation
+	case ev.Branch != nil:
+		return KindBranch
+	case ev.BasicBlock != nil:
+		return KindBasicBlock
+	case ev.Memory != nil:
+		return KindMemory
+	}
+	return KindFlag
+}
+
+// Compat returns the legacy AllCollector view of the event. It is rendered on
+// the first call and shared by every later caller, so plugins must treat it as
+// read-only just like before.
+func (ev *Event) Compat() *AllCollector {
+	if ev.compat != nil {
+		return ev.compat
+	}
+	switch ev.Kind() {
+	case KindIns:
+		ev.compat = ev.Ins.Compat().SendInsInfo()
+		ev.compat.Option = ev.Option
+	case KindTxStart:
+		ev.compat = ev.TxStart.Compat().SendTransInfo(ev.Option)
+	case KindTxEnd:
+		ev.compat = ev.TxEnd.Compat().SendTransInfo(ev.Option)
+	case KindMessage:
+		ev.compat = ev.Message.Compat().SendTransInfo(ev.Option)
+	case KindBlock:
+		ev.compat = ev.Block.Compat().SendBlockInfo(ev.Option)
+	default:
+		ev.compat = SendFlag(ev.Option)
+	}
+	return ev.compat
+}
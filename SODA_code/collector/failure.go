@@ -0,0 +1,59 @@
+package collector
+
+import "bytes"
+
+// Failure causes of a call, creation or transaction. Success is reported as
+// an empty cause.
+const (
+	FailureOutOfGas            = "OUT_OF_GAS"
+	FailureInvalidOpcode       = "INVALID_OPCODE"
+	FailureInvalidJump         = "INVALID_JUMP"
+	FailureStack               = "STACK" // stack underflow or overflow
+	FailureWriteProtection     = "WRITE_PROTECTION"
+	FailureDepth               = "DEPTH"
+	FailureInsufficientBalance = "INSUFFICIENT_BALANCE"
+	FailureRevert              = "REVERT"
+	FailureOther               = "OTHER"
+)
+
+// errorSelector is the selector of Error(string), the revert data of
+// require and revert with a message.
+var errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// RevertReason decodes the message of revert data encoded as Error(string).
+// It reports false if the data isn't one.
+func RevertReason(data []byte) (string, bool) {
+	if len(data) < 4+64 || !bytes.Equal(data[:4], errorSelector) {
+		return "", false
+	}
+	data = data[4:]
+	offset, ok := wordLength(data[:32])
+	if !ok || offset > uint64(len(data))-32 {
+		return "", false
+	}
+	size, ok := wordLength(data[offset : offset+32])
+	if !ok || size > uint64(len(data))-offset-32 {
+		return "", false
+	}
+	return string(data[offset+32 : offset+32+size]), true
+}
+
+// wordLength reads an ABI offset or length, reporting false if it doesn't
+// fit into 32 bits.
+func wordLength(word []byte) (uint64, bool) {
+	for _, b := range word[:28] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return uint64(word[28])<<24 | uint64(word[29])<<16 | uint64(word[30])<<8 | uint64(word[31]), true
+}
+
+// failureReason returns the revert reason of a failure, if any.
+func failureReason(failure string, output []byte) string {
+	if failure != FailureRevert {
+		return ""
+	}
+	reason, _ := RevertReason(output)
+	return reason
+}
@@ -0,0 +1,38 @@
+package collector
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// errorData encodes reason as Error(string) revert data.
+func errorData(reason string) []byte {
+	data := append([]byte{0x08, 0xc3, 0x79, 0xa0}, common.BigToHash(big.NewInt(32)).Bytes()...)
+	data = append(data, common.BigToHash(big.NewInt(int64(len(reason)))).Bytes()...)
+	padded := make([]byte, (len(reason)+31)/32*32)
+	copy(padded, reason)
+	return append(data, padded...)
+}
+
+func TestRevertReason(t *testing.T) {
+	tests := []struct {
+		data   []byte
+		reason string
+		ok     bool
+	}{
+		{errorData("not owner"), "not owner", true},
+		{errorData(""), "", true},
+		{nil, "", false},
+		{errorData("not owner")[:4+64], "", false}, // truncated message
+		{append([]byte{0x4e, 0x48, 0x7b, 0x71}, errorData("x")[4:]...), "", false}, // other selector
+		{append(errorData("x")[:4], bytes.Repeat([]byte{0xff}, 64)...), "", false}, // huge offset
+	}
+	for i, tt := range tests {
+		if reason, ok := RevertReason(tt.data); reason != tt.reason || ok != tt.ok {
+			t.Errorf("test %d: have %q %v, want %q %v", i, reason, ok, tt.reason, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package collector
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Host gives a handler read-only access to the chain the event happened on.
+// State reflects the transaction as executed so far; values returned are
+// copies, so a handler can't change the state through them.
+//
+// Only events of a transaction come with a host. Handlers get nil for the
+// events outside of one, like BLOCK_INFO, and for replayed recordings.
+type Host interface {
+	GetBalance(addr common.Address) *big.Int
+	GetCode(addr common.Address) []byte
+	GetCodeHash(addr common.Address) common.Hash // zero for an account that doesn't exist
+	GetState(addr common.Address, key common.Hash) common.Hash
+	GetNonce(addr common.Address) uint64
+	Exist(addr common.Address) bool
+
+	// Headers of the block being processed and its ancestors, nil if the
+	// block is unknown.
+	GetHeader(hash common.Hash, number uint64) *BlockEvent
+	GetHeaderByNumber(number uint64) *BlockEvent
+}
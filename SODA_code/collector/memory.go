@@ -0,0 +1,32 @@
+package collector
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Directions of a MemoryAccess.
+const (
+	MemoryRead  = "READ"
+	MemoryWrite = "WRITE"
+)
+
+// MemoryAccess describes a region of memory an instruction read or wrote:
+// MLOAD, MSTORE, MSTORE8, the *COPY instructions, SHA3, LOG, RETURN and
+// REVERT, the arguments of a call or the init code of a create, and the
+// return buffer a call filled. It is sent as MEMORY, reads before the
+// instruction runs and writes once it ran, both ahead of the instruction
+// event.
+type MemoryAccess struct {
+	Op          string         `json:"op"`        // instruction accessing the memory
+	Direction   string         `json:"direction"` // MemoryRead or MemoryWrite
+	TxHash      common.Hash    `json:"txhash"`
+	CallLayer   uint64         `json:"calllayer"`
+	CodeAddress common.Address `json:"codeaddress"` // account the executing code was loaded from
+	Pc          uint64         `json:"pc"`
+	Offset      uint64         `json:"offset"`
+	Length      uint64         `json:"length"`
+	Data        []byte         `json:"data"` // content of the region, after the write for a write
+}
+
+// SendMemoryEvent wraps the access into an envelope for dispatch.
+func (m *MemoryAccess) SendMemoryEvent() *Event {
+	return &Event{Option: "MEMORY", Memory: m}
+}
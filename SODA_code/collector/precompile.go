@@ -0,0 +1,148 @@
+package collector
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Precompiled contracts.
+const (
+	PrecompileEcrecover      = "ECRECOVER"
+	PrecompileSha256         = "SHA256"
+	PrecompileRipemd160      = "RIPEMD160"
+	PrecompileIdentity       = "IDENTITY"
+	PrecompileModExp         = "MODEXP"
+	PrecompileBn256Add       = "BN256ADD"
+	PrecompileBn256ScalarMul = "BN256SCALARMUL"
+	PrecompileBn256Pairing   = "BN256PAIRING"
+)
+
+// PrecompileCall describes a call to a precompiled contract. It is sent as
+// PRECOMPILE once the precompile returned, before the message reporting the
+// call.
+type PrecompileCall struct {
+	Name      string         `json:"name"` // one of the Precompile* constants
+	Address   common.Address `json:"address"`
+	TxHash    common.Hash    `json:"txhash"`
+	CallLayer uint64         `json:"calllayer"` // frame of the call to the precompile
+	Caller    common.Address `json:"caller"`
+	Input     []byte         `json:"input"`
+	Output    []byte         `json:"output"`
+	Gas       uint64         `json:"gas"`     // gas charged by the precompile
+	Failure   string         `json:"failure"` // cause of a failed call, one of the Failure* constants
+
+	Decoded *DecodedCall `json:"decoded,omitempty" rlp:"nil"` // arguments read from the input
+
+	Signer common.Address `json:"signer"` // address recovered by ECRECOVER, zero if the signature is invalid
+	HighS  bool           `json:"highs"`  // whether the ECRECOVER signature has an s in the upper half of the curve order, so is malleable
+}
+
+// SendPrecompileEvent wraps the precompile call into an envelope for
+// dispatch.
+func (c *PrecompileCall) SendPrecompileEvent() *Event {
+	return &Event{Option: "PRECOMPILE", Precompile: c}
+}
+
+var precompileNames = map[common.Address]string{
+	common.BytesToAddress([]byte{1}): PrecompileEcrecover,
+	common.BytesToAddress([]byte{2}): PrecompileSha256,
+	common.BytesToAddress([]byte{3}): PrecompileRipemd160,
+	common.BytesToAddress([]byte{4}): PrecompileIdentity,
+	common.BytesToAddress([]byte{5}): PrecompileModExp,
+	common.BytesToAddress([]byte{6}): PrecompileBn256Add,
+	common.BytesToAddress([]byte{7}): PrecompileBn256ScalarMul,
+	common.BytesToAddress([]byte{8}): PrecompileBn256Pairing,
+}
+
+// secp256k1HalfN is half the order of the secp256k1 curve. A signature with
+// a larger s has a twin (r, n-s) recovering the same signer.
+var secp256k1HalfN = common.FromHex("0x7fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a0")
+
+// NewPrecompileCall describes a call to the precompile at addr and decodes
+// its input the way the precompile reads it. The name is empty if addr isn't
+// a precompile.
+func NewPrecompileCall(addr common.Address, input, output []byte) *PrecompileCall {
+	c := &PrecompileCall{Name: precompileNames[addr], Address: addr, Input: input, Output: output}
+	word := func(offset uint64) Word {
+		return BytesToWord(precompileInput(input, offset, 32))
+	}
+	switch c.Name {
+	case PrecompileEcrecover:
+		s := word(96)
+		c.Decoded = precompileArgs("ecrecover",
+			"bytes32", "hash", word(0).Hex(),
+			"uint8", "v", word(32).String(),
+			"bytes32", "r", word(64).Hex(),
+			"bytes32", "s", s.Hex())
+		if len(output) == 32 {
+			c.Signer = common.BytesToAddress(output)
+		}
+		c.HighS = bytes.Compare(s[:], secp256k1HalfN) > 0
+	case PrecompileSha256, PrecompileRipemd160, PrecompileIdentity:
+		c.Decoded = precompileArgs(strings.ToLower(c.Name), "bytes", "data", hexutil.Encode(input))
+	case PrecompileModExp:
+		baseLen, expLen, modLen := inputLength(word(0)), inputLength(word(32)), inputLength(word(64))
+		base := precompileInput(input, 96, baseLen)
+		exp := precompileInput(input, 96+baseLen, expLen)
+		mod := precompileInput(input, 96+baseLen+expLen, modLen)
+		c.Decoded = precompileArgs("modexp",
+			"bytes", "base", hexutil.Encode(base),
+			"bytes", "exponent", hexutil.Encode(exp),
+			"bytes", "modulus", hexutil.Encode(mod))
+	case PrecompileBn256Add:
+		c.Decoded = precompileArgs("bn256Add",
+			"uint256", "x1", word(0).String(),
+			"uint256", "y1", word(32).String(),
+			"uint256", "x2", word(64).String(),
+			"uint256", "y2", word(96).String())
+	case PrecompileBn256ScalarMul:
+		c.Decoded = precompileArgs("bn256ScalarMul",
+			"uint256", "x", word(0).String(),
+			"uint256", "y", word(32).String(),
+			"uint256", "scalar", word(64).String())
+	case PrecompileBn256Pairing:
+		c.Decoded = precompileArgs("bn256Pairing", "uint256", "pairs", strconv.Itoa(len(input)/192))
+	}
+	return c
+}
+
+// precompileArgs builds a decoded call from type, name and value triples.
+func precompileArgs(name string, args ...string) *DecodedCall {
+	c := &DecodedCall{Name: name}
+	types := make([]string, 0, len(args)/3)
+	for i := 0; i+2 < len(args); i += 3 {
+		types = append(types, args[i])
+		c.Args = append(c.Args, DecodedArg{Type: args[i], Name: args[i+1], Value: args[i+2]})
+	}
+	c.Signature = name + "(" + strings.Join(types, ",") + ")"
+	return c
+}
+
+// inputLength reads a MODEXP length, capped so that offsets computed from it
+// can't overflow.
+func inputLength(w Word) uint64 {
+	if n := w.Big(); n.IsUint64() && n.Uint64() < 1<<32 {
+		return n.Uint64()
+	}
+	return 1 << 32
+}
+
+// precompileInput returns size bytes of input from offset, zero padded as the
+// precompiles read their input. Operands longer than a word are clipped to
+// the rest of the input, so absurd lengths in a MODEXP header don't allocate.
+func precompileInput(input []byte, offset, size uint64) []byte {
+	var rest []byte
+	if offset < uint64(len(input)) {
+		rest = input[offset:]
+	}
+	if size > 32 && size > uint64(len(rest)) {
+		size = uint64(len(rest))
+	}
+	out := make([]byte, size)
+	copy(out, rest)
+	return out
+}
@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNewPrecompileCall(t *testing.T) {
+	// A signature with s above half the curve order is malleable.
+	input := make([]byte, 128)
+	input[63] = 27
+	copy(input[96:], bytes.Repeat([]byte{0xff}, 32))
+	signer := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	c := NewPrecompileCall(common.BytesToAddress([]byte{1}), input, signer.Hash().Bytes())
+	if c.Name != PrecompileEcrecover || c.Signer != signer || !c.HighS {
+		t.Errorf("unexpected ecrecover call: %+v", c)
+	}
+	if c.Decoded.Signature != "ecrecover(bytes32,uint8,bytes32,bytes32)" || c.Decoded.Args[1].Value != "27" {
+		t.Errorf("unexpected ecrecover input: %v", c.Decoded)
+	}
+	if c := NewPrecompileCall(common.BytesToAddress([]byte{1}), nil, nil); c.Signer != (common.Address{}) || c.HighS {
+		t.Errorf("unexpected empty ecrecover call: %+v", c)
+	}
+
+	// MODEXP lengths pick the operands, short input is zero padded and
+	// absurd lengths don't allocate.
+	input = append(common.BigToHash(big.NewInt(1)).Bytes(), common.BigToHash(big.NewInt(2)).Bytes()...)
+	input = append(input, bytes.Repeat([]byte{0xff}, 32)...)
+	input = append(input, 0x03, 0x05)
+	c = NewPrecompileCall(common.BytesToAddress([]byte{5}), input, nil)
+	want := "modexp(bytes base: 0x03, bytes exponent: 0x0500, bytes modulus: 0x)"
+	if c.Name != PrecompileModExp || c.Decoded.String() != want {
+		t.Errorf("unexpected modexp input: %v", c.Decoded)
+	}
+
+	if c := NewPrecompileCall(common.BytesToAddress([]byte{9}), input, nil); c.Name != "" || c.Decoded != nil {
+		t.Errorf("decoded a call to a regular account: %+v", c)
+	}
+}
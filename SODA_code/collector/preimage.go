@@ -0,0 +1,172 @@
+package collector
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Kinds of a SlotPath.
+const (
+	SlotFixed   = "FIXED"   // state variable at a constant slot
+	SlotMapping = "MAPPING" // mapping entry, keccak256(key . slot) + offset
+	SlotArray   = "ARRAY"   // dynamic array element, keccak256(slot) + index
+	SlotUnknown = "UNKNOWN" // hash-like slot whose preimage wasn't seen
+)
+
+// maxSlotOffset bounds the distance between a slot and the hash it is
+// explained by, so unrelated hashes don't explain every slot above them.
+const maxSlotOffset = 1 << 32
+
+// maxSlotDepth bounds the nesting of mappings and arrays a slot is explained
+// with.
+const maxSlotDepth = 16
+
+// SlotPath explains a storage slot the way Solidity lays out storage, from
+// the keccak256 preimages computed by the transaction.
+type SlotPath struct {
+	Kind  string    `json:"kind"`                     // one of the Slot* constants
+	Slot  Word      `json:"slot"`                     // the slot explained
+	Base  *SlotPath `json:"base,omitempty" rlp:"nil"` // slot of the mapping or array, nil for a fixed slot
+	Key   []byte    `json:"key"`                      // mapping key as it was hashed
+	Index uint64    `json:"index"`                    // slot of a mapping entry or array element relative to its hash
+}
+
+// String renders the path in Solidity notation, e.g. slot 2[0x..a11ce]+1
+// for the second slot of the mapping entry of 0x..a11ce in slot 2.
+func (p *SlotPath) String() string {
+	switch p.Kind {
+	case SlotFixed:
+		return "slot " + p.Slot.String()
+	case SlotMapping:
+		s := p.Base.String() + "[" + hexutil.Encode(p.Key) + "]"
+		if p.Index > 0 {
+			s += "+" + new(big.Int).SetUint64(p.Index).String()
+		}
+		return s
+	case SlotArray:
+		return p.Base.String() + "[" + new(big.Int).SetUint64(p.Index).String() + "]"
+	}
+	return "slot " + p.Slot.Hex()
+}
+
+// Root returns the fixed slot, or unknown hash, the path starts from.
+func (p *SlotPath) Root() *SlotPath {
+	for p.Base != nil {
+		p = p.Base
+	}
+	return p
+}
+
+// Preimages records the inputs of the keccak256 hashes computed by SHA3 in
+// a transaction. Only inputs of at least 32 bytes can explain a slot, and
+// their hashes are kept sorted so a slot is explained in logarithmic time.
+type Preimages struct {
+	preimages map[common.Hash][]byte
+	sorted    []common.Hash // ascending
+	recent    []common.Hash // added since the last merge into sorted
+}
+
+// maxPreimages bounds the preimages recorded per transaction, as a
+// transaction can hash as often as its gas allows.
+const maxPreimages = 1 << 16
+
+// maxRecentPreimages is how many hashes are searched linearly before they
+// are merged into the sorted ones.
+const maxRecentPreimages = 64
+
+// Add records the input of a hash.
+func (p *Preimages) Add(hash common.Hash, data []byte) {
+	if len(data) < 32 {
+		return
+	}
+	if p.preimages == nil {
+		p.preimages = make(map[common.Hash][]byte)
+	}
+	if _, ok := p.preimages[hash]; ok || len(p.preimages) >= maxPreimages {
+		return
+	}
+	p.preimages[hash] = data
+	if p.recent = append(p.recent, hash); len(p.recent) >= maxRecentPreimages {
+		p.merge()
+	}
+}
+
+// merge moves the recent hashes into the sorted ones.
+func (p *Preimages) merge() {
+	sort.Slice(p.recent, func(i, j int) bool { return bytes.Compare(p.recent[i][:], p.recent[j][:]) < 0 })
+	merged := make([]common.Hash, 0, len(p.sorted)+len(p.recent))
+	i, j := 0, 0
+	for i < len(p.sorted) && j < len(p.recent) {
+		if bytes.Compare(p.sorted[i][:], p.recent[j][:]) < 0 {
+			merged, i = append(merged, p.sorted[i]), i+1
+		} else {
+			merged, j = append(merged, p.recent[j]), j+1
+		}
+	}
+	merged = append(merged, p.sorted[i:]...)
+	p.sorted, p.recent = append(merged, p.recent[j:]...), p.recent[:0]
+}
+
+// Reset forgets the preimages for a new transaction.
+func (p *Preimages) Reset() {
+	*p = Preimages{}
+}
+
+// Explain derives the slot from the recorded preimages. A slot at most
+// maxSlotOffset above a hash of 32 bytes is an array element, above a hash
+// of a key and a slot it is a mapping entry. Small slots nothing explains are
+// fixed, others unknown.
+func (p *Preimages) Explain(slot common.Hash) *SlotPath {
+	return p.explain(Word(slot), 0)
+}
+
+func (p *Preimages) explain(slot Word, depth int) *SlotPath {
+	path := &SlotPath{Kind: SlotFixed, Slot: slot}
+	hash, index, ok := p.nearest(slot)
+	if !ok || depth == maxSlotDepth {
+		if slot.Big().BitLen() > 64 {
+			path.Kind = SlotUnknown
+		}
+		return path
+	}
+	preimage := p.preimages[hash]
+	base := BytesToWord(preimage[len(preimage)-32:])
+	if len(preimage) == 32 {
+		path.Kind = SlotArray
+	} else {
+		path.Kind = SlotMapping
+		path.Key = common.CopyBytes(preimage[:len(preimage)-32])
+	}
+	path.Index = index
+	path.Base = p.explain(base, depth+1)
+	return path
+}
+
+// nearest returns the hash closest below slot, and the distance to it.
+func (p *Preimages) nearest(slot Word) (common.Hash, uint64, bool) {
+	var (
+		best  common.Hash
+		found bool
+	)
+	// The predecessor among the sorted hashes, then the recent ones.
+	if i := sort.Search(len(p.sorted), func(i int) bool { return bytes.Compare(p.sorted[i][:], slot[:]) > 0 }); i > 0 {
+		best, found = p.sorted[i-1], true
+	}
+	for _, hash := range p.recent {
+		if bytes.Compare(hash[:], slot[:]) <= 0 && (!found || bytes.Compare(hash[:], best[:]) > 0) {
+			best, found = hash, true
+		}
+	}
+	if !found {
+		return common.Hash{}, 0, false
+	}
+	d := new(big.Int).Sub(slot.Big(), best.Big())
+	if d.Cmp(big.NewInt(maxSlotOffset)) >= 0 {
+		return common.Hash{}, 0, false
+	}
+	return best, d.Uint64(), true
+}
@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestExplainSlot(t *testing.T) {
+	var (
+		p     Preimages
+		alice = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+		key   = alice.Hash().Hex()
+	)
+	hash := func(data ...[]byte) common.Hash {
+		preimage := bytes.Join(data, nil)
+		h := crypto.Keccak256Hash(preimage)
+		p.Add(h, preimage)
+		return h
+	}
+	plus := func(h common.Hash, n int64) common.Hash {
+		return common.BigToHash(new(big.Int).Add(h.Big(), big.NewInt(n)))
+	}
+	balances := hash(alice.Hash().Bytes(), common.BigToHash(big.NewInt(1)).Bytes())
+	allowances := hash(alice.Hash().Bytes(), hash(alice.Hash().Bytes(), common.BigToHash(big.NewInt(2)).Bytes()).Bytes())
+	holders := hash(common.BigToHash(big.NewInt(3)).Bytes())
+	p.Add(common.HexToHash("0x01"), []byte{1}) // hashes of short input explain nothing
+
+	tests := []struct {
+		slot common.Hash
+		want string
+	}{
+		{common.BigToHash(big.NewInt(5)), "slot 5"},
+		{balances, "slot 1[" + key + "]"},
+		{plus(balances, 2), "slot 1[" + key + "]+2"},
+		{allowances, "slot 2[" + key + "][" + key + "]"},
+		{plus(holders, 7), "slot 3[7]"},
+		{crypto.Keccak256Hash([]byte("unseen")), "slot " + crypto.Keccak256Hash([]byte("unseen")).Hex()},
+	}
+	for _, test := range tests {
+		if have := p.Explain(test.slot).String(); have != test.want {
+			t.Errorf("slot %x: have %s, want %s", test.slot, have, test.want)
+		}
+	}
+	if path := p.Explain(allowances); path.Kind != SlotMapping || path.Root().Slot != Uint64ToWord(2) || path.Base.Kind != SlotMapping {
+		t.Errorf("unexpected nested mapping path: %+v", path)
+	}
+	if path := p.Explain(plus(holders, 7)); path.Kind != SlotArray || path.Index != 7 || path.Key != nil {
+		t.Errorf("unexpected array path: %+v", path)
+	}
+
+	p.Reset()
+	if path := p.Explain(balances); path.Kind != SlotUnknown {
+		t.Errorf("explained a slot without preimages: %+v", path)
+	}
+}
+
+func TestPreimagesSorted(t *testing.T) {
+	var (
+		p      Preimages
+		hashes []common.Hash
+	)
+	for i := 0; i < 1000; i++ {
+		preimage := common.BigToHash(big.NewInt(int64(i))).Bytes()
+		hash := crypto.Keccak256Hash(preimage)
+		p.Add(hash, preimage)
+		hashes = append(hashes, hash)
+	}
+	// Every slot above a hash is explained by it, whether merged or not.
+	for i, hash := range hashes {
+		slot := common.BigToHash(new(big.Int).Add(hash.Big(), big.NewInt(int64(i))))
+		if path := p.Explain(slot); path.Kind != SlotArray || path.Index != uint64(i) || path.Base.Slot != Uint64ToWord(uint64(i)) {
+			t.Fatalf("slot %d above hash %x: unexpected path %+v", i, hash, path)
+		}
+	}
+	if len(p.sorted)+len(p.recent) != len(hashes) || len(p.recent) >= maxRecentPreimages {
+		t.Errorf("have %d sorted and %d recent hashes, want %d", len(p.sorted), len(p.recent), len(hashes))
+	}
+}
+
+func BenchmarkExplain(b *testing.B) {
+	var p Preimages
+	for i := 0; i < maxPreimages; i++ {
+		preimage := common.BigToHash(big.NewInt(int64(i))).Bytes()
+		p.Add(crypto.Keccak256Hash(preimage), preimage)
+	}
+	slot := crypto.Keccak256Hash([]byte("unseen"))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Explain(slot)
+	}
+}
@@ -0,0 +1,1204 @@
+package collector
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// This file implements the protobuf encoding of events described by
+// events.proto. The wire format is written by hand to keep the collector free
+// of generated code: fields are written in field number order, scalars equal
+// to their zero value are omitted as in proto3, and payload messages are
+// always written so the kind of an empty payload survives a round trip.
+// TestEventProtoSchema in go-ethereum/cmd/pluginManage decodes the output
+// with golang/protobuf against events.proto; run it with -update-events-pb
+// after changing the schema.
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errProtoTruncated = errors.New("collector: truncated protobuf message")
+
+// protoWriter appends protobuf fields to a buffer.
+type protoWriter []byte
+
+func (w *protoWriter) uvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	*w = append(*w, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func (w *protoWriter) key(field int, wire uint64) {
+	w.uvarint(uint64(field)<<3 | wire)
+}
+
+// uint writes a uint64 field, omitted if zero.
+func (w *protoWriter) uint(field int, v uint64) {
+	if v != 0 {
+		w.key(field, wireVarint)
+		w.uvarint(v)
+	}
+}
+
+// int writes an int64 field, omitted if zero.
+func (w *protoWriter) int(field int, v int64) {
+	w.uint(field, uint64(v))
+}
+
+// bool writes a bool field, omitted if false.
+func (w *protoWriter) bool(field int, v bool) {
+	if v {
+		w.uint(field, 1)
+	}
+}
+
+// raw writes a length-delimited field, even if empty.
+func (w *protoWriter) raw(field int, b []byte) {
+	w.key(field, wireBytes)
+	w.uvarint(uint64(len(b)))
+	*w = append(*w, b...)
+}
+
+// bytes writes a bytes field, omitted if empty.
+func (w *protoWriter) bytes(field int, b []byte) {
+	if len(b) > 0 {
+		w.raw(field, b)
+	}
+}
+
+// string writes a string field, omitted if empty.
+func (w *protoWriter) string(field int, s string) {
+	w.bytes(field, []byte(s))
+}
+
+// fixed writes a fixed size value such as a hash or a word as a bytes field,
+// omitted if all its bytes are zero.
+func (w *protoWriter) fixed(field int, b []byte) {
+	for _, c := range b {
+		if c != 0 {
+			w.raw(field, b)
+			return
+		}
+	}
+}
+
+// protoField is a single field read from a protobuf message.
+type protoField struct {
+	num  int
+	wire uint64
+	v    uint64 // value of a varint field
+	b    []byte // content of a length-delimited field
+}
+
+func (f *protoField) check(wire uint64) error {
+	if f.wire != wire {
+		return fmt.Errorf("collector: protobuf field %d has wire type %d, want %d", f.num, f.wire, wire)
+	}
+	return nil
+}
+
+func (f *protoField) uint() (uint64, error) {
+	return f.v, f.check(wireVarint)
+}
+
+func (f *protoField) int() (int64, error) {
+	return int64(f.v), f.check(wireVarint)
+}
+
+func (f *protoField) bool() (bool, error) {
+	return f.v != 0, f.check(wireVarint)
+}
+
+func (f *protoField) bytes() ([]byte, error) {
+	return common.CopyBytes(f.b), f.check(wireBytes)
+}
+
+func (f *protoField) string() (string, error) {
+	return string(f.b), f.check(wireBytes)
+}
+
+// fixed copies the field into dst, which it must fill exactly.
+func (f *protoField) fixed(dst []byte) error {
+	if err := f.check(wireBytes); err != nil {
+		return err
+	}
+	if len(f.b) != len(dst) {
+		return fmt.Errorf("collector: protobuf field %d has %d bytes, want %d", f.num, len(f.b), len(dst))
+	}
+	copy(dst, f.b)
+	return nil
+}
+
+// message decodes the field as an embedded message into m.
+func (f *protoField) message(m protoMessage) error {
+	if err := f.check(wireBytes); err != nil {
+		return err
+	}
+	return readProto(f.b, m.unmarshalProto)
+}
+
+// readProto calls fn for every field of a protobuf message. Fixed size fields,
+// which the schema does not use, are skipped.
+func readProto(data []byte, fn func(f *protoField) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		data = data[n:]
+		f := &protoField{num: int(key >> 3), wire: key & 7}
+		switch f.wire {
+		case wireVarint:
+			if f.v, n = binary.Uvarint(data); n <= 0 {
+				return errProtoTruncated
+			}
+			data = data[n:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return errProtoTruncated
+			}
+			f.b, data = data[n:n+int(size)], data[n+int(size):]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if f.wire == wireFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return errProtoTruncated
+			}
+			data = data[size:]
+			continue
+		default:
+			return fmt.Errorf("collector: unsupported protobuf wire type %d", f.wire)
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protoMessage is implemented by the payloads of an event.
+type protoMessage interface {
+	marshalProto(w *protoWriter)
+	unmarshalProto(f *protoField) error
+}
+
+// MarshalProto returns the protobuf encoding of the event.
+func (ev *Event) MarshalProto() ([]byte, error) {
+	var w protoWriter
+	w.uint(1, SchemaVersion)
+	w.string(2, ev.Option)
+	if payload, ok := ev.payload().(protoMessage); ok {
+		var pw protoWriter
+		payload.marshalProto(&pw)
+		w.raw(2+int(ev.Kind()), pw)
+	}
+	return w, nil
+}
+
+// UnmarshalProto decodes the protobuf encoding of an event.
+func (ev *Event) UnmarshalProto(data []byte) error {
+	var version uint64
+	*ev = Event{}
+	err := readProto(data, func(f *protoField) (err error) {
+		switch {
+		case f.num == 1:
+			version, err = f.uint()
+		case f.num == 2:
+			ev.Option, err = f.string()
+		case f.num > 2 && f.num < 2+int(numKinds):
+			if err = f.check(wireBytes); err != nil {
+				return err
+			}
+			var payload interface{}
+			if payload, err = ev.setPayload(Kind(f.num - 2)); err != nil {
+				return err
+			}
+			err = readProto(f.b, payload.(protoMessage).unmarshalProto)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if version != SchemaVersion {
+		return versionError(version)
+	}
+	return nil
+}
+
+func (e *InsEvent) marshalProto(w *protoWriter) {
+	w.string(1, e.OpName)
+	w.uint(2, e.Pc)
+	w.uint(3, e.PcNext)
+	w.int(4, int64(e.CallLayer))
+	w.fixed(5, e.From[:])
+	w.fixed(6, e.To[:])
+	w.fixed(7, e.CallContract[:])
+	w.fixed(8, e.Value[:])
+	for _, arg := range e.Args {
+		w.raw(9, arg[:])
+	}
+	w.fixed(10, e.Result[:])
+	w.bool(11, e.HasResult)
+	w.bytes(12, e.RetArgs)
+	w.bytes(13, e.InputData)
+	w.bytes(14, e.ByteCode)
+	w.bytes(15, e.MemoryData)
+	w.fixed(16, e.PreValue[:])
+	w.fixed(17, e.CurrentValue[:])
+	w.uint(18, e.AllocatedGas)
+	w.uint(19, e.RealGasUsed)
+	w.string(20, e.InternalErr)
+	w.bool(21, e.IsInternalSucceeded)
+	w.bool(22, e.IsCallValid)
+	if len(e.ArgTaint) > 0 {
+		var pw protoWriter
+		for _, t := range e.ArgTaint {
+			pw.uvarint(uint64(t))
+		}
+		w.raw(23, pw)
+	}
+	decodedProto(w, 24, e.Decoded)
+	w.string(25, e.Failure)
+	w.string(26, e.RevertReason)
+	w.string(27, e.FrameType)
+	w.fixed(28, e.CodeAddress[:])
+	w.fixed(29, e.StorageAddress[:])
+	w.fixed(30, e.Sender[:])
+	w.fixed(31, e.CallValue[:])
+	if e.Slot != nil {
+		var sw protoWriter
+		e.Slot.marshalProto(&sw)
+		w.raw(32, sw)
+	}
+	w.uint(33, e.GasBefore)
+	w.uint(34, e.StaticGas)
+	w.uint(35, e.DynamicGas)
+	w.uint(36, e.GasForwarded)
+	w.uint(37, e.GasReturned)
+	w.uint(38, e.RefundBefore)
+	w.uint(39, e.RefundAfter)
+}
+
+func (e *InsEvent) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		e.OpName, err = f.string()
+	case 2:
+		e.Pc, err = f.uint()
+	case 3:
+		e.PcNext, err = f.uint()
+	case 4:
+		var layer int64
+		layer, err = f.int()
+		e.CallLayer = int(layer)
+	case 5:
+		err = f.fixed(e.From[:])
+	case 6:
+		err = f.fixed(e.To[:])
+	case 7:
+		err = f.fixed(e.CallContract[:])
+	case 8:
+		err = f.fixed(e.Value[:])
+	case 9:
+		var arg Word
+		err = f.fixed(arg[:])
+		e.Args = append(e.Args, arg)
+	case 10:
+		err = f.fixed(e.Result[:])
+	case 11:
+		e.HasResult, err = f.bool()
+	case 12:
+		e.RetArgs, err = f.bytes()
+	case 13:
+		e.InputData, err = f.bytes()
+	case 14:
+		e.ByteCode, err = f.bytes()
+	case 15:
+		e.MemoryData, err = f.bytes()
+	case 16:
+		err = f.fixed(e.PreValue[:])
+	case 17:
+		err = f.fixed(e.CurrentValue[:])
+	case 18:
+		e.AllocatedGas, err = f.uint()
+	case 19:
+		e.RealGasUsed, err = f.uint()
+	case 20:
+		e.InternalErr, err = f.string()
+	case 21:
+		e.IsInternalSucceeded, err = f.bool()
+	case 22:
+		e.IsCallValid, err = f.bool()
+	case 23:
+		// Packed repeated field.
+		if err = f.check(wireBytes); err != nil {
+			return err
+		}
+		for data := f.b; len(data) > 0; {
+			t, n := binary.Uvarint(data)
+			if n <= 0 {
+				return errProtoTruncated
+			}
+			e.ArgTaint, data = append(e.ArgTaint, Taint(t)), data[n:]
+		}
+	case 24:
+		e.Decoded, err = decodedField(f)
+	case 25:
+		e.Failure, err = f.string()
+	case 26:
+		e.RevertReason, err = f.string()
+	case 27:
+		e.FrameType, err = f.string()
+	case 28:
+		err = f.fixed(e.CodeAddress[:])
+	case 29:
+		err = f.fixed(e.StorageAddress[:])
+	case 30:
+		err = f.fixed(e.Sender[:])
+	case 31:
+		err = f.fixed(e.CallValue[:])
+	case 32:
+		e.Slot = new(SlotPath)
+		err = f.message(e.Slot)
+	case 33:
+		e.GasBefore, err = f.uint()
+	case 34:
+		e.StaticGas, err = f.uint()
+	case 35:
+		e.DynamicGas, err = f.uint()
+	case 36:
+		e.GasForwarded, err = f.uint()
+	case 37:
+		e.GasReturned, err = f.uint()
+	case 38:
+		e.RefundBefore, err = f.uint()
+	case 39:
+		e.RefundAfter, err = f.uint()
+	}
+	return err
+}
+
+func (e *TxStartEvent) marshalProto(w *protoWriter) {
+	w.fixed(1, e.TxHash[:])
+	w.uint(2, e.BlockNumber)
+	w.uint(3, e.BlockTime)
+	w.fixed(4, e.From[:])
+	w.fixed(5, e.To[:])
+	w.bool(6, e.Create)
+	w.fixed(7, e.Value[:])
+	w.fixed(8, e.GasPrice[:])
+	w.uint(9, e.GasLimit)
+	w.uint(10, e.Nonce)
+	w.bytes(11, e.Input)
+	w.bytes(12, e.Code)
+	decodedProto(w, 13, e.Decoded)
+}
+
+func (e *TxStartEvent) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		err = f.fixed(e.TxHash[:])
+	case 2:
+		e.BlockNumber, err = f.uint()
+	case 3:
+		e.BlockTime, err = f.uint()
+	case 4:
+		err = f.fixed(e.From[:])
+	case 5:
+		err = f.fixed(e.To[:])
+	case 6:
+		e.Create, err = f.bool()
+	case 7:
+		err = f.fixed(e.Value[:])
+	case 8:
+		err = f.fixed(e.GasPrice[:])
+	case 9:
+		e.GasLimit, err = f.uint()
+	case 10:
+		e.Nonce, err = f.uint()
+	case 11:
+		e.Input, err = f.bytes()
+	case 12:
+		e.Code, err = f.bytes()
+	case 13:
+		e.Decoded, err = decodedField(f)
+	}
+	return err
+}
+
+func (e *TxEndEvent) marshalProto(w *protoWriter) {
+	w.fixed(1, e.TxHash[:])
+	w.uint(2, e.GasUsed)
+	w.bool(3, e.Success)
+	w.bool(4, e.Create)
+	w.fixed(5, e.Contract[:])
+	w.bytes(6, e.DeployCode)
+	w.bytes(7, e.RuntimeCode)
+	w.string(8, e.Failure)
+	w.string(9, e.RevertReason)
+	w.fixed(10, e.From[:])
+	w.fixed(11, e.To[:])
+	w.fixed(12, e.Value[:])
+	w.bytes(13, e.Input)
+	w.uint(14, e.BlockNumber)
+	w.uint(15, e.TxIndex)
+	w.uint(16, e.Status)
+	w.bytes(17, e.PostState)
+	w.uint(18, e.CumulativeGasUsed)
+	w.bytes(19, e.Bloom)
+	for _, log := range e.Logs {
+		var lw protoWriter
+		log.marshalProto(&lw)
+		w.raw(20, lw)
+	}
+}
+
+func (e *TxEndEvent) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		err = f.fixed(e.TxHash[:])
+	case 2:
+		e.GasUsed, err = f.uint()
+	case 3:
+		e.Success, err = f.bool()
+	case 4:
+		e.Create, err = f.bool()
+	case 5:
+		err = f.fixed(e.Contract[:])
+	case 6:
+		e.DeployCode, err = f.bytes()
+	case 7:
+		e.RuntimeCode, err = f.bytes()
+	case 8:
+		e.Failure, err = f.string()
+	case 9:
+		e.RevertReason, err = f.string()
+	case 10:
+		err = f.fixed(e.From[:])
+	case 11:
+		err = f.fixed(e.To[:])
+	case 12:
+		err = f.fixed(e.Value[:])
+	case 13:
+		e.Input, err = f.bytes()
+	case 14:
+		e.BlockNumber, err = f.uint()
+	case 15:
+		e.TxIndex, err = f.uint()
+	case 16:
+		e.Status, err = f.uint()
+	case 17:
+		e.PostState, err = f.bytes()
+	case 18:
+		e.CumulativeGasUsed, err = f.uint()
+	case 19:
+		e.Bloom, err = f.bytes()
+	case 20:
+		log := new(Log)
+		if err = f.message(log); err == nil {
+			e.Logs = append(e.Logs, log)
+		}
+	}
+	return err
+}
+
+func (l *Log) marshalProto(w *protoWriter) {
+	w.fixed(1, l.Address[:])
+	for _, topic := range l.Topics {
+		w.raw(2, topic[:])
+	}
+	w.bytes(3, l.Data)
+	w.uint(4, l.Index)
+	decodedProto(w, 5, l.Decoded)
+}
+
+func (l *Log) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		err = f.fixed(l.Address[:])
+	case 2:
+		var topic common.Hash
+		err = f.fixed(topic[:])
+		l.Topics = append(l.Topics, topic)
+	case 3:
+		l.Data, err = f.bytes()
+	case 4:
+		l.Index, err = f.uint()
+	case 5:
+		l.Decoded, err = decodedField(f)
+	}
+	return err
+}
+
+func (e *MessageEvent) marshalProto(w *protoWriter) {
+	w.string(1, e.Type)
+	w.uint(2, e.Pc)
+	w.uint(3, e.CallLayer)
+	w.fixed(4, e.From[:])
+	w.fixed(5, e.To[:])
+	w.fixed(6, e.Value[:])
+	w.bytes(7, e.Input)
+	w.bytes(8, e.Code)
+	w.bool(9, e.Success)
+	decodedProto(w, 10, e.Decoded)
+	w.string(11, e.Failure)
+	w.string(12, e.RevertReason)
+	w.fixed(13, e.Salt[:])
+}
+
+func (e *MessageEvent) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		e.Type, err = f.string()
+	case 2:
+		e.Pc, err = f.uint()
+	case 3:
+		e.CallLayer, err = f.uint()
+	case 4:
+		err = f.fixed(e.From[:])
+	case 5:
+		err = f.fixed(e.To[:])
+	case 6:
+		err = f.fixed(e.Value[:])
+	case 7:
+		e.Input, err = f.bytes()
+	case 8:
+		e.Code, err = f.bytes()
+	case 9:
+		e.Success, err = f.bool()
+	case 10:
+		e.Decoded, err = decodedField(f)
+	case 11:
+		e.Failure, err = f.string()
+	case 12:
+		e.RevertReason, err = f.string()
+	case 13:
+		err = f.fixed(e.Salt[:])
+	}
+	return err
+}
+
+func (e *BlockEvent) marshalProto(w *protoWriter) {
+	w.uint(1, e.Number)
+	w.fixed(2, e.ParentHash[:])
+	w.fixed(3, e.UncleHash[:])
+	w.fixed(4, e.Coinbase[:])
+	w.fixed(5, e.StateRoot[:])
+	w.fixed(6, e.TxHashRoot[:])
+	w.fixed(7, e.ReceiptHash[:])
+	w.bytes(8, e.Bloom)
+	w.fixed(9, e.Difficulty[:])
+	w.uint(10, e.GasLimit)
+	w.uint(11, e.GasUsed)
+	w.uint(12, e.Time)
+	w.bytes(13, e.Extra)
+	w.fixed(14, e.MixDigest[:])
+	w.uint(15, e.Nonce)
+}
+
+func (e *BlockEvent) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		e.Number, err = f.uint()
+	case 2:
+		err = f.fixed(e.ParentHash[:])
+	case 3:
+		err = f.fixed(e.UncleHash[:])
+	case 4:
+		err = f.fixed(e.Coinbase[:])
+	case 5:
+		err = f.fixed(e.StateRoot[:])
+	case 6:
+		err = f.fixed(e.TxHashRoot[:])
+	case 7:
+		err = f.fixed(e.ReceiptHash[:])
+	case 8:
+		e.Bloom, err = f.bytes()
+	case 9:
+		err = f.fixed(e.Difficulty[:])
+	case 10:
+		e.GasLimit, err = f.uint()
+	case 11:
+		e.GasUsed, err = f.uint()
+	case 12:
+		e.Time, err = f.uint()
+	case 13:
+		e.Extra, err = f.bytes()
+	case 14:
+		err = f.fixed(e.MixDigest[:])
+	case 15:
+		e.Nonce, err = f.uint()
+	}
+	return err
+}
+
+func (f *CallFrame) marshalProto(w *protoWriter) {
+	w.string(1, f.Type)
+	w.uint(2, f.CallLayer)
+	w.uint(3, f.Depth)
+	w.fixed(4, f.Caller[:])
+	w.fixed(5, f.Callee[:])
+	w.fixed(6, f.CodeAddress[:])
+	w.fixed(7, f.Value[:])
+	w.bytes(8, f.Input)
+	w.bytes(9, f.Output)
+	w.uint(10, f.Gas)
+	w.uint(11, f.GasUsed)
+	w.bool(12, f.Success)
+	w.bool(13, f.Reverted)
+	for _, child := range f.Children {
+		var cw protoWriter
+		child.marshalProto(&cw)
+		w.raw(14, cw)
+	}
+	w.string(15, f.Failure)
+	w.string(16, f.RevertReason)
+	w.uint(17, f.GasSelf)
+	w.uint(18, f.RefundAdded)
+	w.uint(19, f.RefundRemoved)
+}
+
+func (f *CallFrame) unmarshalProto(field *protoField) (err error) {
+	switch field.num {
+	case 1:
+		f.Type, err = field.string()
+	case 2:
+		f.CallLayer, err = field.uint()
+	case 3:
+		f.Depth, err = field.uint()
+	case 4:
+		err = field.fixed(f.Caller[:])
+	case 5:
+		err = field.fixed(f.Callee[:])
+	case 6:
+		err = field.fixed(f.CodeAddress[:])
+	case 7:
+		err = field.fixed(f.Value[:])
+	case 8:
+		f.Input, err = field.bytes()
+	case 9:
+		f.Output, err = field.bytes()
+	case 10:
+		f.Gas, err = field.uint()
+	case 11:
+		f.GasUsed, err = field.uint()
+	case 12:
+		f.Success, err = field.bool()
+	case 13:
+		f.Reverted, err = field.bool()
+	case 14:
+		child := new(CallFrame)
+		if err = field.message(child); err == nil {
+			f.Children = append(f.Children, child)
+		}
+	case 15:
+		f.Failure, err = field.string()
+	case 16:
+		f.RevertReason, err = field.string()
+	case 17:
+		f.GasSelf, err = field.uint()
+	case 18:
+		f.RefundAdded, err = field.uint()
+	case 19:
+		f.RefundRemoved, err = field.uint()
+	}
+	return err
+}
+
+func (d *StateDiff) marshalProto(w *protoWriter) {
+	w.fixed(1, d.TxHash[:])
+	for _, acc := range d.Accounts {
+		var aw protoWriter
+		acc.marshalProto(&aw)
+		w.raw(2, aw)
+	}
+}
+
+func (d *StateDiff) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		err = f.fixed(d.TxHash[:])
+	case 2:
+		acc := new(AccountDiff)
+		if err = f.message(acc); err == nil {
+			d.Accounts = append(d.Accounts, acc)
+		}
+	}
+	return err
+}
+
+func (a *AccountDiff) marshalProto(w *protoWriter) {
+	w.fixed(1, a.Address[:])
+	w.bool(2, a.Created)
+	w.bool(3, a.Suicided)
+	w.bool(4, a.BalanceChanged)
+	w.fixed(5, a.PreBalance[:])
+	w.fixed(6, a.PostBalance[:])
+	w.uint(7, a.BalanceFrame)
+	w.bool(8, a.NonceChanged)
+	w.uint(9, a.PreNonce)
+	w.uint(10, a.PostNonce)
+	w.uint(11, a.NonceFrame)
+	w.bool(12, a.CodeChanged)
+	w.bytes(13, a.PreCode)
+	w.bytes(14, a.PostCode)
+	w.uint(15, a.CodeFrame)
+	for _, slot := range a.Storage {
+		var sw protoWriter
+		slot.marshalProto(&sw)
+		w.raw(16, sw)
+	}
+}
+
+func (a *AccountDiff) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		err = f.fixed(a.Address[:])
+	case 2:
+		a.Created, err = f.bool()
+	case 3:
+		a.Suicided, err = f.bool()
+	case 4:
+		a.BalanceChanged, err = f.bool()
+	case 5:
+		err = f.fixed(a.PreBalance[:])
+	case 6:
+		err = f.fixed(a.PostBalance[:])
+	case 7:
+		a.BalanceFrame, err = f.uint()
+	case 8:
+		a.NonceChanged, err = f.bool()
+	case 9:
+		a.PreNonce, err = f.uint()
+	case 10:
+		a.PostNonce, err = f.uint()
+	case 11:
+		a.NonceFrame, err = f.uint()
+	case 12:
+		a.CodeChanged, err = f.bool()
+	case 13:
+		a.PreCode, err = f.bytes()
+	case 14:
+		a.PostCode, err = f.bytes()
+	case 15:
+		a.CodeFrame, err = f.uint()
+	case 16:
+		slot := new(StorageDiff)
+		if err = f.message(slot); err == nil {
+			a.Storage = append(a.Storage, slot)
+		}
+	}
+	return err
+}
+
+func (s *StorageDiff) marshalProto(w *protoWriter) {
+	w.fixed(1, s.Key[:])
+	w.fixed(2, s.Pre[:])
+	w.fixed(3, s.Post[:])
+	w.uint(4, s.Frame)
+}
+
+func (s *StorageDiff) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		err = f.fixed(s.Key[:])
+	case 2:
+		err = f.fixed(s.Pre[:])
+	case 3:
+		err = f.fixed(s.Post[:])
+	case 4:
+		s.Frame, err = f.uint()
+	}
+	return err
+}
+
+func (tr *BalanceTransfer) marshalProto(w *protoWriter) {
+	w.string(1, tr.Reason)
+	w.fixed(2, tr.TxHash[:])
+	w.uint(3, tr.BlockNumber)
+	w.fixed(4, tr.From[:])
+	w.fixed(5, tr.To[:])
+	w.fixed(6, tr.Amount[:])
+	w.uint(7, tr.CallLayer)
+	w.bool(8, tr.Reverted)
+}
+
+func (tr *BalanceTransfer) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		tr.Reason, err = f.string()
+	case 2:
+		err = f.fixed(tr.TxHash[:])
+	case 3:
+		tr.BlockNumber, err = f.uint()
+	case 4:
+		err = f.fixed(tr.From[:])
+	case 5:
+		err = f.fixed(tr.To[:])
+	case 6:
+		err = f.fixed(tr.Amount[:])
+	case 7:
+		tr.CallLayer, err = f.uint()
+	case 8:
+		tr.Reverted, err = f.bool()
+	}
+	return err
+}
+
+func (tr *TokenTransfer) marshalProto(w *protoWriter) {
+	w.string(1, tr.Action)
+	w.string(2, tr.Standard)
+	w.fixed(3, tr.Token[:])
+	w.fixed(4, tr.TxHash[:])
+	w.uint(5, tr.CallLayer)
+	w.string(6, tr.Method)
+	w.fixed(7, tr.From[:])
+	w.fixed(8, tr.To[:])
+	w.fixed(9, tr.Amount[:])
+	w.bool(10, tr.FromCall)
+	w.bool(11, tr.FromLog)
+	w.bool(12, tr.Consistent)
+	for _, slot := range tr.Slots {
+		var sw protoWriter
+		slot.marshalProto(&sw)
+		w.raw(13, sw)
+	}
+	w.bool(14, tr.Reverted)
+}
+
+func (tr *TokenTransfer) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		tr.Action, err = f.string()
+	case 2:
+		tr.Standard, err = f.string()
+	case 3:
+		err = f.fixed(tr.Token[:])
+	case 4:
+		err = f.fixed(tr.TxHash[:])
+	case 5:
+		tr.CallLayer, err = f.uint()
+	case 6:
+		tr.Method, err = f.string()
+	case 7:
+		err = f.fixed(tr.From[:])
+	case 8:
+		err = f.fixed(tr.To[:])
+	case 9:
+		err = f.fixed(tr.Amount[:])
+	case 10:
+		tr.FromCall, err = f.bool()
+	case 11:
+		tr.FromLog, err = f.bool()
+	case 12:
+		tr.Consistent, err = f.bool()
+	case 13:
+		slot := new(StorageDiff)
+		if err = f.message(slot); err == nil {
+			tr.Slots = append(tr.Slots, slot)
+		}
+	case 14:
+		tr.Reverted, err = f.bool()
+	}
+	return err
+}
+
+func (c *PrecompileCall) marshalProto(w *protoWriter) {
+	w.string(1, c.Name)
+	w.fixed(2, c.Address[:])
+	w.fixed(3, c.TxHash[:])
+	w.uint(4, c.CallLayer)
+	w.fixed(5, c.Caller[:])
+	w.bytes(6, c.Input)
+	w.bytes(7, c.Output)
+	w.uint(8, c.Gas)
+	w.string(9, c.Failure)
+	decodedProto(w, 10, c.Decoded)
+	w.fixed(11, c.Signer[:])
+	w.bool(12, c.HighS)
+}
+
+func (c *PrecompileCall) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		c.Name, err = f.string()
+	case 2:
+		err = f.fixed(c.Address[:])
+	case 3:
+		err = f.fixed(c.TxHash[:])
+	case 4:
+		c.CallLayer, err = f.uint()
+	case 5:
+		err = f.fixed(c.Caller[:])
+	case 6:
+		c.Input, err = f.bytes()
+	case 7:
+		c.Output, err = f.bytes()
+	case 8:
+		c.Gas, err = f.uint()
+	case 9:
+		c.Failure, err = f.string()
+	case 10:
+		c.Decoded, err = decodedField(f)
+	case 11:
+		err = f.fixed(c.Signer[:])
+	case 12:
+		c.HighS, err = f.bool()
+	}
+	return err
+}
+
+func (c *ContractCreation) marshalProto(w *protoWriter) {
+	w.string(1, c.Type)
+	w.fixed(2, c.TxHash[:])
+	w.uint(3, c.BlockNumber)
+	w.fixed(4, c.Creator[:])
+	w.fixed(5, c.Deployer[:])
+	w.fixed(6, c.Address[:])
+	w.fixed(7, c.Salt[:])
+	w.fixed(8, c.InitCodeHash[:])
+	w.bytes(9, c.RuntimeCode)
+	w.fixed(10, c.Value[:])
+	w.uint(11, c.Depth)
+	w.uint(12, c.CallLayer)
+	w.bool(13, c.Success)
+	w.string(14, c.Failure)
+}
+
+func (c *ContractCreation) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		c.Type, err = f.string()
+	case 2:
+		err = f.fixed(c.TxHash[:])
+	case 3:
+		c.BlockNumber, err = f.uint()
+	case 4:
+		err = f.fixed(c.Creator[:])
+	case 5:
+		err = f.fixed(c.Deployer[:])
+	case 6:
+		err = f.fixed(c.Address[:])
+	case 7:
+		err = f.fixed(c.Salt[:])
+	case 8:
+		err = f.fixed(c.InitCodeHash[:])
+	case 9:
+		c.RuntimeCode, err = f.bytes()
+	case 10:
+		err = f.fixed(c.Value[:])
+	case 11:
+		c.Depth, err = f.uint()
+	case 12:
+		c.CallLayer, err = f.uint()
+	case 13:
+		c.Success, err = f.bool()
+	case 14:
+		c.Failure, err = f.string()
+	}
+	return err
+}
+
+func (b *Branch) marshalProto(w *protoWriter) {
+	w.string(1, b.Op)
+	w.fixed(2, b.TxHash[:])
+	w.uint(3, b.CallLayer)
+	w.fixed(4, b.CodeAddress[:])
+	w.fixed(5, b.CodeHash[:])
+	w.uint(6, b.Pc)
+	w.fixed(7, b.Dest[:])
+	w.fixed(8, b.Condition[:])
+	w.bool(9, b.Taken)
+	w.uint(10, b.Next)
+}
+
+func (b *Branch) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		b.Op, err = f.string()
+	case 2:
+		err = f.fixed(b.TxHash[:])
+	case 3:
+		b.CallLayer, err = f.uint()
+	case 4:
+		err = f.fixed(b.CodeAddress[:])
+	case 5:
+		err = f.fixed(b.CodeHash[:])
+	case 6:
+		b.Pc, err = f.uint()
+	case 7:
+		err = f.fixed(b.Dest[:])
+	case 8:
+		err = f.fixed(b.Condition[:])
+	case 9:
+		b.Taken, err = f.bool()
+	case 10:
+		b.Next, err = f.uint()
+	}
+	return err
+}
+
+func (b *BasicBlock) marshalProto(w *protoWriter) {
+	w.fixed(1, b.TxHash[:])
+	w.uint(2, b.CallLayer)
+	w.fixed(3, b.CodeAddress[:])
+	w.fixed(4, b.CodeHash[:])
+	w.uint(5, b.Start)
+	w.uint(6, b.End)
+}
+
+func (b *BasicBlock) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		err = f.fixed(b.TxHash[:])
+	case 2:
+		b.CallLayer, err = f.uint()
+	case 3:
+		err = f.fixed(b.CodeAddress[:])
+	case 4:
+		err = f.fixed(b.CodeHash[:])
+	case 5:
+		b.Start, err = f.uint()
+	case 6:
+		b.End, err = f.uint()
+	}
+	return err
+}
+
+func (m *MemoryAccess) marshalProto(w *protoWriter) {
+	w.string(1, m.Op)
+	w.string(2, m.Direction)
+	w.fixed(3, m.TxHash[:])
+	w.uint(4, m.CallLayer)
+	w.fixed(5, m.CodeAddress[:])
+	w.uint(6, m.Pc)
+	w.uint(7, m.Offset)
+	w.uint(8, m.Length)
+	w.bytes(9, m.Data)
+}
+
+func (m *MemoryAccess) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		m.Op, err = f.string()
+	case 2:
+		m.Direction, err = f.string()
+	case 3:
+		err = f.fixed(m.TxHash[:])
+	case 4:
+		m.CallLayer, err = f.uint()
+	case 5:
+		err = f.fixed(m.CodeAddress[:])
+	case 6:
+		m.Pc, err = f.uint()
+	case 7:
+		m.Offset, err = f.uint()
+	case 8:
+		m.Length, err = f.uint()
+	case 9:
+		m.Data, err = f.bytes()
+	}
+	return err
+}
+
+func (p *SlotPath) marshalProto(w *protoWriter) {
+	w.string(1, p.Kind)
+	w.fixed(2, p.Slot[:])
+	if p.Base != nil {
+		var bw protoWriter
+		p.Base.marshalProto(&bw)
+		w.raw(3, bw)
+	}
+	w.bytes(4, p.Key)
+	w.uint(5, p.Index)
+}
+
+func (p *SlotPath) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		p.Kind, err = f.string()
+	case 2:
+		err = f.fixed(p.Slot[:])
+	case 3:
+		p.Base = new(SlotPath)
+		err = f.message(p.Base)
+	case 4:
+		p.Key, err = f.bytes()
+	case 5:
+		p.Index, err = f.uint()
+	}
+	return err
+}
+
+func (c *DecodedCall) marshalProto(w *protoWriter) {
+	w.string(1, c.Signature)
+	w.string(2, c.Name)
+	for i := range c.Args {
+		var aw protoWriter
+		c.Args[i].marshalProto(&aw)
+		w.raw(3, aw)
+	}
+}
+
+func (c *DecodedCall) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		c.Signature, err = f.string()
+	case 2:
+		c.Name, err = f.string()
+	case 3:
+		var arg DecodedArg
+		if err = f.message(&arg); err == nil {
+			c.Args = append(c.Args, arg)
+		}
+	}
+	return err
+}
+
+func (a *DecodedArg) marshalProto(w *protoWriter) {
+	w.string(1, a.Name)
+	w.string(2, a.Type)
+	w.string(3, a.Value)
+}
+
+func (a *DecodedArg) unmarshalProto(f *protoField) (err error) {
+	switch f.num {
+	case 1:
+		a.Name, err = f.string()
+	case 2:
+		a.Type, err = f.string()
+	case 3:
+		a.Value, err = f.string()
+	}
+	return err
+}
+
+// decodedProto writes c as the embedded message field, if set.
+func decodedProto(w *protoWriter, field int, c *DecodedCall) {
+	if c == nil {
+		return
+	}
+	var cw protoWriter
+	c.marshalProto(&cw)
+	w.raw(field, cw)
+}
+
+// decodedField decodes the embedded message field into a new call.
+func decodedField(f *protoField) (*DecodedCall, error) {
+	c := new(DecodedCall)
+	if err := f.message(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,254 @@
+package collector
+
+import (
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SchemaVersion is the version of the event schema defined in this package
+// and in events.proto. It is written by every codec and checked on decode;
+// it changes whenever a field changes meaning or encoding.
+const SchemaVersion = 9
+
+// Kind identifies the payload carried by an event.
+type Kind uint8
+
+const (
+	KindFlag          Kind = iota // no payload: TXSTART, TXEND and other markers
+	KindIns                       // InsEvent: an executed instruction
+	KindTxStart                   // TxStartEvent: EXTERNALINFOSTART
+	KindTxEnd                     // TxEndEvent: EXTERNALINFOEND
+	KindMessage                   // MessageEvent: TRANS_* internal messages
+	KindBlock                     // BlockEvent: BLOCK_INFO
+	KindCallTree                  // CallFrame: CALLTREE, the root of the call tree
+	KindStateDiff                 // StateDiff: TXSTATEDIFF
+	KindTransfer                  // BalanceTransfer: BALANCE_TRANSFER
+	KindTokenTransfer             // TokenTransfer: TOKEN_TRANSFER
+	KindPrecompile                // PrecompileCall: PRECOMPILE
+	KindCreation                  // ContractCreation: CONTRACT_CREATED
+	KindBranch                    // Branch: BRANCH
+	KindBasicBlock                // BasicBlock: BASICBLOCK
+	KindMemory                    // MemoryAccess: MEMORY
+	numKinds
+)
+
+// TxStartEvent describes an external transaction before it is executed. The
+// transaction always runs at call layer 1.
+type TxStartEvent struct {
+	TxHash      common.Hash    `json:"txhash"`
+	BlockNumber uint64         `json:"blocknumber"`
+	BlockTime   uint64         `json:"blocktime"` // block timestamp in seconds
+	From        common.Address `json:"from"`      // sender of the transaction
+	To          common.Address `json:"to"`        // recipient, unset for a creation
+	Create      bool           `json:"create"`    // whether the transaction deploys a contract
+	Value       Word           `json:"value"`     // wei sent along
+	GasPrice    Word           `json:"gasprice"`  // wei per gas
+	GasLimit    uint64         `json:"gaslimit"`
+	Nonce       uint64         `json:"nonce"`
+	Input       []byte         `json:"input"` // call data, or the init code of a creation
+	Code        []byte         `json:"code"`  // code of the recipient, empty for a creation
+
+	Decoded *DecodedCall `json:"decoded,omitempty" rlp:"nil"` // input of a call, if its method is known
+}
+
+// TxEndEvent describes the outcome of an external transaction. It is sent
+// once the plugins had a chance to block the transaction, so a blocked
+// transaction reports its reverted state.
+type TxEndEvent struct {
+	TxHash      common.Hash    `json:"txhash"`
+	GasUsed     uint64         `json:"gasused"`
+	Success     bool           `json:"success"`     // whether execution finished without error
+	Create      bool           `json:"create"`      // whether the transaction deployed a contract
+	Contract    common.Address `json:"contract"`    // address of the deployed contract
+	DeployCode  []byte         `json:"deploycode"`  // init code of the deployed contract
+	RuntimeCode []byte         `json:"runtimecode"` // code stored at Contract
+
+	Failure      string `json:"failure"`      // cause of a failed transaction, one of the Failure* constants
+	RevertReason string `json:"revertreason"` // Error(string) message of a reverted transaction
+
+	// The transaction again, so the event can be judged on its own.
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"` // recipient, unset for a creation
+	Value Word           `json:"value"`
+	Input []byte         `json:"input"`
+
+	// The receipt, unset if the transaction couldn't be applied.
+	BlockNumber       uint64 `json:"blocknumber"`
+	TxIndex           uint64 `json:"txindex"`           // position of the transaction in the block
+	Status            uint64 `json:"status"`            // 1 if execution succeeded, 0 otherwise
+	PostState         []byte `json:"poststate"`         // intermediate state root of a receipt before Byzantium
+	CumulativeGasUsed uint64 `json:"cumulativegasused"` // gas used by the block up to and including the transaction
+	Bloom             []byte `json:"bloom"`
+	Logs              []*Log `json:"logs"`
+}
+
+// Log is a log emitted by a transaction, as stored in its receipt.
+type Log struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    []byte         `json:"data"`
+	Index   uint64         `json:"index"` // position of the log in the block
+
+	Decoded *DecodedCall `json:"decoded,omitempty" rlp:"nil"` // event of the log, if its signature is known
+}
+
+// Message types of a MessageEvent.
+const (
+	MessageCall         = "CALL"
+	MessageCallCode     = "CALLCODE"
+	MessageDelegateCall = "DELEGATECALL"
+	MessageStaticCall   = "STATICCALL"
+	MessageCreate       = "CREATE"
+	MessageCreate2      = "CREATE2"
+	MessageSuicide      = "SUICIDE"
+)
+
+// MessageEvent describes an internal message once it returned: a call, a
+// contract creation or the balance transfer of a selfdestruct. It is sent as
+// TRANS_<Type>.
+type MessageEvent struct {
+	Type         string         `json:"type"`      // one of the Message* constants
+	Pc           uint64         `json:"pc"`        // pc of the instruction in the sender
+	CallLayer    uint64         `json:"calllayer"` // call layer of the sender
+	From         common.Address `json:"from"`      // contract sending the message
+	To           common.Address `json:"to"`        // callee, created contract or selfdestruct beneficiary
+	Value        Word           `json:"value"`     // wei moved, always zero for DELEGATECALL and STATICCALL
+	Input        []byte         `json:"input"`     // call data, or the init code of a creation
+	Code         []byte         `json:"code"`      // code of the callee, or the code returned by a creation
+	Success      bool           `json:"success"`
+	Failure      string         `json:"failure"`      // cause of a failed message, one of the Failure* constants
+	RevertReason string         `json:"revertreason"` // Error(string) message of a reverted message
+	Salt         Word           `json:"salt"`         // salt of a CREATE2
+
+	Decoded *DecodedCall `json:"decoded,omitempty" rlp:"nil"` // input of a call, if its method is known
+}
+
+// BlockEvent describes the header of a block before its transactions are
+// processed.
+type BlockEvent struct {
+	Number      uint64         `json:"number"`
+	ParentHash  common.Hash    `json:"parenthash"`
+	UncleHash   common.Hash    `json:"unclehash"`
+	Coinbase    common.Address `json:"coinbase"`
+	StateRoot   common.Hash    `json:"stateroot"`
+	TxHashRoot  common.Hash    `json:"txhashroot"`
+	ReceiptHash common.Hash    `json:"receipthash"`
+	Bloom       []byte         `json:"bloom"`
+	Difficulty  Word           `json:"difficulty"`
+	GasLimit    uint64         `json:"gaslimit"`
+	GasUsed     uint64         `json:"gasused"`
+	Time        uint64         `json:"time"`
+	Extra       []byte         `json:"extra"`
+	MixDigest   common.Hash    `json:"mixdigest"`
+	Nonce       uint64         `json:"nonce"`
+}
+
+// SendTxStartEvent wraps the transaction start into an envelope for dispatch.
+func (e *TxStartEvent) SendTxStartEvent() *Event {
+	return &Event{Option: "EXTERNALINFOSTART", TxStart: e}
+}
+
+// SendTxEndEvent wraps the transaction outcome into an envelope for dispatch.
+func (e *TxEndEvent) SendTxEndEvent() *Event {
+	return &Event{Option: "EXTERNALINFOEND", TxEnd: e}
+}
+
+// SendMessageEvent wraps the message into an envelope for dispatch.
+func (e *MessageEvent) SendMessageEvent() *Event {
+	return &Event{Option: "TRANS_" + e.Type, Message: e}
+}
+
+// SendBlockEvent wraps the block header into an envelope for dispatch.
+func (e *BlockEvent) SendBlockEvent() *Event {
+	return &Event{Option: "BLOCK_INFO", Block: e}
+}
+
+// Compat renders the event into the legacy transaction collector.
+func (e *TxStartEvent) Compat() *TransCollector {
+	tc := NewTransCollector()
+	tc.Op = "EXTERNALINFOSTART"
+	tc.TxHash = e.TxHash.String()
+	tc.BlockNumber = strconv.FormatUint(e.BlockNumber, 10)
+	tc.BlockTime = strconv.FormatUint(e.BlockTime, 10)
+	tc.From = e.From.String()
+	tc.Value = e.Value.String()
+	tc.GasPrice = e.GasPrice.String()
+	tc.GasLimit = e.GasLimit
+	tc.Nonce = e.Nonce
+	tc.CallLayer = 1
+	if !e.Create {
+		tc.CallType = "CALL"
+		tc.To = e.To.String()
+		tc.CallInfo.InputData = e.Input
+		tc.CallInfo.ContractCode = e.Code
+	}
+	return tc
+}
+
+// Compat renders the event into the legacy transaction collector.
+func (e *TxEndEvent) Compat() *TransCollector {
+	tc := NewTransCollector()
+	tc.Op = "EXTERNALINFOEND"
+	tc.TxHash = e.TxHash.String()
+	tc.GasUsed = e.GasUsed
+	tc.CallLayer = 1
+	tc.IsSuccess = e.Success
+	if e.Create {
+		tc.CallType = "CREATE"
+		tc.To = e.Contract.String()
+		tc.CreateInfo.ContractAddr = e.Contract.String()
+		tc.CreateInfo.ContractDeployCode = e.DeployCode
+		tc.CreateInfo.ContractRuntimeCode = e.RuntimeCode
+	}
+	return tc
+}
+
+// Compat renders the event into the legacy transaction collector, which
+// reused the external transaction fields for internal messages.
+func (e *MessageEvent) Compat() *TransCollector {
+	tc := NewTransCollector()
+	tc.Op = "TRANS_" + e.Type
+	tc.Pc = e.Pc
+	tc.CallLayer = int(e.CallLayer)
+	tc.From = e.From.String()
+	tc.To = e.To.String()
+	tc.IsSuccess = e.Success
+	switch e.Type {
+	case MessageCall, MessageCallCode, MessageDelegateCall, MessageStaticCall:
+		tc.CallType = "CALL"
+		tc.CallInfo.InputData = e.Input
+		tc.CallInfo.ContractCode = e.Code
+	case MessageCreate, MessageCreate2:
+		tc.CallType = "CREATE"
+		tc.CreateInfo.ContractAddr = e.To.String()
+		tc.CreateInfo.ContractDeployCode = e.Input
+		tc.CreateInfo.ContractRuntimeCode = e.Code
+	}
+	if e.Type != MessageDelegateCall && e.Type != MessageStaticCall {
+		tc.Value = e.Value.String()
+	}
+	return tc
+}
+
+// Compat renders the event into the legacy block collector.
+func (e *BlockEvent) Compat() *BlockCollector {
+	bc := NewBlockCollector()
+	bc.Op = "Block" + strconv.FormatUint(e.Number, 10)
+	bc.ParentHash = e.ParentHash.String()
+	bc.UncleHash = e.UncleHash.String()
+	bc.Coinbase = e.Coinbase.String()
+	bc.StateRoot = e.StateRoot.String()
+	bc.TxHashRoot = e.TxHashRoot.String()
+	bc.ReceiptHash = e.ReceiptHash.String()
+	bc.Bloom = e.Bloom
+	bc.Difficulty = e.Difficulty.String()
+	bc.Number = strconv.FormatUint(e.Number, 10)
+	bc.GasLimit = e.GasLimit
+	bc.GasUsed = e.GasUsed
+	bc.Time = e.Time
+	bc.Extra = e.Extra
+	bc.MixDigest = e.MixDigest.String()
+	bc.Nonce = e.Nonce
+	return bc
+}
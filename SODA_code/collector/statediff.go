@@ -0,0 +1,64 @@
+package collector
+
+import "github.com/ethereum/go-ethereum/common"
+
+// StateDiff lists the accounts whose state a transaction changed, with their
+// state before and after it. It is sent as TXSTATEDIFF once the transaction
+// ran, so a transaction blocked by a plugin only reports the gas payment.
+//
+// Every change is attributed to the call frame that made it last, identified
+// by the CallLayer of its CallFrame. Frame 0 stands for changes made outside
+// of any frame: the nonce of the sender, the purchase and refund of gas and
+// the fee of the miner.
+type StateDiff struct {
+	TxHash   common.Hash    `json:"txhash"`
+	Accounts []*AccountDiff `json:"accounts"` // in the order they were first changed
+}
+
+// AccountDiff describes the changes to a single account. Values that ended
+// up as they were before the transaction are not reported.
+type AccountDiff struct {
+	Address  common.Address `json:"address"`
+	Created  bool           `json:"created"`  // whether the account was created by the transaction
+	Suicided bool           `json:"suicided"` // whether the account self-destructed
+
+	BalanceChanged bool   `json:"balancechanged"`
+	PreBalance     Word   `json:"prebalance"`
+	PostBalance    Word   `json:"postbalance"`
+	BalanceFrame   uint64 `json:"balanceframe"`
+
+	NonceChanged bool   `json:"noncechanged"`
+	PreNonce     uint64 `json:"prenonce"`
+	PostNonce    uint64 `json:"postnonce"`
+	NonceFrame   uint64 `json:"nonceframe"`
+
+	CodeChanged bool   `json:"codechanged"`
+	PreCode     []byte `json:"precode"`
+	PostCode    []byte `json:"postcode"`
+	CodeFrame   uint64 `json:"codeframe"`
+
+	Storage []*StorageDiff `json:"storage"` // changed slots in the order they were first written
+}
+
+// StorageDiff describes the change of a storage slot.
+type StorageDiff struct {
+	Key   common.Hash `json:"key"`
+	Pre   common.Hash `json:"pre"`
+	Post  common.Hash `json:"post"`
+	Frame uint64      `json:"frame"`
+}
+
+// Account returns the changes to addr, nil if it wasn't changed.
+func (d *StateDiff) Account(addr common.Address) *AccountDiff {
+	for _, acc := range d.Accounts {
+		if acc.Address == addr {
+			return acc
+		}
+	}
+	return nil
+}
+
+// SendStateDiffEvent wraps the state diff into an envelope for dispatch.
+func (d *StateDiff) SendStateDiffEvent() *Event {
+	return &Event{Option: "TXSTATEDIFF", StateDiff: d}
+}
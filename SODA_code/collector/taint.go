@@ -0,0 +1,38 @@
+package collector
+
+import "strings"
+
+// Taint is the set of sources a value was computed from. The interpreter
+// tracks it for plugins registering with taint tracking enabled and reports
+// the taint of the arguments of every instruction event.
+type Taint uint16
+
+// Taint sources.
+const (
+	TaintOrigin     Taint = 1 << iota // ORIGIN
+	TaintTimestamp                    // TIMESTAMP
+	TaintNumber                       // NUMBER
+	TaintBlockhash                    // BLOCKHASH
+	TaintBalance                      // BALANCE
+	TaintCallData                     // CALLDATALOAD and CALLDATACOPY
+	TaintStorage                      // SLOAD
+	TaintReturnData                   // outcome and return data of a call
+)
+
+var taintNames = []string{"ORIGIN", "TIMESTAMP", "NUMBER", "BLOCKHASH", "BALANCE", "CALLDATA", "STORAGE", "RETURNDATA"}
+
+// Has reports whether the value depends on any of the given sources.
+func (t Taint) Has(sources Taint) bool {
+	return t&sources != 0
+}
+
+// String lists the sources separated by "|", or returns "" for a clean value.
+func (t Taint) String() string {
+	var names []string
+	for i, name := range taintNames {
+		if t&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
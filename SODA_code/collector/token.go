@@ -0,0 +1,223 @@
+package collector
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Token standards.
+const (
+	TokenERC20  = "ERC20"
+	TokenERC721 = "ERC721"
+)
+
+// Actions of a TokenTransfer. A transfer from the zero address is a mint and
+// one to the zero address a burn.
+const (
+	TokenTransferred = "TRANSFER"
+	TokenApproved    = "APPROVAL"
+	TokenMinted      = "MINT"
+	TokenBurned      = "BURN"
+)
+
+// TokenTransfer describes a token transfer, approval, mint or burn, decoded
+// from the input of a call to the token, from a Transfer or Approval log it
+// emitted, or from both. A call and the log it emitted are reported once.
+// Token transfers are sent as TOKEN_TRANSFER in execution order once the
+// transaction ended.
+type TokenTransfer struct {
+	Action    string         `json:"action"`   // one of the Token* action constants
+	Standard  string         `json:"standard"` // told by the log or the method, empty if unknown
+	Token     common.Address `json:"token"`    // contract whose storage holds the balances
+	TxHash    common.Hash    `json:"txhash"`
+	CallLayer uint64         `json:"calllayer"` // frame of the call, or of the log without a call
+	Method    string         `json:"method"`    // name of the decoded method, empty without a call
+	From      common.Address `json:"from"`      // owner for an approval
+	To        common.Address `json:"to"`        // spender for an approval
+	Amount    Word           `json:"amount"`    // amount, or the id of an ERC721 token
+
+	FromCall   bool `json:"fromcall"`   // whether the call input was decoded
+	FromLog    bool `json:"fromlog"`    // whether a log was decoded
+	Consistent bool `json:"consistent"` // whether the call and the log agree, false if either is missing
+
+	Slots    []*StorageDiff `json:"slots"`    // storage of the token changed by the frame and its children
+	Reverted bool           `json:"reverted"` // whether the frame or the transaction was reverted
+}
+
+// SendTokenTransferEvent wraps the token transfer into an envelope for
+// dispatch.
+func (tr *TokenTransfer) SendTokenTransferEvent() *Event {
+	return &Event{Option: "TOKEN_TRANSFER", TokenTransfer: tr}
+}
+
+// tokenMethod is a token method decoded from call input.
+type tokenMethod struct {
+	name     string
+	standard string // empty if shared by ERC20 and ERC721
+	approve  bool
+	from     bool // whether the first argument is the owner
+	minting  bool
+}
+
+var tokenMethods = map[[4]byte]tokenMethod{
+	{0xa9, 0x05, 0x9c, 0xbb}: {name: "transfer", standard: TokenERC20},
+	{0x23, 0xb8, 0x72, 0xdd}: {name: "transferFrom", from: true},
+	{0x42, 0x84, 0x2e, 0x0e}: {name: "safeTransferFrom", standard: TokenERC721, from: true},
+	{0xb8, 0x8d, 0x4f, 0xde}: {name: "safeTransferFrom", standard: TokenERC721, from: true},
+	{0x09, 0x5e, 0xa7, 0xb3}: {name: "approve", approve: true},
+	{0x40, 0xc1, 0x0f, 0x19}: {name: "mint", minting: true},
+}
+
+// Topics of the Transfer and Approval logs, shared by ERC20 and ERC721.
+var (
+	transferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	approvalTopic = common.HexToHash("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+)
+
+// DecodeTokenCall decodes a call of token by caller to a token method, nil if
+// the input isn't one.
+func DecodeTokenCall(caller, token common.Address, input []byte) *TokenTransfer {
+	if len(input) < 4 {
+		return nil
+	}
+	var selector [4]byte
+	copy(selector[:], input)
+	m, ok := tokenMethods[selector]
+	if !ok {
+		return nil
+	}
+	args := input[4:]
+	arg := func(i int) (w Word) {
+		if len(args) >= 32*(i+1) {
+			copy(w[:], args[32*i:32*(i+1)])
+		}
+		return w
+	}
+	addr := func(i int) common.Address {
+		w := arg(i)
+		return common.BytesToAddress(w[12:])
+	}
+	tr := &TokenTransfer{Standard: m.standard, Token: token, Method: m.name, FromCall: true}
+	switch {
+	case m.from:
+		tr.From, tr.To, tr.Amount = addr(0), addr(1), arg(2)
+	case m.minting:
+		tr.To, tr.Amount = addr(0), arg(1)
+	default:
+		tr.From, tr.To, tr.Amount = caller, addr(0), arg(1)
+	}
+	tr.Action = tokenAction(m.approve, tr.From, tr.To)
+	return tr
+}
+
+// DecodeTokenLog decodes a Transfer or Approval log emitted by token, nil if
+// the log isn't one. ERC721 logs carry the token id as a fourth topic.
+func DecodeTokenLog(token common.Address, topics []common.Hash, data []byte) *TokenTransfer {
+	if len(topics) < 3 || (topics[0] != transferTopic && topics[0] != approvalTopic) {
+		return nil
+	}
+	tr := &TokenTransfer{
+		Token:   token,
+		From:    common.BytesToAddress(topics[1][12:]),
+		To:      common.BytesToAddress(topics[2][12:]),
+		FromLog: true,
+	}
+	switch {
+	case len(topics) == 4:
+		tr.Standard, tr.Amount = TokenERC721, Word(topics[3])
+	case len(topics) == 3 && len(data) == 32:
+		tr.Standard = TokenERC20
+		copy(tr.Amount[:], data)
+	default:
+		return nil
+	}
+	tr.Action = tokenAction(topics[0] == approvalTopic, tr.From, tr.To)
+	return tr
+}
+
+func tokenAction(approve bool, from, to common.Address) string {
+	switch {
+	case approve:
+		return TokenApproved
+	case from == (common.Address{}):
+		return TokenMinted
+	case to == (common.Address{}):
+		return TokenBurned
+	}
+	return TokenTransferred
+}
+
+// merge adds the log decoded for a call to it.
+func (tr *TokenTransfer) merge(log *TokenTransfer) {
+	tr.FromLog = true
+	tr.Consistent = tr.Action == log.Action && tr.From == log.From && tr.To == log.To && tr.Amount == log.Amount
+	if tr.Standard == "" || tr.Consistent {
+		tr.Standard = log.Standard
+	}
+}
+
+// matches reports whether log may have been emitted for the call tr.
+func (tr *TokenTransfer) matches(log *TokenTransfer) bool {
+	return tr.Token == log.Token && (tr.Action == TokenApproved) == (log.Action == TokenApproved)
+}
+
+// Token records a token call or log of the current frame. It is ignored
+// outside of execution.
+func (t *CallTree) Token(tr *TokenTransfer) {
+	f := t.Current()
+	if f == nil {
+		return
+	}
+	tr.CallLayer = f.CallLayer
+	t.tokens = append(t.tokens, tr)
+	t.tokenFrames = append(t.tokenFrames, f)
+}
+
+// TokenTransfers returns the token transfers recorded so far in execution
+// order. A call absorbs the first matching log of the token emitted by the
+// call or its children. Slots lists the changes of diff to the storage of the
+// token attributed to the frame of a transfer or its children; diff may be
+// nil.
+func (t *CallTree) TokenTransfers(diff *StateDiff) []*TokenTransfer {
+	var (
+		transfers []*TokenTransfer
+		merged    = make(map[*TokenTransfer]bool)
+	)
+	for i, tr := range t.tokens {
+		if merged[tr] {
+			continue
+		}
+		frame := t.tokenFrames[i]
+		layers := make(map[uint64]bool)
+		frame.Walk(func(f *CallFrame) bool {
+			layers[f.CallLayer] = true
+			return true
+		})
+		if tr.FromCall {
+			for j := i + 1; j < len(t.tokens); j++ {
+				log := t.tokens[j]
+				if log.FromLog && !merged[log] && layers[log.CallLayer] && tr.matches(log) {
+					tr.merge(log)
+					merged[log] = true
+					break
+				}
+			}
+		}
+		tr.Slots = nil
+		if acc := diff.account(tr.Token); acc != nil {
+			for _, slot := range acc.Storage {
+				if layers[slot.Frame] {
+					tr.Slots = append(tr.Slots, slot)
+				}
+			}
+		}
+		tr.Reverted = !frame.Committed()
+		transfers = append(transfers, tr)
+	}
+	return transfers
+}
+
+// account is Account tolerating a nil diff.
+func (d *StateDiff) account(addr common.Address) *AccountDiff {
+	if d == nil {
+		return nil
+	}
+	return d.Account(addr)
+}
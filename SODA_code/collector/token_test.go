@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// tokenInput encodes a call to a token method with address and word arguments.
+func tokenInput(selector string, args ...common.Hash) []byte {
+	input := common.FromHex(selector)
+	for _, arg := range args {
+		input = append(input, arg[:]...)
+	}
+	return input
+}
+
+func TestDecodeToken(t *testing.T) {
+	var (
+		token  = common.HexToAddress("0x70")
+		alice  = common.HexToAddress("0xa1")
+		bob    = common.HexToAddress("0xb0")
+		amount = common.BigToHash(big.NewInt(5))
+	)
+	tests := []struct {
+		name string
+		have *TokenTransfer
+		want TokenTransfer
+	}{
+		{
+			"transfer",
+			DecodeTokenCall(alice, token, tokenInput("a9059cbb", bob.Hash(), amount)),
+			TokenTransfer{Action: TokenTransferred, Standard: TokenERC20, Token: token, Method: "transfer", From: alice, To: bob, Amount: Word(amount), FromCall: true},
+		},
+		{
+			"transferFrom",
+			DecodeTokenCall(bob, token, tokenInput("23b872dd", alice.Hash(), common.Hash{}, amount)),
+			TokenTransfer{Action: TokenBurned, Token: token, Method: "transferFrom", From: alice, Amount: Word(amount), FromCall: true},
+		},
+		{
+			"mint",
+			DecodeTokenCall(alice, token, tokenInput("40c10f19", bob.Hash(), amount)),
+			TokenTransfer{Action: TokenMinted, Token: token, Method: "mint", To: bob, Amount: Word(amount), FromCall: true},
+		},
+		{
+			"erc20 log",
+			DecodeTokenLog(token, []common.Hash{transferTopic, alice.Hash(), bob.Hash()}, amount[:]),
+			TokenTransfer{Action: TokenTransferred, Standard: TokenERC20, Token: token, From: alice, To: bob, Amount: Word(amount), FromLog: true},
+		},
+		{
+			"erc721 approval log",
+			DecodeTokenLog(token, []common.Hash{approvalTopic, alice.Hash(), bob.Hash(), amount}, nil),
+			TokenTransfer{Action: TokenApproved, Standard: TokenERC721, Token: token, From: alice, To: bob, Amount: Word(amount), FromLog: true},
+		},
+	}
+	for _, tt := range tests {
+		if tt.have == nil {
+			t.Errorf("%s: not decoded", tt.name)
+		} else if !reflect.DeepEqual(*tt.have, tt.want) {
+			t.Errorf("%s: mismatch:\nhave %+v\nwant %+v", tt.name, *tt.have, tt.want)
+		}
+	}
+	if tr := DecodeTokenCall(alice, token, common.FromHex("70a08231")); tr != nil {
+		t.Errorf("balanceOf decoded as %+v", tr)
+	}
+	if tr := DecodeTokenLog(token, []common.Hash{transferTopic, alice.Hash()}, nil); tr != nil {
+		t.Errorf("log with missing topics decoded as %+v", tr)
+	}
+}
+
+func TestTokenTransfers(t *testing.T) {
+	var (
+		tree   CallTree
+		token  = common.HexToAddress("0x70")
+		alice  = common.HexToAddress("0xa1")
+		bob    = common.HexToAddress("0xb0")
+		amount = common.BigToHash(common.Big3)
+	)
+	// alice transfers to bob through a token emitting two logs, the first with
+	// the wrong amount, then approves bob, which the token doesn't log.
+	tree.Enter(&CallFrame{CallLayer: 1, Callee: alice})
+	tree.Enter(&CallFrame{CallLayer: 2, Callee: token})
+	tree.Token(DecodeTokenCall(alice, token, tokenInput("a9059cbb", bob.Hash(), amount)))
+	tree.Token(DecodeTokenLog(token, []common.Hash{transferTopic, alice.Hash(), bob.Hash()}, common.BigToHash(common.Big1).Bytes()))
+	tree.Token(DecodeTokenLog(token, []common.Hash{transferTopic, alice.Hash(), bob.Hash()}, common.BigToHash(common.Big2).Bytes()))
+	tree.Exit(nil, 0, "")
+	tree.Enter(&CallFrame{CallLayer: 3, Callee: token})
+	tree.Token(DecodeTokenCall(alice, token, tokenInput("095ea7b3", bob.Hash(), amount)))
+	tree.Exit(nil, 0, FailureOutOfGas)
+	tree.Exit(nil, 0, "")
+
+	diff := &StateDiff{Accounts: []*AccountDiff{{Address: token, Storage: []*StorageDiff{
+		{Key: bob.Hash(), Post: amount, Frame: 2},
+		{Key: alice.Hash(), Post: amount, Frame: 1},
+	}}}}
+	transfers := tree.TokenTransfers(diff)
+	if len(transfers) != 3 {
+		t.Fatalf("have %d token transfers, want 3", len(transfers))
+	}
+	if tr := transfers[0]; !tr.FromCall || !tr.FromLog || tr.Consistent || tr.Standard != TokenERC20 ||
+		len(tr.Slots) != 1 || tr.Slots[0].Key != bob.Hash() || tr.Reverted || tr.Amount != Word(amount) {
+		t.Errorf("unexpected transfer: %+v", tr)
+	}
+	if tr := transfers[1]; tr.FromCall || !tr.FromLog || tr.Amount != Word(common.BigToHash(common.Big2)) || tr.CallLayer != 2 || len(tr.Slots) != 1 {
+		t.Errorf("unexpected log without a call: %+v", tr)
+	}
+	if tr := transfers[2]; tr.Action != TokenApproved || tr.FromLog || tr.Consistent || !tr.Reverted || tr.CallLayer != 3 {
+		t.Errorf("unexpected approval: %+v", tr)
+	}
+}
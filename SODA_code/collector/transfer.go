@@ -0,0 +1,31 @@
+package collector
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Reasons of a BalanceTransfer.
+const (
+	TransferCall    = "CALL"    // value sent along with a call, the transaction included
+	TransferCreate  = "CREATE"  // endowment of a created contract
+	TransferSuicide = "SUICIDE" // balance left to the beneficiary of a selfdestruct
+	TransferReward  = "REWARD"  // block reward of the miner
+	TransferUncle   = "UNCLE"   // reward of the miner of an uncle
+)
+
+// BalanceTransfer describes a movement of ether. Transfers made by a
+// transaction are sent as BALANCE_TRANSFER in execution order once it ended,
+// so Reverted is final; rewards are sent when the block is finalised.
+type BalanceTransfer struct {
+	Reason      string         `json:"reason"` // one of the Transfer* constants
+	TxHash      common.Hash    `json:"txhash"` // unset for rewards
+	BlockNumber uint64         `json:"blocknumber"`
+	From        common.Address `json:"from"` // unset for rewards
+	To          common.Address `json:"to"`
+	Amount      Word           `json:"amount"`    // wei moved
+	CallLayer   uint64         `json:"calllayer"` // frame that moved the ether, 0 for rewards
+	Reverted    bool           `json:"reverted"`  // whether the frame or the transaction was reverted
+}
+
+// SendBalanceTransferEvent wraps the transfer into an envelope for dispatch.
+func (tr *BalanceTransfer) SendBalanceTransferEvent() *Event {
+	return &Event{Option: "BALANCE_TRANSFER", Transfer: tr}
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// WordLength is the length of an EVM stack word in bytes.
+const WordLength = 32
+
+// Word is a 256-bit EVM stack word kept in its big-endian binary form. It is
+// copied out of the interpreter without any formatting; string rendering only
+// happens when a consumer asks for it.
+type Word [WordLength]byte
+
+// BigToWord converts a stack value into a Word. Negative values, which some
+// signed opcodes keep on the side, are stored in two's complement form.
+func BigToWord(b *big.Int) Word {
+	var w Word
+	if b.Sign() < 0 {
+		b = math.U256(new(big.Int).Set(b))
+	}
+	math.ReadBits(b, w[:])
+	return w
+}
+
+// BytesToWord left-pads b to 32 bytes. If b is larger than 32 bytes only the
+// trailing 32 bytes are kept.
+func BytesToWord(b []byte) Word {
+	var w Word
+	if len(b) > WordLength {
+		b = b[len(b)-WordLength:]
+	}
+	copy(w[WordLength-len(b):], b)
+	return w
+}
+
+// AddressToWord left-pads an address into a Word, the same way it would sit
+// on the EVM stack.
+func AddressToWord(a common.Address) Word {
+	return BytesToWord(a.Bytes())
+}
+
+// Uint64ToWord converts an unsigned integer into a Word.
+func Uint64ToWord(v uint64) Word {
+	return BigToWord(new(big.Int).SetUint64(v))
+}
+
+// Big returns the word as a freshly allocated unsigned big integer.
+func (w Word) Big() *big.Int { return new(big.Int).SetBytes(w[:]) }
+
+// Uint64 returns the lowest 64 bits of the word.
+func (w Word) Uint64() uint64 { return w.Big().Uint64() }
+
+// IsZero reports whether all bytes of the word are zero.
+func (w Word) IsZero() bool { return w == Word{} }
+
+// Bytes returns a copy of the underlying bytes.
+func (w Word) Bytes() []byte { return common.CopyBytes(w[:]) }
+
+// Address returns the lowest 20 bytes of the word as an address.
+func (w Word) Address() common.Address { return common.BytesToAddress(w[:]) }
+
+// Hash reinterprets the word as a hash.
+func (w Word) Hash() common.Hash { return common.Hash(w) }
+
+// Hex renders the word as a 0x-prefixed, zero-padded hex string.
+func (w Word) Hex() string { return hexutil.Encode(w[:]) }
+
+// String renders the word as an unsigned decimal number, the format used by
+// the legacy string collector.
+func (w Word) String() string { return w.Big().String() }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w Word) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(w[:]).MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (w *Word) UnmarshalText(input []byte) error {
+	return hexutil.UnmarshalFixedText("Word", input, w[:])
+}
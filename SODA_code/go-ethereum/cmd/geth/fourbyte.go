@@ -0,0 +1,24 @@
+package main
+
+//add new file
+
+import (
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/signer/fourbyte"
+)
+
+// loadSignatures hands the 4byte database embedded in signer/fourbyte to the
+// signature registry of the SODA apps, so they see the calls they inspect and
+// alert on decoded. Like the dashboard assets, the database is generated with
+// go generate.
+func loadSignatures() {
+	db, err := fourbyte.New()
+	if err != nil {
+		log.Warn("Failed to load 4byte database", "err", err)
+		return
+	}
+	embeds, _ := db.Size()
+	log.Info("Loaded 4byte database", "embeds", embeds)
+	pluginManage.Signatures.SetSelectors(db.Selector)
+}
@@ -306,6 +306,8 @@ func geth(ctx *cli.Context) error {
 	if args := ctx.Args(); len(args) > 0 {
 		return fmt.Errorf("invalid command: %q", args[0])
 	}
+	//add new
+	loadSignatures()
 	node := makeFullNode(ctx)
 	defer node.Close()
 	startNode(ctx, node)
@@ -0,0 +1,77 @@
+package pluginManage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// alertLimit is the number of recent alerts kept in memory.
+const alertLimit = 200
+
+// Alert is a warning raised by a plugin, as written to its log.
+type Alert struct {
+	Time     time.Time `json:"time"`
+	Plugin   string    `json:"plugin"`
+	Event    string    `json:"event"`    // event the plugin was handling
+	Severity string    `json:"severity"` // "warning" or "serious"
+	Message  string    `json:"message"`
+	TxHash   string    `json:"txhash"`
+	Contract string    `json:"contract"`
+	Block    uint64    `json:"block"`
+
+	Call *collector.DecodedCall `json:"call,omitempty"` // call the alert was raised in, if its method is known
+}
+
+var alertLog struct {
+	lock   sync.Mutex
+	seq    uint64   // number of alerts raised so far
+	recent []*Alert // last alertLimit alerts, oldest first
+}
+
+// recordAlert adds an alert to the in-memory log.
+func recordAlert(plugin, event string, level int, message, contract string) {
+	alert := &Alert{
+		Time:     time.Now(),
+		Plugin:   plugin,
+		Event:    event,
+		Severity: "warning",
+		Message:  message,
+		TxHash:   tingrong.TxHash,
+		Contract: contract,
+		Block:    tingrong.BlockNumber,
+		Call:     currentCall(),
+	}
+	if level > 2 {
+		alert.Severity = "serious"
+	}
+	alertLog.lock.Lock()
+	defer alertLog.lock.Unlock()
+
+	alertLog.seq++
+	alertLog.recent = append(alertLog.recent, alert)
+	if len(alertLog.recent) > alertLimit {
+		alertLog.recent = alertLog.recent[len(alertLog.recent)-alertLimit:]
+	}
+}
+
+// AlertsSince returns the alerts raised after the first seq ones, up to the
+// last alertLimit, together with the number of alerts raised so far. Pass the
+// returned number to the next call to only receive new alerts.
+func AlertsSince(seq uint64) ([]*Alert, uint64) {
+	alertLog.lock.Lock()
+	defer alertLog.lock.Unlock()
+
+	if seq >= alertLog.seq {
+		return nil, alertLog.seq
+	}
+	fresh := alertLog.seq - seq
+	if fresh > uint64(len(alertLog.recent)) {
+		fresh = uint64(len(alertLog.recent))
+	}
+	alerts := make([]*Alert, fresh)
+	copy(alerts, alertLog.recent[uint64(len(alertLog.recent))-fresh:])
+	return alerts, alertLog.seq
+}
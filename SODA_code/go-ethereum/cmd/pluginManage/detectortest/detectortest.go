@@ -0,0 +1,259 @@
+// Package detectortest runs SODA detectors against real contract bytecode in
+// an in-memory EVM, so every detection app can ship its own unit tests.
+//
+// A test creates a Harness, loads the detectors under test, deploys the
+// contracts of its scenario and sends transactions to them. The alerts the
+// detectors raise along the way are collected and can be checked with the
+// Expect helpers:
+//
+//	h := detectortest.New(t)
+//	h.Load("P1")
+//	h.Deploy(victim, detectortest.Assemble(t, victimSource))
+//	h.Call(attacker, victim, nil, nil)
+//	h.ExpectAlert("P1", "warning")
+package detectortest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/plugintest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// Harness is an in-memory chain with SODA detection enabled. Every call and
+// creation runs as a whole transaction, reported to the registered detectors
+// the way a block transaction is.
+type Harness struct {
+	t testing.TB
+
+	Config  *runtime.Config
+	Manager *pluginManage.PluginManages
+
+	seq uint64 // alerts raised before the harness was created
+}
+
+// New returns a harness without detectors and with an empty state. All forks
+// up to Petersburg are active from the genesis block.
+func New(t testing.TB) *Harness {
+	t.Helper()
+
+	if _, err := plugintest.WorkDir(); err != nil {
+		t.Fatal(err)
+	}
+	manager := pluginManage.NewPluginManages()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	_, seq := pluginManage.AlertsSince(^uint64(0))
+	return &Harness{
+		t: t,
+		Config: &runtime.Config{
+			ChainConfig: &params.ChainConfig{
+				ChainID:             big.NewInt(1),
+				HomesteadBlock:      new(big.Int),
+				EIP150Block:         new(big.Int),
+				EIP155Block:         new(big.Int),
+				EIP158Block:         new(big.Int),
+				ByzantiumBlock:      new(big.Int),
+				ConstantinopleBlock: new(big.Int),
+				PetersburgBlock:     new(big.Int),
+				TransferDataPlg:     manager,
+			},
+			BlockNumber: big.NewInt(1),
+			State:       statedb,
+			TxStart:     true,
+		},
+		Manager: manager,
+		seq:     seq,
+	}
+}
+
+// Load builds the named detection apps and registers them. The test is
+// skipped if the plugins can't be built on this host.
+func (h *Harness) Load(names ...string) {
+	h.t.Helper()
+
+	for _, name := range names {
+		path, err := plugintest.Build(name)
+		if err != nil {
+			h.t.Skip(err)
+		}
+		pluginManage.RegisterPlugin(h.Manager, path)
+	}
+}
+
+// Register registers a detector compiled into the test binary. Symbols maps
+// the names a plugin exports, Register and the handlers it lists, to the
+// functions implementing them.
+func (h *Harness) Register(symbols map[string]interface{}) {
+	h.t.Helper()
+
+	lookup := func(symbol string) (interface{}, error) {
+		if fn, ok := symbols[symbol]; ok {
+			return fn, nil
+		}
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	}
+	if err := pluginManage.RegisterDetector(h.Manager, lookup); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+// Subscribe registers a detector compiled into the test binary under name.
+// Handlers maps the opcodes and events the detector subscribes to, to the
+// functions handling them.
+func (h *Harness) Subscribe(name string, handlers map[string]interface{}) {
+	h.t.Helper()
+	h.subscribe(&pluginManage.RegisterInfo{PluginName: name}, handlers)
+}
+
+// SubscribeTaint is Subscribe for a detector that needs taint tracking.
+func (h *Harness) SubscribeTaint(name string, handlers map[string]interface{}) {
+	h.t.Helper()
+	h.subscribe(&pluginManage.RegisterInfo{PluginName: name, Taint: true}, handlers)
+}
+
+// subscribe exports every handler under the name of its opcode.
+func (h *Harness) subscribe(info *pluginManage.RegisterInfo, handlers map[string]interface{}) {
+	h.t.Helper()
+
+	info.OpCode = make(map[string]string, len(handlers))
+	symbols := make(map[string]interface{}, len(handlers)+1)
+	for opcode, handler := range handlers {
+		info.OpCode[opcode] = opcode
+		symbols[opcode] = handler
+	}
+	blob, err := json.Marshal(info)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	symbols["Register"] = func() []byte { return blob }
+	h.Register(symbols)
+}
+
+// State returns the state the transactions run on.
+func (h *Harness) State() *state.StateDB {
+	return h.Config.State
+}
+
+// Deploy puts code at addr without running a creation transaction.
+func (h *Harness) Deploy(addr common.Address, code []byte) {
+	h.Config.State.CreateAccount(addr)
+	h.Config.State.SetCode(addr, code)
+}
+
+// Fund adds wei to the balance of addr.
+func (h *Harness) Fund(addr common.Address, wei *big.Int) {
+	h.Config.State.AddBalance(addr, wei)
+}
+
+// SetStorage sets a storage slot of addr.
+func (h *Harness) SetStorage(addr common.Address, key, value common.Hash) {
+	h.Config.State.SetState(addr, key, value)
+}
+
+// Call sends a transaction from from to the contract at to. A nil value sends
+// no ether. It returns the output of the call and its error, if any.
+func (h *Harness) Call(from, to common.Address, input []byte, value *big.Int) ([]byte, error) {
+	h.prepare(from, value)
+	ret, _, err := runtime.Call(to, input, h.Config)
+	return ret, err
+}
+
+// Create sends a transaction from from deploying a contract with the given
+// init code. It returns the address of the new contract.
+func (h *Harness) Create(from common.Address, code []byte, value *big.Int) (common.Address, error) {
+	h.prepare(from, value)
+	_, addr, _, err := runtime.Create(code, h.Config)
+	return addr, err
+}
+
+func (h *Harness) prepare(from common.Address, value *big.Int) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	h.Config.Origin = from
+	h.Config.Value = value
+}
+
+// Blocked reports whether a detector blocked the last transaction.
+func (h *Harness) Blocked() bool {
+	return tingrong.BLOCKING_FLAG
+}
+
+// Alerts returns the alerts raised since the harness was created.
+func (h *Harness) Alerts() []*pluginManage.Alert {
+	alerts, _ := pluginManage.AlertsSince(h.seq)
+	return alerts
+}
+
+// Filter returns the alerts raised by plugin since the harness was created.
+func (h *Harness) Filter(plugin string) []*pluginManage.Alert {
+	var alerts []*pluginManage.Alert
+	for _, alert := range h.Alerts() {
+		if alert.Plugin == plugin {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// ExpectAlert fails the test unless plugin raised an alert of the given
+// severity, "warning" or "serious". It returns the first such alert.
+func (h *Harness) ExpectAlert(plugin, severity string) *pluginManage.Alert {
+	h.t.Helper()
+
+	for _, alert := range h.Filter(plugin) {
+		if alert.Severity == severity {
+			return alert
+		}
+	}
+	h.t.Fatalf("no %s alert from %s, have %s", severity, plugin, describe(h.Alerts()))
+	return nil
+}
+
+// ExpectNoAlert fails the test if plugin raised any alert.
+func (h *Harness) ExpectNoAlert(plugin string) {
+	h.t.Helper()
+
+	if alerts := h.Filter(plugin); len(alerts) > 0 {
+		h.t.Fatalf("unexpected alerts from %s: %s", plugin, describe(alerts))
+	}
+}
+
+func describe(alerts []*pluginManage.Alert) string {
+	if len(alerts) == 0 {
+		return "none"
+	}
+	lines := make([]string, len(alerts))
+	for i, alert := range alerts {
+		lines[i] = "\n\t" + alert.Severity + " " + alert.Plugin + " on " + alert.Event + ": " + alert.Message
+	}
+	return strings.Join(lines, "")
+}
+
+// Assemble compiles EVM assembly in the syntax of core/asm into bytecode:
+// one instruction per line, "name:" defining a jump destination, "@name"
+// referring to it and ";;" starting a comment.
+func Assemble(t testing.TB, source string) []byte {
+	t.Helper()
+
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex([]byte(source), false))
+	bin, errs := compiler.Compile()
+	if len(errs) > 0 {
+		t.Fatalf("failed to assemble: %v", errs)
+	}
+	return hexutil.MustDecode("0x" + bin)
+}
@@ -0,0 +1,287 @@
+package pluginManage
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/build"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/collector"
+	"github.com/golang/protobuf/proto"
+)
+
+// The collector writes protobuf by hand. These tests decode its output with
+// golang/protobuf into message types generated from events.proto, so the
+// encoder can't drift from the schema it documents.
+
+var updateEventsPB = flag.Bool("update-events-pb", false, "regenerate eventspb_test.go from events.proto")
+
+const eventsPBFile = "eventspb_test.go"
+
+// protoSchemaField is a field declared in events.proto.
+type protoSchemaField struct {
+	name     string
+	typ      string
+	num      int
+	repeated bool
+}
+
+// protoSchemaMessage is a message declared in events.proto.
+type protoSchemaMessage struct {
+	name   string
+	fields []protoSchemaField
+}
+
+var protoFieldRE = regexp.MustCompile(`^(repeated\s+)?(\w+)\s+(\w+)\s*=\s*(\d+)\s*;$`)
+
+// parseEventsProto reads the messages of events.proto in declaration order.
+// Oneof members are taken as plain fields, which they are on the wire.
+func parseEventsProto() ([]*protoSchemaMessage, error) {
+	pkg, err := build.Import("github.com/ethereum/collector", "", build.FindOnly)
+	if err != nil {
+		return nil, err
+	}
+	src, err := ioutil.ReadFile(filepath.Join(pkg.Dir, "events.proto"))
+	if err != nil {
+		return nil, err
+	}
+	var messages []*protoSchemaMessage
+	for i, line := range strings.Split(string(src), "\n") {
+		if j := strings.Index(line, "//"); j >= 0 {
+			line = line[:j]
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line == "}" || strings.HasPrefix(line, "oneof "):
+		case strings.HasPrefix(line, "syntax ") || strings.HasPrefix(line, "package "):
+		case strings.HasPrefix(line, "message "):
+			name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "message "), "{"))
+			messages = append(messages, &protoSchemaMessage{name: name})
+		default:
+			m := protoFieldRE.FindStringSubmatch(line)
+			if m == nil || len(messages) == 0 {
+				return nil, fmt.Errorf("events.proto:%d: unexpected line %q", i+1, line)
+			}
+			var num int
+			fmt.Sscan(m[4], &num)
+			msg := messages[len(messages)-1]
+			msg.fields = append(msg.fields, protoSchemaField{m[3], m[2], num, m[1] != ""})
+		}
+	}
+	return messages, nil
+}
+
+// protoScalars maps the scalar types of events.proto to their wire and Go types.
+var protoScalars = map[string][2]string{
+	"uint64": {"varint", "uint64"},
+	"int64":  {"varint", "int64"},
+	"uint32": {"varint", "uint32"},
+	"bool":   {"varint", "bool"},
+	"string": {"bytes", "string"},
+	"bytes":  {"bytes", "[]byte"},
+}
+
+// generateEventsPB writes the messages as Go types in the form of
+// protoc-gen-go, without the oneof wrappers.
+func generateEventsPB(messages []*protoSchemaMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by TestEventsPB from events.proto with -update-events-pb. DO NOT EDIT.\n\n")
+	buf.WriteString("package pluginManage\n\nimport \"github.com/golang/protobuf/proto\"\n")
+	for _, msg := range messages {
+		name := "pb" + msg.name
+		fmt.Fprintf(&buf, "\ntype %s struct {\n", name)
+		for _, f := range msg.fields {
+			wire, typ := "bytes", "*pb"+f.typ
+			if scalar, ok := protoScalars[f.typ]; ok {
+				wire, typ = scalar[0], scalar[1]
+			}
+			label := "opt"
+			if f.repeated {
+				typ, label = "[]"+typ, "rep"
+				if wire == "varint" {
+					label = "rep,packed"
+				}
+			}
+			fmt.Fprintf(&buf, "\t%s %s `protobuf:\"%s,%d,%s,name=%s,proto3\"`\n", protoGoName(f.name), typ, wire, f.num, label, f.name)
+		}
+		buf.WriteString("\tXXX_unrecognized []byte\n}\n\n")
+		fmt.Fprintf(&buf, "func (m *%s) Reset() { *m = %s{} }\n", name, name)
+		fmt.Fprintf(&buf, "func (m *%s) String() string { return proto.CompactTextString(m) }\n", name)
+		fmt.Fprintf(&buf, "func (*%s) ProtoMessage() {}\n", name)
+	}
+	return format.Source(buf.Bytes())
+}
+
+// protoGoName turns a field name like call_layer into CallLayer.
+func protoGoName(name string) string {
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func TestEventsPB(t *testing.T) {
+	messages, err := parseEventsProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := generateEventsPB(messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *updateEventsPB {
+		if err := ioutil.WriteFile(eventsPBFile, code, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	have, err := ioutil.ReadFile(eventsPBFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, code) {
+		t.Errorf("%s is out of date with events.proto, run the test with -update-events-pb", eventsPBFile)
+	}
+}
+
+// fillEvent sets every exported field of v, recursing into structs, pointers
+// and slices up to a few levels of call frames.
+func fillEvent(v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if depth > 5 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fillEvent(v.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				fillEvent(v.Field(i), depth)
+			}
+		}
+	case reflect.Slice:
+		if depth > 4 {
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < v.Len(); i++ {
+			fillEvent(v.Index(i), depth+1)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillEvent(v.Index(i), depth)
+		}
+	case reflect.Uint8:
+		v.SetUint(0xab)
+	case reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(300)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(-2)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.String:
+		v.SetString("soda")
+	}
+}
+
+// protoSeen records which fields of a decoded message are set, by their path
+// from the event, and fails on fields the decoder didn't recognize.
+func protoSeen(t *testing.T, v reflect.Value, path string, seen map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			protoSeen(t, v.Elem(), path, seen)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Ptr {
+			for i := 0; i < v.Len(); i++ {
+				protoSeen(t, v.Index(i), path, seen)
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f, field := v.Type().Field(i), v.Field(i)
+			if f.Name == "XXX_unrecognized" {
+				if field.Len() > 0 {
+					t.Errorf("%s: fields unknown to events.proto or of the wrong wire type: %x", path, field.Bytes())
+				}
+				continue
+			}
+			if !reflect.DeepEqual(field.Interface(), reflect.Zero(f.Type).Interface()) {
+				seen[path+"."+f.Name] = true
+			}
+			protoSeen(t, field, path+"."+f.Name, seen)
+		}
+	}
+}
+
+// protoUnseen lists the fields below path that were never set, descending
+// depth levels of messages.
+func protoUnseen(typ reflect.Type, path string, seen map[string]bool, depth int) (unseen []string) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Name == "XXX_unrecognized" {
+			continue
+		}
+		fpath := path + "." + f.Name
+		if !seen[fpath] {
+			unseen = append(unseen, fpath)
+			continue
+		}
+		elem := f.Type
+		for elem.Kind() == reflect.Slice || elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Struct && depth > 0 {
+			unseen = append(unseen, protoUnseen(elem, fpath, seen, depth-1)...)
+		}
+	}
+	return unseen
+}
+
+func TestEventProtoSchema(t *testing.T) {
+	var (
+		payloads = reflect.TypeOf(collector.Event{})
+		seen     = make(map[string]bool)
+	)
+	// Encode an event of every kind with all its fields set
+	for i := 0; i < payloads.NumField(); i++ {
+		field := payloads.Field(i)
+		if field.PkgPath != "" || field.Type.Kind() != reflect.Ptr {
+			continue
+		}
+		ev := &collector.Event{Option: "SODA"}
+		fillEvent(reflect.ValueOf(ev).Elem().Field(i), 0)
+
+		blob, err := ev.MarshalProto()
+		if err != nil {
+			t.Fatalf("%s: %v", field.Name, err)
+		}
+		dec := new(pbEvent)
+		if err := proto.Unmarshal(blob, dec); err != nil {
+			t.Errorf("%s: can't decode with events.proto: %v", field.Name, err)
+			continue
+		}
+		protoSeen(t, reflect.ValueOf(dec), "Event", seen)
+
+		// Nothing the encoder wrote is lost in the schema types
+		enc, err := proto.Marshal(dec)
+		if err != nil {
+			t.Fatalf("%s: %v", field.Name, err)
+		}
+		if !bytes.Equal(enc, blob) {
+			t.Errorf("%s: encoding differs from events.proto:\nhave %x\nwant %x", field.Name, blob, enc)
+		}
+	}
+	if unseen := protoUnseen(reflect.TypeOf(pbEvent{}), "Event", seen, 2); len(unseen) > 0 {
+		t.Errorf("fields of events.proto the encoder never writes: %v", unseen)
+	}
+}
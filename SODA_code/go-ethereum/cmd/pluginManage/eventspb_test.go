@@ -0,0 +1,419 @@
+// Code generated by TestEventsPB from events.proto with -update-events-pb. DO NOT EDIT.
+
+package pluginManage
+
+import "github.com/golang/protobuf/proto"
+
+type pbEvent struct {
+	Version          uint64              `protobuf:"varint,1,opt,name=version,proto3"`
+	Option           string              `protobuf:"bytes,2,opt,name=option,proto3"`
+	Ins              *pbInsEvent         `protobuf:"bytes,3,opt,name=ins,proto3"`
+	TxStart          *pbTxStartEvent     `protobuf:"bytes,4,opt,name=tx_start,proto3"`
+	TxEnd            *pbTxEndEvent       `protobuf:"bytes,5,opt,name=tx_end,proto3"`
+	Message          *pbMessageEvent     `protobuf:"bytes,6,opt,name=message,proto3"`
+	Block            *pbBlockEvent       `protobuf:"bytes,7,opt,name=block,proto3"`
+	CallTree         *pbCallFrame        `protobuf:"bytes,8,opt,name=call_tree,proto3"`
+	StateDiff        *pbStateDiff        `protobuf:"bytes,9,opt,name=state_diff,proto3"`
+	Transfer         *pbBalanceTransfer  `protobuf:"bytes,10,opt,name=transfer,proto3"`
+	TokenTransfer    *pbTokenTransfer    `protobuf:"bytes,11,opt,name=token_transfer,proto3"`
+	Precompile       *pbPrecompileCall   `protobuf:"bytes,12,opt,name=precompile,proto3"`
+	Creation         *pbContractCreation `protobuf:"bytes,13,opt,name=creation,proto3"`
+	Branch           *pbBranch           `protobuf:"bytes,14,opt,name=branch,proto3"`
+	BasicBlock       *pbBasicBlock       `protobuf:"bytes,15,opt,name=basic_block,proto3"`
+	Memory           *pbMemoryAccess     `protobuf:"bytes,16,opt,name=memory,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbEvent) Reset()         { *m = pbEvent{} }
+func (m *pbEvent) String() string { return proto.CompactTextString(m) }
+func (*pbEvent) ProtoMessage()    {}
+
+type pbInsEvent struct {
+	OpName              string         `protobuf:"bytes,1,opt,name=op_name,proto3"`
+	Pc                  uint64         `protobuf:"varint,2,opt,name=pc,proto3"`
+	PcNext              uint64         `protobuf:"varint,3,opt,name=pc_next,proto3"`
+	CallLayer           int64          `protobuf:"varint,4,opt,name=call_layer,proto3"`
+	From                []byte         `protobuf:"bytes,5,opt,name=from,proto3"`
+	To                  []byte         `protobuf:"bytes,6,opt,name=to,proto3"`
+	CallContract        []byte         `protobuf:"bytes,7,opt,name=call_contract,proto3"`
+	Value               []byte         `protobuf:"bytes,8,opt,name=value,proto3"`
+	Args                [][]byte       `protobuf:"bytes,9,rep,name=args,proto3"`
+	Result              []byte         `protobuf:"bytes,10,opt,name=result,proto3"`
+	HasResult           bool           `protobuf:"varint,11,opt,name=has_result,proto3"`
+	RetArgs             []byte         `protobuf:"bytes,12,opt,name=ret_args,proto3"`
+	InputData           []byte         `protobuf:"bytes,13,opt,name=input_data,proto3"`
+	ByteCode            []byte         `protobuf:"bytes,14,opt,name=byte_code,proto3"`
+	MemoryData          []byte         `protobuf:"bytes,15,opt,name=memory_data,proto3"`
+	PreValue            []byte         `protobuf:"bytes,16,opt,name=pre_value,proto3"`
+	CurrentValue        []byte         `protobuf:"bytes,17,opt,name=current_value,proto3"`
+	AllocatedGas        uint64         `protobuf:"varint,18,opt,name=allocated_gas,proto3"`
+	RealGasUsed         uint64         `protobuf:"varint,19,opt,name=real_gas_used,proto3"`
+	InternalErr         string         `protobuf:"bytes,20,opt,name=internal_err,proto3"`
+	IsInternalSucceeded bool           `protobuf:"varint,21,opt,name=is_internal_succeeded,proto3"`
+	IsCallValid         bool           `protobuf:"varint,22,opt,name=is_call_valid,proto3"`
+	ArgTaint            []uint32       `protobuf:"varint,23,rep,packed,name=arg_taint,proto3"`
+	Decoded             *pbDecodedCall `protobuf:"bytes,24,opt,name=decoded,proto3"`
+	Failure             string         `protobuf:"bytes,25,opt,name=failure,proto3"`
+	RevertReason        string         `protobuf:"bytes,26,opt,name=revert_reason,proto3"`
+	FrameType           string         `protobuf:"bytes,27,opt,name=frame_type,proto3"`
+	CodeAddress         []byte         `protobuf:"bytes,28,opt,name=code_address,proto3"`
+	StorageAddress      []byte         `protobuf:"bytes,29,opt,name=storage_address,proto3"`
+	Sender              []byte         `protobuf:"bytes,30,opt,name=sender,proto3"`
+	CallValue           []byte         `protobuf:"bytes,31,opt,name=call_value,proto3"`
+	Slot                *pbSlotPath    `protobuf:"bytes,32,opt,name=slot,proto3"`
+	GasBefore           uint64         `protobuf:"varint,33,opt,name=gas_before,proto3"`
+	StaticGas           uint64         `protobuf:"varint,34,opt,name=static_gas,proto3"`
+	DynamicGas          uint64         `protobuf:"varint,35,opt,name=dynamic_gas,proto3"`
+	GasForwarded        uint64         `protobuf:"varint,36,opt,name=gas_forwarded,proto3"`
+	GasReturned         uint64         `protobuf:"varint,37,opt,name=gas_returned,proto3"`
+	RefundBefore        uint64         `protobuf:"varint,38,opt,name=refund_before,proto3"`
+	RefundAfter         uint64         `protobuf:"varint,39,opt,name=refund_after,proto3"`
+	XXX_unrecognized    []byte
+}
+
+func (m *pbInsEvent) Reset()         { *m = pbInsEvent{} }
+func (m *pbInsEvent) String() string { return proto.CompactTextString(m) }
+func (*pbInsEvent) ProtoMessage()    {}
+
+type pbTxStartEvent struct {
+	TxHash           []byte         `protobuf:"bytes,1,opt,name=tx_hash,proto3"`
+	BlockNumber      uint64         `protobuf:"varint,2,opt,name=block_number,proto3"`
+	BlockTime        uint64         `protobuf:"varint,3,opt,name=block_time,proto3"`
+	From             []byte         `protobuf:"bytes,4,opt,name=from,proto3"`
+	To               []byte         `protobuf:"bytes,5,opt,name=to,proto3"`
+	Create           bool           `protobuf:"varint,6,opt,name=create,proto3"`
+	Value            []byte         `protobuf:"bytes,7,opt,name=value,proto3"`
+	GasPrice         []byte         `protobuf:"bytes,8,opt,name=gas_price,proto3"`
+	GasLimit         uint64         `protobuf:"varint,9,opt,name=gas_limit,proto3"`
+	Nonce            uint64         `protobuf:"varint,10,opt,name=nonce,proto3"`
+	Input            []byte         `protobuf:"bytes,11,opt,name=input,proto3"`
+	Code             []byte         `protobuf:"bytes,12,opt,name=code,proto3"`
+	Decoded          *pbDecodedCall `protobuf:"bytes,13,opt,name=decoded,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbTxStartEvent) Reset()         { *m = pbTxStartEvent{} }
+func (m *pbTxStartEvent) String() string { return proto.CompactTextString(m) }
+func (*pbTxStartEvent) ProtoMessage()    {}
+
+type pbTxEndEvent struct {
+	TxHash            []byte   `protobuf:"bytes,1,opt,name=tx_hash,proto3"`
+	GasUsed           uint64   `protobuf:"varint,2,opt,name=gas_used,proto3"`
+	Success           bool     `protobuf:"varint,3,opt,name=success,proto3"`
+	Create            bool     `protobuf:"varint,4,opt,name=create,proto3"`
+	Contract          []byte   `protobuf:"bytes,5,opt,name=contract,proto3"`
+	DeployCode        []byte   `protobuf:"bytes,6,opt,name=deploy_code,proto3"`
+	RuntimeCode       []byte   `protobuf:"bytes,7,opt,name=runtime_code,proto3"`
+	Failure           string   `protobuf:"bytes,8,opt,name=failure,proto3"`
+	RevertReason      string   `protobuf:"bytes,9,opt,name=revert_reason,proto3"`
+	From              []byte   `protobuf:"bytes,10,opt,name=from,proto3"`
+	To                []byte   `protobuf:"bytes,11,opt,name=to,proto3"`
+	Value             []byte   `protobuf:"bytes,12,opt,name=value,proto3"`
+	Input             []byte   `protobuf:"bytes,13,opt,name=input,proto3"`
+	BlockNumber       uint64   `protobuf:"varint,14,opt,name=block_number,proto3"`
+	TxIndex           uint64   `protobuf:"varint,15,opt,name=tx_index,proto3"`
+	Status            uint64   `protobuf:"varint,16,opt,name=status,proto3"`
+	PostState         []byte   `protobuf:"bytes,17,opt,name=post_state,proto3"`
+	CumulativeGasUsed uint64   `protobuf:"varint,18,opt,name=cumulative_gas_used,proto3"`
+	Bloom             []byte   `protobuf:"bytes,19,opt,name=bloom,proto3"`
+	Logs              []*pbLog `protobuf:"bytes,20,rep,name=logs,proto3"`
+	XXX_unrecognized  []byte
+}
+
+func (m *pbTxEndEvent) Reset()         { *m = pbTxEndEvent{} }
+func (m *pbTxEndEvent) String() string { return proto.CompactTextString(m) }
+func (*pbTxEndEvent) ProtoMessage()    {}
+
+type pbLog struct {
+	Address          []byte         `protobuf:"bytes,1,opt,name=address,proto3"`
+	Topics           [][]byte       `protobuf:"bytes,2,rep,name=topics,proto3"`
+	Data             []byte         `protobuf:"bytes,3,opt,name=data,proto3"`
+	Index            uint64         `protobuf:"varint,4,opt,name=index,proto3"`
+	Decoded          *pbDecodedCall `protobuf:"bytes,5,opt,name=decoded,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbLog) Reset()         { *m = pbLog{} }
+func (m *pbLog) String() string { return proto.CompactTextString(m) }
+func (*pbLog) ProtoMessage()    {}
+
+type pbMessageEvent struct {
+	Type             string         `protobuf:"bytes,1,opt,name=type,proto3"`
+	Pc               uint64         `protobuf:"varint,2,opt,name=pc,proto3"`
+	CallLayer        uint64         `protobuf:"varint,3,opt,name=call_layer,proto3"`
+	From             []byte         `protobuf:"bytes,4,opt,name=from,proto3"`
+	To               []byte         `protobuf:"bytes,5,opt,name=to,proto3"`
+	Value            []byte         `protobuf:"bytes,6,opt,name=value,proto3"`
+	Input            []byte         `protobuf:"bytes,7,opt,name=input,proto3"`
+	Code             []byte         `protobuf:"bytes,8,opt,name=code,proto3"`
+	Success          bool           `protobuf:"varint,9,opt,name=success,proto3"`
+	Decoded          *pbDecodedCall `protobuf:"bytes,10,opt,name=decoded,proto3"`
+	Failure          string         `protobuf:"bytes,11,opt,name=failure,proto3"`
+	RevertReason     string         `protobuf:"bytes,12,opt,name=revert_reason,proto3"`
+	Salt             []byte         `protobuf:"bytes,13,opt,name=salt,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbMessageEvent) Reset()         { *m = pbMessageEvent{} }
+func (m *pbMessageEvent) String() string { return proto.CompactTextString(m) }
+func (*pbMessageEvent) ProtoMessage()    {}
+
+type pbBlockEvent struct {
+	Number           uint64 `protobuf:"varint,1,opt,name=number,proto3"`
+	ParentHash       []byte `protobuf:"bytes,2,opt,name=parent_hash,proto3"`
+	UncleHash        []byte `protobuf:"bytes,3,opt,name=uncle_hash,proto3"`
+	Coinbase         []byte `protobuf:"bytes,4,opt,name=coinbase,proto3"`
+	StateRoot        []byte `protobuf:"bytes,5,opt,name=state_root,proto3"`
+	TxHashRoot       []byte `protobuf:"bytes,6,opt,name=tx_hash_root,proto3"`
+	ReceiptHash      []byte `protobuf:"bytes,7,opt,name=receipt_hash,proto3"`
+	Bloom            []byte `protobuf:"bytes,8,opt,name=bloom,proto3"`
+	Difficulty       []byte `protobuf:"bytes,9,opt,name=difficulty,proto3"`
+	GasLimit         uint64 `protobuf:"varint,10,opt,name=gas_limit,proto3"`
+	GasUsed          uint64 `protobuf:"varint,11,opt,name=gas_used,proto3"`
+	Time             uint64 `protobuf:"varint,12,opt,name=time,proto3"`
+	Extra            []byte `protobuf:"bytes,13,opt,name=extra,proto3"`
+	MixDigest        []byte `protobuf:"bytes,14,opt,name=mix_digest,proto3"`
+	Nonce            uint64 `protobuf:"varint,15,opt,name=nonce,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbBlockEvent) Reset()         { *m = pbBlockEvent{} }
+func (m *pbBlockEvent) String() string { return proto.CompactTextString(m) }
+func (*pbBlockEvent) ProtoMessage()    {}
+
+type pbCallFrame struct {
+	Type             string         `protobuf:"bytes,1,opt,name=type,proto3"`
+	CallLayer        uint64         `protobuf:"varint,2,opt,name=call_layer,proto3"`
+	Depth            uint64         `protobuf:"varint,3,opt,name=depth,proto3"`
+	Caller           []byte         `protobuf:"bytes,4,opt,name=caller,proto3"`
+	Callee           []byte         `protobuf:"bytes,5,opt,name=callee,proto3"`
+	CodeAddress      []byte         `protobuf:"bytes,6,opt,name=code_address,proto3"`
+	Value            []byte         `protobuf:"bytes,7,opt,name=value,proto3"`
+	Input            []byte         `protobuf:"bytes,8,opt,name=input,proto3"`
+	Output           []byte         `protobuf:"bytes,9,opt,name=output,proto3"`
+	Gas              uint64         `protobuf:"varint,10,opt,name=gas,proto3"`
+	GasUsed          uint64         `protobuf:"varint,11,opt,name=gas_used,proto3"`
+	Success          bool           `protobuf:"varint,12,opt,name=success,proto3"`
+	Reverted         bool           `protobuf:"varint,13,opt,name=reverted,proto3"`
+	Children         []*pbCallFrame `protobuf:"bytes,14,rep,name=children,proto3"`
+	Failure          string         `protobuf:"bytes,15,opt,name=failure,proto3"`
+	RevertReason     string         `protobuf:"bytes,16,opt,name=revert_reason,proto3"`
+	GasSelf          uint64         `protobuf:"varint,17,opt,name=gas_self,proto3"`
+	RefundAdded      uint64         `protobuf:"varint,18,opt,name=refund_added,proto3"`
+	RefundRemoved    uint64         `protobuf:"varint,19,opt,name=refund_removed,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbCallFrame) Reset()         { *m = pbCallFrame{} }
+func (m *pbCallFrame) String() string { return proto.CompactTextString(m) }
+func (*pbCallFrame) ProtoMessage()    {}
+
+type pbStateDiff struct {
+	TxHash           []byte           `protobuf:"bytes,1,opt,name=tx_hash,proto3"`
+	Accounts         []*pbAccountDiff `protobuf:"bytes,2,rep,name=accounts,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbStateDiff) Reset()         { *m = pbStateDiff{} }
+func (m *pbStateDiff) String() string { return proto.CompactTextString(m) }
+func (*pbStateDiff) ProtoMessage()    {}
+
+type pbAccountDiff struct {
+	Address          []byte           `protobuf:"bytes,1,opt,name=address,proto3"`
+	Created          bool             `protobuf:"varint,2,opt,name=created,proto3"`
+	Suicided         bool             `protobuf:"varint,3,opt,name=suicided,proto3"`
+	BalanceChanged   bool             `protobuf:"varint,4,opt,name=balance_changed,proto3"`
+	PreBalance       []byte           `protobuf:"bytes,5,opt,name=pre_balance,proto3"`
+	PostBalance      []byte           `protobuf:"bytes,6,opt,name=post_balance,proto3"`
+	BalanceFrame     uint64           `protobuf:"varint,7,opt,name=balance_frame,proto3"`
+	NonceChanged     bool             `protobuf:"varint,8,opt,name=nonce_changed,proto3"`
+	PreNonce         uint64           `protobuf:"varint,9,opt,name=pre_nonce,proto3"`
+	PostNonce        uint64           `protobuf:"varint,10,opt,name=post_nonce,proto3"`
+	NonceFrame       uint64           `protobuf:"varint,11,opt,name=nonce_frame,proto3"`
+	CodeChanged      bool             `protobuf:"varint,12,opt,name=code_changed,proto3"`
+	PreCode          []byte           `protobuf:"bytes,13,opt,name=pre_code,proto3"`
+	PostCode         []byte           `protobuf:"bytes,14,opt,name=post_code,proto3"`
+	CodeFrame        uint64           `protobuf:"varint,15,opt,name=code_frame,proto3"`
+	Storage          []*pbStorageDiff `protobuf:"bytes,16,rep,name=storage,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbAccountDiff) Reset()         { *m = pbAccountDiff{} }
+func (m *pbAccountDiff) String() string { return proto.CompactTextString(m) }
+func (*pbAccountDiff) ProtoMessage()    {}
+
+type pbStorageDiff struct {
+	Key              []byte `protobuf:"bytes,1,opt,name=key,proto3"`
+	Pre              []byte `protobuf:"bytes,2,opt,name=pre,proto3"`
+	Post             []byte `protobuf:"bytes,3,opt,name=post,proto3"`
+	Frame            uint64 `protobuf:"varint,4,opt,name=frame,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbStorageDiff) Reset()         { *m = pbStorageDiff{} }
+func (m *pbStorageDiff) String() string { return proto.CompactTextString(m) }
+func (*pbStorageDiff) ProtoMessage()    {}
+
+type pbBalanceTransfer struct {
+	Reason           string `protobuf:"bytes,1,opt,name=reason,proto3"`
+	TxHash           []byte `protobuf:"bytes,2,opt,name=tx_hash,proto3"`
+	BlockNumber      uint64 `protobuf:"varint,3,opt,name=block_number,proto3"`
+	From             []byte `protobuf:"bytes,4,opt,name=from,proto3"`
+	To               []byte `protobuf:"bytes,5,opt,name=to,proto3"`
+	Amount           []byte `protobuf:"bytes,6,opt,name=amount,proto3"`
+	CallLayer        uint64 `protobuf:"varint,7,opt,name=call_layer,proto3"`
+	Reverted         bool   `protobuf:"varint,8,opt,name=reverted,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbBalanceTransfer) Reset()         { *m = pbBalanceTransfer{} }
+func (m *pbBalanceTransfer) String() string { return proto.CompactTextString(m) }
+func (*pbBalanceTransfer) ProtoMessage()    {}
+
+type pbTokenTransfer struct {
+	Action           string           `protobuf:"bytes,1,opt,name=action,proto3"`
+	Standard         string           `protobuf:"bytes,2,opt,name=standard,proto3"`
+	Token            []byte           `protobuf:"bytes,3,opt,name=token,proto3"`
+	TxHash           []byte           `protobuf:"bytes,4,opt,name=tx_hash,proto3"`
+	CallLayer        uint64           `protobuf:"varint,5,opt,name=call_layer,proto3"`
+	Method           string           `protobuf:"bytes,6,opt,name=method,proto3"`
+	From             []byte           `protobuf:"bytes,7,opt,name=from,proto3"`
+	To               []byte           `protobuf:"bytes,8,opt,name=to,proto3"`
+	Amount           []byte           `protobuf:"bytes,9,opt,name=amount,proto3"`
+	FromCall         bool             `protobuf:"varint,10,opt,name=from_call,proto3"`
+	FromLog          bool             `protobuf:"varint,11,opt,name=from_log,proto3"`
+	Consistent       bool             `protobuf:"varint,12,opt,name=consistent,proto3"`
+	Slots            []*pbStorageDiff `protobuf:"bytes,13,rep,name=slots,proto3"`
+	Reverted         bool             `protobuf:"varint,14,opt,name=reverted,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbTokenTransfer) Reset()         { *m = pbTokenTransfer{} }
+func (m *pbTokenTransfer) String() string { return proto.CompactTextString(m) }
+func (*pbTokenTransfer) ProtoMessage()    {}
+
+type pbPrecompileCall struct {
+	Name             string         `protobuf:"bytes,1,opt,name=name,proto3"`
+	Address          []byte         `protobuf:"bytes,2,opt,name=address,proto3"`
+	TxHash           []byte         `protobuf:"bytes,3,opt,name=tx_hash,proto3"`
+	CallLayer        uint64         `protobuf:"varint,4,opt,name=call_layer,proto3"`
+	Caller           []byte         `protobuf:"bytes,5,opt,name=caller,proto3"`
+	Input            []byte         `protobuf:"bytes,6,opt,name=input,proto3"`
+	Output           []byte         `protobuf:"bytes,7,opt,name=output,proto3"`
+	Gas              uint64         `protobuf:"varint,8,opt,name=gas,proto3"`
+	Failure          string         `protobuf:"bytes,9,opt,name=failure,proto3"`
+	Decoded          *pbDecodedCall `protobuf:"bytes,10,opt,name=decoded,proto3"`
+	Signer           []byte         `protobuf:"bytes,11,opt,name=signer,proto3"`
+	HighS            bool           `protobuf:"varint,12,opt,name=high_s,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbPrecompileCall) Reset()         { *m = pbPrecompileCall{} }
+func (m *pbPrecompileCall) String() string { return proto.CompactTextString(m) }
+func (*pbPrecompileCall) ProtoMessage()    {}
+
+type pbContractCreation struct {
+	Type             string `protobuf:"bytes,1,opt,name=type,proto3"`
+	TxHash           []byte `protobuf:"bytes,2,opt,name=tx_hash,proto3"`
+	BlockNumber      uint64 `protobuf:"varint,3,opt,name=block_number,proto3"`
+	Creator          []byte `protobuf:"bytes,4,opt,name=creator,proto3"`
+	Deployer         []byte `protobuf:"bytes,5,opt,name=deployer,proto3"`
+	Address          []byte `protobuf:"bytes,6,opt,name=address,proto3"`
+	Salt             []byte `protobuf:"bytes,7,opt,name=salt,proto3"`
+	InitCodeHash     []byte `protobuf:"bytes,8,opt,name=init_code_hash,proto3"`
+	RuntimeCode      []byte `protobuf:"bytes,9,opt,name=runtime_code,proto3"`
+	Value            []byte `protobuf:"bytes,10,opt,name=value,proto3"`
+	Depth            uint64 `protobuf:"varint,11,opt,name=depth,proto3"`
+	CallLayer        uint64 `protobuf:"varint,12,opt,name=call_layer,proto3"`
+	Success          bool   `protobuf:"varint,13,opt,name=success,proto3"`
+	Failure          string `protobuf:"bytes,14,opt,name=failure,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbContractCreation) Reset()         { *m = pbContractCreation{} }
+func (m *pbContractCreation) String() string { return proto.CompactTextString(m) }
+func (*pbContractCreation) ProtoMessage()    {}
+
+type pbSlotPath struct {
+	Kind             string      `protobuf:"bytes,1,opt,name=kind,proto3"`
+	Slot             []byte      `protobuf:"bytes,2,opt,name=slot,proto3"`
+	Base             *pbSlotPath `protobuf:"bytes,3,opt,name=base,proto3"`
+	Key              []byte      `protobuf:"bytes,4,opt,name=key,proto3"`
+	Index            uint64      `protobuf:"varint,5,opt,name=index,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbSlotPath) Reset()         { *m = pbSlotPath{} }
+func (m *pbSlotPath) String() string { return proto.CompactTextString(m) }
+func (*pbSlotPath) ProtoMessage()    {}
+
+type pbBranch struct {
+	Op               string `protobuf:"bytes,1,opt,name=op,proto3"`
+	TxHash           []byte `protobuf:"bytes,2,opt,name=tx_hash,proto3"`
+	CallLayer        uint64 `protobuf:"varint,3,opt,name=call_layer,proto3"`
+	CodeAddress      []byte `protobuf:"bytes,4,opt,name=code_address,proto3"`
+	CodeHash         []byte `protobuf:"bytes,5,opt,name=code_hash,proto3"`
+	Pc               uint64 `protobuf:"varint,6,opt,name=pc,proto3"`
+	Dest             []byte `protobuf:"bytes,7,opt,name=dest,proto3"`
+	Condition        []byte `protobuf:"bytes,8,opt,name=condition,proto3"`
+	Taken            bool   `protobuf:"varint,9,opt,name=taken,proto3"`
+	Next             uint64 `protobuf:"varint,10,opt,name=next,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbBranch) Reset()         { *m = pbBranch{} }
+func (m *pbBranch) String() string { return proto.CompactTextString(m) }
+func (*pbBranch) ProtoMessage()    {}
+
+type pbBasicBlock struct {
+	TxHash           []byte `protobuf:"bytes,1,opt,name=tx_hash,proto3"`
+	CallLayer        uint64 `protobuf:"varint,2,opt,name=call_layer,proto3"`
+	CodeAddress      []byte `protobuf:"bytes,3,opt,name=code_address,proto3"`
+	CodeHash         []byte `protobuf:"bytes,4,opt,name=code_hash,proto3"`
+	Start            uint64 `protobuf:"varint,5,opt,name=start,proto3"`
+	End              uint64 `protobuf:"varint,6,opt,name=end,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbBasicBlock) Reset()         { *m = pbBasicBlock{} }
+func (m *pbBasicBlock) String() string { return proto.CompactTextString(m) }
+func (*pbBasicBlock) ProtoMessage()    {}
+
+type pbMemoryAccess struct {
+	Op               string `protobuf:"bytes,1,opt,name=op,proto3"`
+	Direction        string `protobuf:"bytes,2,opt,name=direction,proto3"`
+	TxHash           []byte `protobuf:"bytes,3,opt,name=tx_hash,proto3"`
+	CallLayer        uint64 `protobuf:"varint,4,opt,name=call_layer,proto3"`
+	CodeAddress      []byte `protobuf:"bytes,5,opt,name=code_address,proto3"`
+	Pc               uint64 `protobuf:"varint,6,opt,name=pc,proto3"`
+	Offset           uint64 `protobuf:"varint,7,opt,name=offset,proto3"`
+	Length           uint64 `protobuf:"varint,8,opt,name=length,proto3"`
+	Data             []byte `protobuf:"bytes,9,opt,name=data,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbMemoryAccess) Reset()         { *m = pbMemoryAccess{} }
+func (m *pbMemoryAccess) String() string { return proto.CompactTextString(m) }
+func (*pbMemoryAccess) ProtoMessage()    {}
+
+type pbDecodedCall struct {
+	Signature        string          `protobuf:"bytes,1,opt,name=signature,proto3"`
+	Name             string          `protobuf:"bytes,2,opt,name=name,proto3"`
+	Args             []*pbDecodedArg `protobuf:"bytes,3,rep,name=args,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbDecodedCall) Reset()         { *m = pbDecodedCall{} }
+func (m *pbDecodedCall) String() string { return proto.CompactTextString(m) }
+func (*pbDecodedCall) ProtoMessage()    {}
+
+type pbDecodedArg struct {
+	Name             string `protobuf:"bytes,1,opt,name=name,proto3"`
+	Type             string `protobuf:"bytes,2,opt,name=type,proto3"`
+	Value            string `protobuf:"bytes,3,opt,name=value,proto3"`
+	XXX_unrecognized []byte
+}
+
+func (m *pbDecodedArg) Reset()         { *m = pbDecodedArg{} }
+func (m *pbDecodedArg) String() string { return proto.CompactTextString(m) }
+func (*pbDecodedArg) ProtoMessage()    {}
@@ -0,0 +1,137 @@
+package pluginManage
+
+//add new file
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Layouts is the storage layout the interpreter learns from the explained
+// slots of SLOAD and SSTORE events, keyed by the hash of the code accessing
+// the storage. It keeps the maxLayouts codes learned most recently.
+var Layouts = NewLayoutDB()
+
+// Bounds of a LayoutDB, so the layouts of a long running node don't grow
+// without limit.
+const (
+	maxLayouts   = 4096 // codes, the first learned is evicted first
+	maxVariables = 256  // variables per code, later ones are ignored
+)
+
+// StorageVariable is a state variable as seen from the slots its code
+// accessed.
+type StorageVariable struct {
+	Slot   collector.Word `json:"slot"`   // fixed slot the variable is declared at
+	Type   string         `json:"type"`   // value, or the nesting of mappings and arrays, e.g. mapping=>array
+	Fields uint64         `json:"fields"` // slots used by one mapping entry or array element, at least
+	Reads  uint64         `json:"reads"`
+	Writes uint64         `json:"writes"`
+}
+
+// LayoutDB collects the storage variables of contract codes. It is safe for
+// concurrent use.
+type LayoutDB struct {
+	lock    sync.Mutex
+	layouts map[common.Hash]map[string]*StorageVariable
+	order   []common.Hash // codes in the order they were first learned
+}
+
+// NewLayoutDB returns an empty layout database.
+func NewLayoutDB() *LayoutDB {
+	return &LayoutDB{layouts: make(map[common.Hash]map[string]*StorageVariable)}
+}
+
+// Learn records an access of the code to the slot. Slots that can't be
+// traced back to a fixed slot are ignored.
+func (db *LayoutDB) Learn(codeHash common.Hash, path *collector.SlotPath, write bool) {
+	root := path.Root()
+	if root.Kind != collector.SlotFixed {
+		return
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	layout := db.layouts[codeHash]
+	if layout == nil {
+		if len(db.order) >= maxLayouts {
+			delete(db.layouts, db.order[0])
+			db.order = db.order[1:]
+		}
+		layout = make(map[string]*StorageVariable)
+		db.layouts[codeHash] = layout
+		db.order = append(db.order, codeHash)
+	}
+	typ := slotType(path)
+	v := layout[variableKey(root, typ)]
+	if v == nil {
+		if len(layout) >= maxVariables {
+			return
+		}
+		v = &StorageVariable{Slot: root.Slot, Type: typ}
+		layout[variableKey(root, typ)] = v
+	}
+	if path.Base != nil && path.Index+1 > v.Fields {
+		v.Fields = path.Index + 1
+	}
+	if write {
+		v.Writes++
+	} else {
+		v.Reads++
+	}
+}
+
+// Layout returns copies of the variables learned for the code, ordered by
+// slot.
+func (db *LayoutDB) Layout(codeHash common.Hash) []*StorageVariable {
+	db.lock.Lock()
+	vars := make([]*StorageVariable, 0, len(db.layouts[codeHash]))
+	for _, v := range db.layouts[codeHash] {
+		cpy := *v
+		vars = append(vars, &cpy)
+	}
+	db.lock.Unlock()
+
+	sort.Slice(vars, func(i, j int) bool {
+		if c := bytes.Compare(vars[i].Slot[:], vars[j].Slot[:]); c != 0 {
+			return c < 0
+		}
+		return vars[i].Type < vars[j].Type
+	})
+	return vars
+}
+
+// Variable returns a copy of the learned variable the slot belongs to, nil
+// if the code never accessed it.
+func (db *LayoutDB) Variable(codeHash common.Hash, path *collector.SlotPath) *StorageVariable {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	v := db.layouts[codeHash][variableKey(path.Root(), slotType(path))]
+	if v == nil {
+		return nil
+	}
+	cpy := *v
+	return &cpy
+}
+
+// slotType names the nesting of mappings and arrays leading to the slot.
+func slotType(path *collector.SlotPath) string {
+	var kinds []string
+	for p := path; p.Base != nil; p = p.Base {
+		kinds = append([]string{strings.ToLower(p.Kind)}, kinds...)
+	}
+	if len(kinds) == 0 {
+		return "value"
+	}
+	return strings.Join(kinds, "=>")
+}
+
+func variableKey(root *collector.SlotPath, typ string) string {
+	return root.Slot.Hex() + "/" + typ
+}
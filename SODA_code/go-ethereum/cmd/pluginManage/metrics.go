@@ -0,0 +1,73 @@
+package pluginManage
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// pluginMetrics are the meters shared by every handler of a plugin. They are
+// registered in metrics.DefaultRegistry as soda/<plugin>/..., so the usual
+// exporters pick them up once geth runs with --metrics.
+type pluginMetrics struct {
+	enabled  metrics.Gauge // 1 while the plugin is registered
+	warnings metrics.Meter // handler results reported as warnings
+	serious  metrics.Meter // handler results reported as serious, disabling the plugin
+	panics   metrics.Meter // handler calls that panicked
+	skipped  metrics.Meter // events skipped because the plugin was disabled
+	queue    metrics.Gauge // events waiting for the plugin, always 0
+	dropped  metrics.Meter // events lost before reaching the plugin, always 0
+}
+
+// eventMetrics are the meters of a single plugin handler for a single event,
+// registered as soda/<plugin>/<event>/....
+type eventMetrics struct {
+	plugin  *pluginMetrics
+	calls   metrics.Meter // handler invocations
+	latency metrics.Timer // handler latency
+}
+
+// newPluginMetrics registers the meters of a plugin. Handlers run
+// synchronously in the interpreter loop, so no event ever waits for a plugin
+// or gets lost: the queue depth and the dropped events stay at zero and only
+// exist for dashboards watching them. Events a disabled plugin doesn't get
+// are counted as skipped.
+func newPluginMetrics(plugin string) *pluginMetrics {
+	prefix := "soda/" + plugin + "/"
+	return &pluginMetrics{
+		enabled:  metrics.GetOrRegisterGauge(prefix+"enabled", nil),
+		warnings: metrics.GetOrRegisterMeter(prefix+"alerts/warning", nil),
+		serious:  metrics.GetOrRegisterMeter(prefix+"alerts/serious", nil),
+		panics:   metrics.GetOrRegisterMeter(prefix+"panics", nil),
+		skipped:  metrics.GetOrRegisterMeter(prefix+"skipped", nil),
+		queue:    metrics.GetOrRegisterGauge(prefix+"queue", nil),
+		dropped:  metrics.GetOrRegisterMeter(prefix+"dropped", nil),
+	}
+}
+
+func newEventMetrics(plugin *pluginMetrics, name, event string) *eventMetrics {
+	prefix := "soda/" + name + "/" + event + "/"
+	return &eventMetrics{
+		plugin:  plugin,
+		calls:   metrics.GetOrRegisterMeter(prefix+"calls", nil),
+		latency: metrics.GetOrRegisterTimer(prefix+"latency", nil),
+	}
+}
+
+// begin marks the start of a handler call and returns its start time. The
+// clock is only read if metrics are enabled.
+func (m *eventMetrics) begin() time.Time {
+	if !metrics.Enabled {
+		return time.Time{}
+	}
+	m.calls.Mark(1)
+	return time.Now()
+}
+
+// end marks the end of a handler call started at start.
+func (m *eventMetrics) end(start time.Time) {
+	if !metrics.Enabled {
+		return
+	}
+	m.latency.UpdateSince(start)
+}
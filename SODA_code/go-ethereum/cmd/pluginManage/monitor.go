@@ -3,7 +3,7 @@ package pluginManage
 import (
 	"github.com/ethereum/collector"
 	"os"
-	// "fmt"
+	"fmt"
 )
 
 
@@ -11,13 +11,24 @@ import (
 
 type SendFuncType func(*collector.AllCollector) (byte,string)
 
+// EventFuncType is the handler signature for plugins consuming the binary
+// collector events instead of the legacy string view.
+type EventFuncType func(*collector.Event) (byte,string)
+
+// HostFuncType is the handler signature for plugins that also read the chain
+// state through the host.
+type HostFuncType func(*collector.Event, collector.Host) (byte,string)
+
 type MonitorType struct {
 	Status 		bool
 	SendFunc 	SendFuncType
+	EventFunc 	EventFuncType
+	HostFunc 	HostFuncType
 	Opcode 		string
 	Logger 		*WarnTxLog
 	IAL_Optinon	string
 	PluginName 	string
+	Taint		bool	// whether the plugin reads the taint of instruction arguments
 }
 
 func (m *MonitorType) SetStatus(Status bool) {
@@ -33,8 +44,40 @@ func (m *MonitorType) SetSendFunc(SendFunc SendFuncType) {
 func (m *MonitorType) GetSendFunc() SendFuncType {
 	return m.SendFunc
 }
-func (m *MonitorType) Send(data *collector.AllCollector) (byte,string) {
-	return m.SendFunc(data)
+func (m *MonitorType) SetEventFunc(EventFunc EventFuncType) {
+	m.EventFunc = EventFunc
+}
+func (m *MonitorType) GetEventFunc() EventFuncType {
+	return m.EventFunc
+}
+func (m *MonitorType) SetHostFunc(HostFunc HostFuncType) {
+	m.HostFunc = HostFunc
+}
+func (m *MonitorType) GetHostFunc() HostFuncType {
+	return m.HostFunc
+}
+
+// Send hands the event to the plugin, rendering the legacy string view only
+// for handlers that still take an AllCollector.
+func (m *MonitorType) Send(data *collector.Event, host collector.Host) (byte,string) {
+	if m.HostFunc != nil {
+		return m.HostFunc(data, host)
+	}
+	if m.EventFunc != nil {
+		return m.EventFunc(data)
+	}
+	return m.SendFunc(data.Compat())
+}
+
+// trySend is Send, turning a panic inside the plugin into an error.
+func (m *MonitorType) trySend(data *collector.Event, host collector.Host) (level byte, result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	level, result = m.Send(data, host)
+	return level, result, nil
 }
 
 func (m *MonitorType) SetOpcode(Opcode string) {
@@ -51,6 +94,13 @@ func (m *MonitorType) GetIAL_Optinon() string {
 	return m.IAL_Optinon
 }
 
+func (m *MonitorType) SetTaint(Taint bool) {
+	m.Taint = Taint
+}
+func (m *MonitorType) GetTaint() bool {
+	return m.Taint
+}
+
 func (m *MonitorType) SetPluginName(PluginName string) {
 	m.PluginName = PluginName
 }
@@ -61,10 +111,10 @@ func (m *MonitorType) GetPluginName() string {
 
 func (m *MonitorType) SetLogger(FileName string) {
 	m.Logger = NewPluginLogger()
-	filepath := "./plugin_log/" + FileName + "datalog/" +FileName+"datalog" 
+	filepath := LogDir + "/" + FileName + "datalog/" +FileName+"datalog" 
 	m.Logger.InitialFileLog(filepath)
 
-	logpath := "./plugin_log/" + FileName + "datalog"
+	logpath := LogDir + "/" + FileName + "datalog"
 	// fmt.Println("Data log path:",logpath)
 	_,err_1 := os.Stat(logpath)
 	// fmt.Println(err_1)
@@ -5,7 +5,7 @@ package pluginManage
 import (
 	"github.com/ethereum/collector"
 	"strings"
-	// "fmt"
+	"fmt"
 	"github.com/ethereum/go-ethereum/tingrong"
 	"github.com/ethereum/go-ethereum/fei"
 )
@@ -14,13 +14,119 @@ import (
 
 type PluginManages struct {
 	plugins map[string][]*MonitorType
-	
+
+	// Compiled from plugins on every (un)registration so that the interpreter
+	// never touches the string map while executing.
+	table   [opcodeCount + int(numEvents)][]*MonitorType // subscribers per opcode and synthetic event
+	stats   [opcodeCount + int(numEvents)][]*eventMetrics // meters of every subscriber in table
+	collect [opcodeCount]bool                            // opcodes the interpreter has to collect
+	send    [opcodeCount]int                             // table slot a collected opcode is dispatched to
+	events  uint64                                       // bitmask of subscribed synthetic events
+	active  bool                                         // whether anything is subscribed at all
+	taint   bool                                         // whether a subscriber needs taint tracking
+
+	dispatched uint64                     // events handed to at least one plugin
+	registered map[string]*pluginMetrics // plugins with at least one handler
+	recorder   *Recorder                 // recording every dispatched event, if any
+	host       collector.Host            // state of the running transaction, nil outside of one
 }
 
 var clearvalue []*MonitorType
 
 func NewPluginManages() *PluginManages {
-	return &PluginManages{make(map[string][]*MonitorType)}
+	plg := &PluginManages{plugins: make(map[string][]*MonitorType)}
+	plg.compile()
+	return plg
+}
+
+// compile rebuilds the dispatch table from the registered plugins.
+func (plg *PluginManages) compile() {
+	for index := range plg.table {
+		plg.table[index], plg.stats[index] = nil, nil
+	}
+	stats := make(map[string]*pluginMetrics)
+	for opcode, monitors := range plg.plugins {
+		index, ok := registerOp[opcode]
+		if !ok || len(monitors) == 0 {
+			continue
+		}
+		plg.table[index] = monitors
+		for _, monitor := range monitors {
+			name := monitor.GetPluginName()
+			if stats[name] == nil {
+				stats[name] = newPluginMetrics(name)
+			}
+			plg.stats[index] = append(plg.stats[index], newEventMetrics(stats[name], name, opcode))
+		}
+	}
+	for name, m := range plg.registered {
+		if stats[name] == nil {
+			m.enabled.Update(0)
+		}
+	}
+	for _, m := range stats {
+		m.enabled.Update(1)
+	}
+	plg.registered = stats
+	plg.events, plg.active, plg.taint = 0, false, false
+	for _, monitors := range plg.table {
+		for _, monitor := range monitors {
+			plg.taint = plg.taint || monitor.GetTaint()
+		}
+	}
+	for ev := EventID(0); ev < numEvents; ev++ {
+		if len(plg.table[eventIndex(ev)]) > 0 {
+			plg.events |= 1 << uint(ev)
+			plg.active = true
+		}
+	}
+	for op := 0; op < opcodeCount; op++ {
+		plg.collect[op], plg.send[op] = len(plg.table[op]) > 0, op
+		plg.active = plg.active || plg.collect[op]
+	}
+	for opcode, pair := range registerPairOp {
+		op, start, end := registerOp[opcode], registerOp[pair[0]], registerOp[pair[1]]
+		plg.collect[op] = len(plg.table[start]) > 0 || len(plg.table[end]) > 0
+		plg.send[op] = end
+	}
+	// A recording holds the full event stream, not only what the plugins
+	// subscribed to.
+	if plg.recorder != nil {
+		for op := range plg.collect {
+			plg.collect[op] = true
+		}
+		plg.events, plg.active = 1<<uint(numEvents)-1, true
+	}
+}
+
+// StartRecording writes every event dispatched from now on, restricted to the
+// transactions selected by filter, into a new recording at path. A running
+// recording is stopped first.
+func (plg *PluginManages) StartRecording(path string, filter *RecordFilter) error {
+	if err := plg.StopRecording(); err != nil {
+		fmt.Println("Recording failed:", err)
+	}
+	recorder, err := CreateRecording(path, filter)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Recording events to", path)
+	plg.recorder = recorder
+	plg.compile()
+	return nil
+}
+
+// StopRecording finishes the running recording, if any.
+func (plg *PluginManages) StopRecording() error {
+	if plg == nil || plg.recorder == nil {
+		return nil
+	}
+	recorder := plg.recorder
+	plg.recorder = nil
+	plg.compile()
+
+	fmt.Println("Recorded", recorder.Events(), "events")
+	return recorder.Close()
 }
 
 func (plg *PluginManages) RegisterOpcode(opcode string ,monitor *MonitorType){
@@ -47,57 +153,128 @@ func (plg *PluginManages) RegisterOpcode(opcode string ,monitor *MonitorType){
 			break
 		}
 	}
+	plg.compile()
+}
+
+// Active reports whether any plugin subscribed to anything. It is safe to call
+// on a nil manager, which is what chains without SODA set up carry.
+func (plg *PluginManages) Active() bool {
+	return plg != nil && plg.active
+}
+
+// Collects reports whether the interpreter has to collect data for op.
+func (plg *PluginManages) Collects(op byte) bool {
+	return plg != nil && plg.collect[op]
+}
+
+// Taints reports whether the interpreter has to track the taint of values for
+// the subscribed plugins.
+func (plg *PluginManages) Taints() bool {
+	return plg != nil && plg.taint
+}
 
+// HasEvent reports whether any plugin subscribed to the synthetic event.
+func (plg *PluginManages) HasEvent(ev EventID) bool {
+	return plg != nil && plg.events&(1<<uint(ev)) != 0
+}
+
+// Dispatched returns how many events were handed to the plugins so far.
+func (plg *PluginManages) Dispatched() uint64 {
+	if plg == nil {
+		return 0
+	}
+	return plg.dispatched
 }
 
 func (plg *PluginManages) GetOpcodeRegister(opcode string) bool {
-	_, isTrue := plg.plugins[opcode]
-	return isTrue
-}
-
-
-
-func (plg *PluginManages) SendDataToPlugin(opcode string, data *collector.AllCollector) bool {
-	// if tingrong.TxHash == "0x847194c9081008ede0ca7dbbb037408a15b6b96b11bca07f032af001c2edd083" || tingrong.TxHash == "0x1fa290fac8231ff6936ae22b2d6116ecf7dfe5cda6823ce44cd803ef620aab84"{
-	// 	fmt.Println("tingrong.TxHash :",tingrong.TxHash)
-	if monitor_arr, isTrue := plg.plugins[opcode]; isTrue {
-		for index := 0; index < len(monitor_arr); index++ {
-			// true_opcode :=  plg.plugins[opcode][index].GetIAL_Optinon()
-			if plg.plugins[opcode][index].GetStatus(){
-				
-				// fmt.Println("senddata:",data)
-				// fmt.Println("new:", plg.plugins[opcode][index])
-				warning_level,results := ((plg.plugins[opcode])[index]).Send(data)
-				switch warning_level{
-				case 0x01:
-					StandardWarningReport(((plg.plugins[opcode])[index]).GetPluginName(),results,((plg.plugins[opcode])[index]).GetLogger(),opcode,2)
-				case 0x02:
-					StandardWarningReport(((plg.plugins[opcode])[index]).GetPluginName(),results,((plg.plugins[opcode])[index]).GetLogger(),opcode,3)
-					((plg.plugins[opcode])[index]).SetStatus(false)
-					tingrong.BLOCKING_FLAG = true
-					continue
-				case 0x03:
-					StandardWarningReport(((plg.plugins[opcode])[index]).GetPluginName(),results,((plg.plugins[opcode])[index]).GetLogger(),opcode,3)
-					((plg.plugins[opcode])[index]).SetStatus(false)
-					tingrong.BLOCKING_FLAG = true
-					continue
-				default:
-					continue
-				}
-				
-				
-			}
-		}
-	}else{
+	if plg == nil {
 		return false
 	}
-	// }
+	index, ok := registerOp[opcode]
+	return ok && len(plg.table[index]) > 0
+}
 
+// SendOpcode dispatches the data collected for op. Opcodes reported as a
+// start/end pair are dispatched as their end event.
+func (plg *PluginManages) SendOpcode(op byte, data *collector.Event) bool {
+	return plg.dispatch(plg.send[op], data.Option, data)
+}
+
+// SendEvent dispatches a synthetic event.
+func (plg *PluginManages) SendEvent(ev EventID, data *collector.Event) bool {
+	return plg.dispatch(eventIndex(ev), data.Option, data)
+}
+
+func (plg *PluginManages) SendDataToPlugin(opcode string, data *collector.Event) bool {
+	if plg == nil {
+		return false
+	}
+	index, ok := registerOp[opcode]
+	if !ok {
+		return false
+	}
+	return plg.dispatch(index, opcode, data)
+}
+
+func (plg *PluginManages) dispatch(index int, opcode string, data *collector.Event) bool {
+	//add new
+	if plg.recorder != nil || len(plg.table[index]) > 0 {
+		annotate(data)
+	}
+	if plg.recorder != nil && !plg.recorder.record(index, data) {
+		if err := plg.StopRecording(); err != nil {
+			fmt.Println("Recording failed:", err)
+		}
+	}
+	monitor_arr := plg.table[index]
+	if len(monitor_arr) == 0 {
+		return false
+	}
+	plg.dispatched++
+	for i, monitor := range monitor_arr {
+		stats := plg.stats[index][i]
+		if !monitor.GetStatus(){
+			stats.plugin.skipped.Mark(1)
+			continue
+		}
+		start := stats.begin()
+		warning_level,results,err := monitor.trySend(data, plg.host)
+		stats.end(start)
+		if err != nil {
+			// A crashing plugin is switched off for the rest of the
+			// transaction instead of taking the node down.
+			stats.plugin.panics.Mark(1)
+			StandardWarningReport(monitor.GetPluginName(),"panic: "+err.Error(),monitor.GetLogger(),opcode,3)
+			monitor.SetStatus(false)
+			continue
+		}
+		switch warning_level{
+		case 0x01:
+			stats.plugin.warnings.Mark(1)
+			StandardWarningReport(monitor.GetPluginName(),results,monitor.GetLogger(),opcode,2)
+		case 0x02, 0x03:
+			stats.plugin.serious.Mark(1)
+			StandardWarningReport(monitor.GetPluginName(),results,monitor.GetLogger(),opcode,3)
+			monitor.SetStatus(false)
+			tingrong.BLOCKING_FLAG = true
+		}
+	}
 	return true
 }
 
+// SetHost hands the state of the transaction about to run to the handlers
+// that read it.
+func (plg *PluginManages) SetHost(host collector.Host) {
+	if plg == nil {
+		return
+	}
+	plg.host = host
+}
 
 func (plg *PluginManages) Start() {
+	if plg == nil {
+		return
+	}
 	for _, valuelist := range plg.plugins {
 		for index := 0; index < len(valuelist); index++ {
 			(valuelist[index]).SetStatus(true)
@@ -106,6 +283,9 @@ func (plg *PluginManages) Start() {
 }
 
 func (plg *PluginManages) Stop() {
+	if plg == nil {
+		return
+	}
 	for _, valuelist := range plg.plugins {
 		for index := 0; index < len(valuelist); index++ {
 			(valuelist[index]).SetStatus(false)
@@ -118,6 +298,9 @@ func StandardWarningReport(PluginName ,comments string,logger *WarnTxLog,opcode
 	var contract string
 	if opcode == "EXTERNALINFOSTART" && len(tingrong.CALL_STACK) == 0{
 		contract = "EXTERNALCREATE"
+	}else if len(tingrong.CALL_STACK) == 0{
+		//add new: BLOCK_INFO and the rewards run outside of any call
+		contract = "BLOCK"
 	}else{
 		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
 		temp_arr := strings.Split(temp_str,"#")
@@ -134,6 +317,7 @@ func StandardWarningReport(PluginName ,comments string,logger *WarnTxLog,opcode
 	}
 	logger.WriteLog(logstr)
 	logger.CloseFile()
+	recordAlert(PluginName,opcode,level,comments,contract)
 
 }
 
@@ -154,4 +338,5 @@ func (plg *PluginManages) UnRegisterPlg() {
 			}
 		}
 	}
-}
\ No newline at end of file
+	plg.compile()
+}
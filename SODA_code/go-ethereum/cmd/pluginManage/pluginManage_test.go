@@ -0,0 +1,412 @@
+package pluginManage
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// newTestMonitor registers handler for opcode under the given plugin name.
+func newTestMonitor(plg *PluginManages, name, opcode string, handler EventFuncType) {
+	monitor := new(MonitorType)
+	monitor.SetPluginName(name)
+	monitor.SetLogger(name)
+	monitor.SetEventFunc(handler)
+	monitor.SetOpcode(opcode)
+	plg.RegisterOpcode(opcode, monitor)
+}
+
+func TestDispatchMetrics(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	dir, err := ioutil.TempDir("", "soda-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	LogDir = dir
+
+	plg := NewPluginManages()
+	newTestMonitor(plg, "TestMetrics", "ADD", func(*collector.Event) (byte, string) {
+		return 0x01, "add"
+	})
+	newTestMonitor(plg, "TestMetrics", "TXSTART", func(*collector.Event) (byte, string) {
+		panic("broken handler")
+	})
+	tingrong.CALL_STACK = []string{"0x0000000000000000000000000000000000000000#1"}
+	defer func() { tingrong.CALL_STACK = nil }()
+
+	plg.Start()
+	plg.SendOpcode(0x01, collector.FlagEvent("ADD"))
+	plg.SendEvent(EvTxStart, collector.FlagEvent("TXSTART"))
+	plg.SendEvent(EvTxStart, collector.FlagEvent("TXSTART"))
+
+	if n := metrics.GetOrRegisterMeter("soda/TestMetrics/ADD/calls", nil).Count(); n != 1 {
+		t.Errorf("ADD calls mismatch: have %d, want 1", n)
+	}
+	if n := metrics.GetOrRegisterTimer("soda/TestMetrics/ADD/latency", nil).Count(); n != 1 {
+		t.Errorf("ADD latency samples mismatch: have %d, want 1", n)
+	}
+	if n := metrics.GetOrRegisterMeter("soda/TestMetrics/alerts/warning", nil).Count(); n != 1 {
+		t.Errorf("warning count mismatch: have %d, want 1", n)
+	}
+	if n := metrics.GetOrRegisterMeter("soda/TestMetrics/panics", nil).Count(); n != 1 {
+		t.Errorf("panic count mismatch: have %d, want 1", n)
+	}
+	// The panicking handler is disabled, so the second TXSTART is skipped
+	if n := metrics.GetOrRegisterMeter("soda/TestMetrics/skipped", nil).Count(); n != 1 {
+		t.Errorf("skipped count mismatch: have %d, want 1", n)
+	}
+	// Dispatch is synchronous, nothing is queued or dropped.
+	if n := metrics.DefaultRegistry.Get("soda/TestMetrics/queue"); n == nil || n.(metrics.Gauge).Value() != 0 {
+		t.Errorf("unexpected queue depth %v", n)
+	}
+	if n := metrics.DefaultRegistry.Get("soda/TestMetrics/dropped"); n == nil || n.(metrics.Meter).Count() != 0 {
+		t.Errorf("unexpected dropped events %v", n)
+	}
+	if tingrong.BLOCKING_FLAG {
+		t.Errorf("panicking plugin blocked the transaction")
+	}
+}
+
+func TestAlertsSince(t *testing.T) {
+	_, seq := AlertsSince(0)
+	for i := 0; i < alertLimit+5; i++ {
+		recordAlert("TestAlerts", "CALLEND", 2+i%2, "reentrancy", "0x00")
+	}
+	alerts, last := AlertsSince(seq)
+	if last != seq+alertLimit+5 {
+		t.Fatalf("sequence mismatch: have %d, want %d", last, seq+alertLimit+5)
+	}
+	if len(alerts) != alertLimit {
+		t.Fatalf("alert count mismatch: have %d, want %d", len(alerts), alertLimit)
+	}
+	if alerts[0].Severity != "serious" || alerts[1].Severity != "warning" {
+		t.Errorf("severity mismatch: have %s, %s", alerts[0].Severity, alerts[1].Severity)
+	}
+	recordAlert("TestAlerts", "CALLEND", 3, "reentrancy", "0x00")
+	if alerts, _ := AlertsSince(last); len(alerts) != 1 || alerts[0].Severity != "serious" {
+		t.Errorf("expected a single new serious alert, have %v", alerts)
+	}
+}
+
+// Events sent outside of a transaction find an empty call stack, alerts and
+// panics on them must not take the node down.
+func TestBlockEventAlerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "soda-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	LogDir = dir
+
+	tests := []struct {
+		ev    EventID
+		event *collector.Event
+	}{
+		{EvBalanceTransfer, (&collector.BalanceTransfer{Reason: collector.TransferReward, BlockNumber: 1}).SendBalanceTransferEvent()},
+		{EvBalanceTransfer, (&collector.BalanceTransfer{Reason: collector.TransferUncle, BlockNumber: 1}).SendBalanceTransferEvent()},
+		{EvBlockInfo, (&collector.BlockEvent{Number: 1}).SendBlockEvent()},
+	}
+	defer func() { tingrong.BLOCKING_FLAG = false }()
+	for i, tt := range tests {
+		plg := NewPluginManages()
+		newTestMonitor(plg, "TestBlockAlert", tt.event.Option, func(*collector.Event) (byte, string) {
+			return 0x02, "block alert"
+		})
+		newTestMonitor(plg, "TestBlockPanic", tt.event.Option, func(*collector.Event) (byte, string) {
+			panic("broken handler")
+		})
+		tingrong.CALL_STACK = nil
+
+		_, seq := AlertsSince(0)
+		plg.Start()
+		if !plg.SendEvent(tt.ev, tt.event) {
+			t.Errorf("test %d: %s not dispatched", i, tt.event.Option)
+			continue
+		}
+		alerts, _ := AlertsSince(seq)
+		if len(alerts) != 2 {
+			t.Fatalf("test %d: alert count mismatch: have %d, want 2", i, len(alerts))
+		}
+		for _, alert := range alerts {
+			if alert.Contract != "BLOCK" || alert.Event != tt.event.Option {
+				t.Errorf("test %d: alert mismatch: have %s on %s, want BLOCK on %s", i, alert.Event, alert.Contract, tt.event.Option)
+			}
+		}
+	}
+}
+
+// runTestTx dispatches the events of a transaction calling from contract a
+// into contract b.
+func runTestTx(plg *PluginManages, hash common.Hash) {
+	tingrong.TxHash = hash.String()
+	tingrong.CALL_STACK = []string{"0x000000000000000000000000000000000000000a#1"}
+	plg.SendEvent(EvTxStart, collector.FlagEvent("TXSTART"))
+	add := collector.NewInsEvent()
+	add.OpName = "ADD"
+	add.AddArgs(big.NewInt(1), big.NewInt(2))
+	plg.SendOpcode(0x01, add.SendInsEvent())
+
+	tingrong.CALL_STACK = append(tingrong.CALL_STACK, "0x000000000000000000000000000000000000000b#2")
+	eq := collector.NewInsEvent()
+	eq.OpName = "EQ"
+	eq.CallLayer = 2
+	plg.SendOpcode(0x14, eq.SendInsEvent())
+	tingrong.CALL_STACK = tingrong.CALL_STACK[:1]
+	plg.SendEvent(EvTxEnd, collector.FlagEvent("TXEND"))
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "soda-record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	LogDir = dir
+	defer func() { tingrong.CALL_STACK = nil }()
+
+	var (
+		path    = filepath.Join(dir, "events.rec")
+		skipped = common.HexToHash("0x01")
+		wanted  = common.HexToHash("0x02")
+	)
+	// Record the second of two transactions with a manager no plugin
+	// subscribed to, so everything is collected for the recording only
+	plg := NewPluginManages()
+	if err := plg.StartRecording(path, &RecordFilter{Txs: []common.Hash{wanted}}); err != nil {
+		t.Fatal(err)
+	}
+	if !plg.Collects(0x01) || !plg.HasEvent(EvTxEnd) {
+		t.Fatalf("recording manager does not collect every event")
+	}
+	runTestTx(plg, skipped)
+	runTestTx(plg, wanted)
+	runTestTx(plg, skipped) // completes the recording, nothing is left to select
+	if plg.recorder != nil {
+		t.Fatalf("recording not finished after its last transaction")
+	}
+	if plg.Active() {
+		t.Errorf("manager still collects after the recording finished")
+	}
+
+	// Replay it into handlers checking the restored transaction state
+	var seen []string
+	replay := NewPluginManages()
+	handler := func(ev *collector.Event) (byte, string) {
+		seen = append(seen, ev.Option+"@"+tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1])
+		if tingrong.TxHash != wanted.String() {
+			t.Errorf("%s replayed in transaction %s", ev.Option, tingrong.TxHash)
+		}
+		if ev.Option == "EQ" {
+			return 0x01, "eq"
+		}
+		return 0x00, ""
+	}
+	for _, opcode := range []string{"TXSTART", "ADD", "EQ", "TXEND"} {
+		newTestMonitor(replay, "TestReplay", opcode, handler)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	_, seq := AlertsSince(0)
+	stats, err := Replay(replay, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Txs != 1 || stats.Events != 4 {
+		t.Errorf("replay stats mismatch: have %+v, want 1 tx with 4 events", stats)
+	}
+	want := []string{
+		"TXSTART@0x000000000000000000000000000000000000000a#1",
+		"ADD@0x000000000000000000000000000000000000000a#1",
+		"EQ@0x000000000000000000000000000000000000000b#2",
+		"TXEND@0x000000000000000000000000000000000000000a#1",
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("replayed events mismatch:\nhave %v\nwant %v", seen, want)
+	}
+	alerts, _ := AlertsSince(seq)
+	if len(alerts) != 1 || alerts[0].Contract != "0x000000000000000000000000000000000000000b" || alerts[0].TxHash != wanted.String() {
+		t.Errorf("unexpected replayed alerts: %v", alerts)
+	}
+}
+
+func TestReplaySchemaVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "soda-record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	LogDir = dir
+	defer func() { tingrong.CALL_STACK = nil }()
+
+	// A recording of a newer schema whose event has a member unknown here
+	var buf bytes.Buffer
+	r, err := NewRecorder(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := fmt.Sprintf(`{"version":%d,"option":"ADD","ins":{"pc":3,"added":1},"added":{}}`, collector.SchemaVersion+1)
+	r.write(recordTx, &recordTxEntry{common.HexToHash("0x01"), 1})
+	r.write(recordStack, []string{"0x000000000000000000000000000000000000000a#1"})
+	r.write(recordEvent, &recordEventEntry{0x01, []byte(event)})
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var pcs []uint64
+	replay := NewPluginManages()
+	newTestMonitor(replay, "TestReplay", "ADD", func(ev *collector.Event) (byte, string) {
+		pcs = append(pcs, ev.Ins.Pc)
+		return 0x00, ""
+	})
+	if _, err := Replay(replay, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(pcs) != 1 || pcs[0] != 3 {
+		t.Errorf("unexpected replayed events: %v", pcs)
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	db := NewSignatureDB()
+	to := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	amount := common.BigToHash(big.NewInt(5))
+
+	input := append(common.FromHex("a9059cbb"), to.Hash().Bytes()...)
+	call := db.DecodeCall(append(input, amount[:]...))
+	want := &collector.DecodedCall{
+		Signature: "transfer(address,uint256)",
+		Name:      "transfer",
+		Args: []collector.DecodedArg{
+			{Type: "address", Value: to.Hex()},
+			{Type: "uint256", Value: "5"},
+		},
+	}
+	if !reflect.DeepEqual(call, want) {
+		t.Errorf("decoded call mismatch: have %+v, want %+v", call, want)
+	}
+	if call := db.DecodeCall(input); call != nil {
+		t.Errorf("decoded truncated call data: %+v", call)
+	}
+
+	from := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	topics := []common.Hash{crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")), from.Hash(), to.Hash()}
+	log := db.DecodeLog(topics, amount[:])
+	if log == nil || log.String() != "Transfer(address: "+from.Hex()+", address: "+to.Hex()+", uint256: 5)" {
+		t.Errorf("unexpected decoded log: %v", log)
+	}
+
+	// User signatures come from 4byte databases and contract ABIs.
+	dir, err := ioutil.TempDir("", "soda-abi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	selector := hexutil.Encode(crypto.Keccak256([]byte("setOwner(address)"))[:4])
+	fourbyte := `{"` + selector + `": "setOwner(address)", "0x12345678": "forged(uint256)"}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "4byte.json"), []byte(fourbyte), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec := `[{"type": "function", "name": "vote", "inputs": [{"name": "proposal", "type": "uint8"}]},
+		{"type": "event", "name": "Voted", "inputs": [{"name": "voter", "type": "address", "indexed": true}, {"name": "proposal", "type": "uint8"}]}]`
+	if err := ioutil.WriteFile(filepath.Join(dir, "ballot.json"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.LoadDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if call := db.DecodeCall(append(common.FromHex(selector), from.Hash().Bytes()...)); call == nil || call.String() != "setOwner(address: "+from.Hex()+")" {
+		t.Errorf("unexpected 4byte call: %v", call)
+	}
+	if call := db.DecodeCall(append(common.FromHex("12345678"), amount[:]...)); call != nil {
+		t.Errorf("decoded a forged 4byte entry: %v", call)
+	}
+	vote := append(crypto.Keccak256([]byte("vote(uint8)"))[:4], common.BigToHash(big.NewInt(2)).Bytes()...)
+	if call := db.DecodeCall(vote); call == nil || call.String() != "vote(uint8 proposal: 2)" {
+		t.Errorf("unexpected ABI call: %v", call)
+	}
+	topics = []common.Hash{crypto.Keccak256Hash([]byte("Voted(address,uint8)")), from.Hash()}
+	if log := db.DecodeLog(topics, common.BigToHash(big.NewInt(2)).Bytes()); log == nil || log.String() != "Voted(address voter: "+from.Hex()+", uint8 proposal: 2)" {
+		t.Errorf("unexpected ABI log: %v", log)
+	}
+}
+
+// Methods the database doesn't hold are resolved with the selector lookup,
+// as the node does with the database of signer/fourbyte.
+func TestSignatureSelectors(t *testing.T) {
+	fourbyte := map[string]string{
+		hexutil.Encode(crypto.Keccak256([]byte("setOwner(address)"))[:4]): "setOwner(address)",
+		"0x12345678": "forged(uint256)",
+		"0x87654321": "broken(",
+	}
+	db := NewSignatureDB()
+	db.SetSelectors(func(id []byte) (string, error) {
+		if sig, ok := fourbyte[hexutil.Encode(id)]; ok {
+			return sig, nil
+		}
+		return "", fmt.Errorf("signature %x not found", id)
+	})
+	owner := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	tests := []struct {
+		input []byte
+		want  string
+	}{
+		{append(crypto.Keccak256([]byte("setOwner(address)"))[:4], owner.Hash().Bytes()...), "setOwner(address: " + owner.Hex() + ")"},
+		{append(common.FromHex("a9059cbb"), make([]byte, 64)...), "transfer(address: " + (common.Address{}).Hex() + ", uint256: 0)"},
+		{append(common.FromHex("12345678"), make([]byte, 32)...), ""},
+		{common.FromHex("87654321"), ""},
+		{common.FromHex("deadbeef"), ""},
+	}
+	for i, tt := range tests {
+		call := db.DecodeCall(tt.input)
+		if (call == nil) != (tt.want == "") || (call != nil && call.String() != tt.want) {
+			t.Errorf("test %d: have %v, want %q", i, call, tt.want)
+		}
+	}
+}
+
+func TestLayoutBounds(t *testing.T) {
+	db := NewLayoutDB()
+	fixed := func(slot uint64) *collector.SlotPath {
+		return &collector.SlotPath{Kind: collector.SlotFixed, Slot: collector.Uint64ToWord(slot)}
+	}
+	code := func(i int) common.Hash { return common.BigToHash(big.NewInt(int64(i))) }
+
+	for i := 0; i < maxVariables+1; i++ {
+		db.Learn(code(0), fixed(uint64(i)), true)
+	}
+	if n := len(db.Layout(code(0))); n != maxVariables {
+		t.Errorf("have %d variables, want %d", n, maxVariables)
+	}
+	// The first code learned is the first evicted.
+	for i := 1; i <= maxLayouts; i++ {
+		db.Learn(code(i), fixed(0), false)
+	}
+	if len(db.Layout(code(0))) != 0 || len(db.Layout(code(1))) != 1 || len(db.Layout(code(maxLayouts))) != 1 {
+		t.Error("unexpected eviction order")
+	}
+	// Callers get copies.
+	db.Layout(code(1))[0].Reads = 100
+	if v := db.Variable(code(1), fixed(0)); v == nil || v.Reads != 1 {
+		t.Errorf("layout changed through a copy: %+v", v)
+	}
+}
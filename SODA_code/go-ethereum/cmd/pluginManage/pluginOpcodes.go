@@ -2,183 +2,250 @@ package pluginManage
 
 //add new file
 
+// opcodeCount is the number of EVM opcode slots at the front of the dispatch
+// table. Synthetic events are numbered right after them.
+const opcodeCount = 256
+
+// EventID identifies a synthetic event, i.e. one the framework emits itself
+// rather than an EVM opcode.
+type EventID int
+
+const (
+	EvExternalInfoStart EventID = iota
+	EvExternalInfoEnd
+	EvCreateStart
+	EvCreateEnd
+	EvCreate2Start
+	EvCreate2End
+	EvCallStart
+	EvCallEnd
+	EvCallCodeStart
+	EvCallCodeEnd
+	EvDelegateCallStart
+	EvDelegateCallEnd
+	EvStaticCallStart
+	EvStaticCallEnd
+	EvEndSignal
+	EvBlockInfo
+	EvTxStart
+	EvTxEnd
+	EvTransCreate
+	EvTransCreate2
+	EvTransCall
+	EvTransCallCode
+	EvTransDelegateCall
+	EvTransStaticCall
+	EvTransSuicide
+	EvCallTree
+	EvTxStateDiff
+	EvBalanceTransfer
+	EvTokenTransfer
+	EvPrecompile
+	EvContractCreated
+	EvBranch
+	EvBasicBlock
+	EvMemory
+	numEvents
+)
+
 var registerOp = map[string]int{
 	//evm opcodes
-	"STOP":           0,
-	"ADD":            0,
-	"MUL":            0,
-	"SUB":            0,
-	"DIV":            0,
-	"SDIV":           0,
-	"MOD":            0,
-	"SMOD":           0,
-	"EXP":            0,
-	"NOT":            0,
-	"LT":             0,
-	"GT":             0,
-	"SLT":            0,
-	"SGT":            0,
-	"EQ":             0,
-	"ISZERO":         0,
-	"SIGNEXTEND":     0,
-	"AND":            0,
-	"OR":             0,
-	"XOR":            0,
-	"BYTE":           0,
-	"SHL":            0,
-	"SHR":            0,
-	"SAR":            0,
-	"ADDMOD":         0,
-	"MULMOD":         0,
-	"SHA3":           0,
-	"ADDRESS":        0,
-	"BALANCE":        0,
-	"ORIGIN":         0,
-	"CALLER":         0,
-	"CALLVALUE":      0,
-	"CALLDATALOAD":   0,
-	"CALLDATASIZE":   0,
-	"CALLDATACOPY":   0,
-	"DELEGATECALL":   0,
-	"STATICCALL":     0,
-	"CODESIZE":       0,
-	"CODECOPY":       0,
-	"GASPRICE":       0,
-	"EXTCODESIZE":    0,
-	"EXTCODECOPY":    0,
-	"RETURNDATASIZE": 0,
-	"RETURNDATACOPY": 0,
-	"EXTCODEHASH":    0,
-	"BLOCKHASH":      0,
-	"COINBASE":       0,
-	"TIMESTAMP":      0,
-	"NUMBER":         0,
-	"DIFFICULTY":     0,
-	"GASLIMIT":       0,
-	"POP":            0,
-	"MLOAD":          0,
-	"MSTORE":         0,
-	"MSTORE8":        0,
-	"SLOAD":          0,
-	"SSTORE":         0,
-	"JUMP":           0,
-	"JUMPI":          0,
-	"PC":             0,
-	"MSIZE":          0,
-	"GAS":            0,
-	"JUMPDEST":       0,
-	"PUSH1":          0,
-	"PUSH2":          0,
-	"PUSH3":          0,
-	"PUSH4":          0,
-	"PUSH5":          0,
-	"PUSH6":          0,
-	"PUSH7":          0,
-	"PUSH8":          0,
-	"PUSH9":          0,
-	"PUSH10":         0,
-	"PUSH11":         0,
-	"PUSH12":         0,
-	"PUSH13":         0,
-	"PUSH14":         0,
-	"PUSH15":         0,
-	"PUSH16":         0,
-	"PUSH17":         0,
-	"PUSH18":         0,
-	"PUSH19":         0,
-	"PUSH20":         0,
-	"PUSH21":         0,
-	"PUSH22":         0,
-	"PUSH23":         0,
-	"PUSH24":         0,
-	"PUSH25":         0,
-	"PUSH26":         0,
-	"PUSH27":         0,
-	"PUSH28":         0,
-	"PUSH29":         0,
-	"PUSH30":         0,
-	"PUSH31":         0,
-	"PUSH32":         0,
-	"DUP1":           0,
-	"DUP2":           0,
-	"DUP3":           0,
-	"DUP4":           0,
-	"DUP5":           0,
-	"DUP6":           0,
-	"DUP7":           0,
-	"DUP8":           0,
-	"DUP9":           0,
-	"DUP10":          0,
-	"DUP11":          0,
-	"DUP12":          0,
-	"DUP13":          0,
-	"DUP14":          0,
-	"DUP15":          0,
-	"DUP16":          0,
-	"SWAP1":          0,
-	"SWAP2":          0,
-	"SWAP3":          0,
-	"SWAP4":          0,
-	"SWAP5":          0,
-	"SWAP6":          0,
-	"SWAP7":          0,
-	"SWAP8":          0,
-	"SWAP9":          0,
-	"SWAP10":         0,
-	"SWAP11":         0,
-	"SWAP12":         0,
-	"SWAP13":         0,
-	"SWAP14":         0,
-	"SWAP15":         0,
-	"SWAP16":         0,
-	"LOG0":           0,
-	"LOG1":           0,
-	"LOG2":           0,
-	"LOG3":           0,
-	"LOG4":           0,
-	"CREATE":         0,
-	"CREATE2":        0,
-	"CALL":           0,
-	"RETURN":         0,
-	"CALLCODE":       0,
-	"REVERT":         0,
-	"SELFDESTRUCT":   0,
+	"STOP":           0x00,
+	"ADD":            0x01,
+	"MUL":            0x02,
+	"SUB":            0x03,
+	"DIV":            0x04,
+	"SDIV":           0x05,
+	"MOD":            0x06,
+	"SMOD":           0x07,
+	"EXP":            0x0a,
+	"NOT":            0x19,
+	"LT":             0x10,
+	"GT":             0x11,
+	"SLT":            0x12,
+	"SGT":            0x13,
+	"EQ":             0x14,
+	"ISZERO":         0x15,
+	"SIGNEXTEND":     0x0b,
+	"AND":            0x16,
+	"OR":             0x17,
+	"XOR":            0x18,
+	"BYTE":           0x1a,
+	"SHL":            0x1b,
+	"SHR":            0x1c,
+	"SAR":            0x1d,
+	"ADDMOD":         0x08,
+	"MULMOD":         0x09,
+	"SHA3":           0x20,
+	"ADDRESS":        0x30,
+	"BALANCE":        0x31,
+	"ORIGIN":         0x32,
+	"CALLER":         0x33,
+	"CALLVALUE":      0x34,
+	"CALLDATALOAD":   0x35,
+	"CALLDATASIZE":   0x36,
+	"CALLDATACOPY":   0x37,
+	"DELEGATECALL":   0xf4,
+	"STATICCALL":     0xfa,
+	"CODESIZE":       0x38,
+	"CODECOPY":       0x39,
+	"GASPRICE":       0x3a,
+	"EXTCODESIZE":    0x3b,
+	"EXTCODECOPY":    0x3c,
+	"RETURNDATASIZE": 0x3d,
+	"RETURNDATACOPY": 0x3e,
+	"EXTCODEHASH":    0x3f,
+	"BLOCKHASH":      0x40,
+	"COINBASE":       0x41,
+	"TIMESTAMP":      0x42,
+	"NUMBER":         0x43,
+	"DIFFICULTY":     0x44,
+	"GASLIMIT":       0x45,
+	"POP":            0x50,
+	"MLOAD":          0x51,
+	"MSTORE":         0x52,
+	"MSTORE8":        0x53,
+	"SLOAD":          0x54,
+	"SSTORE":         0x55,
+	"JUMP":           0x56,
+	"JUMPI":          0x57,
+	"PC":             0x58,
+	"MSIZE":          0x59,
+	"GAS":            0x5a,
+	"JUMPDEST":       0x5b,
+	"PUSH1":          0x60,
+	"PUSH2":          0x61,
+	"PUSH3":          0x62,
+	"PUSH4":          0x63,
+	"PUSH5":          0x64,
+	"PUSH6":          0x65,
+	"PUSH7":          0x66,
+	"PUSH8":          0x67,
+	"PUSH9":          0x68,
+	"PUSH10":         0x69,
+	"PUSH11":         0x6a,
+	"PUSH12":         0x6b,
+	"PUSH13":         0x6c,
+	"PUSH14":         0x6d,
+	"PUSH15":         0x6e,
+	"PUSH16":         0x6f,
+	"PUSH17":         0x70,
+	"PUSH18":         0x71,
+	"PUSH19":         0x72,
+	"PUSH20":         0x73,
+	"PUSH21":         0x74,
+	"PUSH22":         0x75,
+	"PUSH23":         0x76,
+	"PUSH24":         0x77,
+	"PUSH25":         0x78,
+	"PUSH26":         0x79,
+	"PUSH27":         0x7a,
+	"PUSH28":         0x7b,
+	"PUSH29":         0x7c,
+	"PUSH30":         0x7d,
+	"PUSH31":         0x7e,
+	"PUSH32":         0x7f,
+	"DUP1":           0x80,
+	"DUP2":           0x81,
+	"DUP3":           0x82,
+	"DUP4":           0x83,
+	"DUP5":           0x84,
+	"DUP6":           0x85,
+	"DUP7":           0x86,
+	"DUP8":           0x87,
+	"DUP9":           0x88,
+	"DUP10":          0x89,
+	"DUP11":          0x8a,
+	"DUP12":          0x8b,
+	"DUP13":          0x8c,
+	"DUP14":          0x8d,
+	"DUP15":          0x8e,
+	"DUP16":          0x8f,
+	"SWAP1":          0x90,
+	"SWAP2":          0x91,
+	"SWAP3":          0x92,
+	"SWAP4":          0x93,
+	"SWAP5":          0x94,
+	"SWAP6":          0x95,
+	"SWAP7":          0x96,
+	"SWAP8":          0x97,
+	"SWAP9":          0x98,
+	"SWAP10":         0x99,
+	"SWAP11":         0x9a,
+	"SWAP12":         0x9b,
+	"SWAP13":         0x9c,
+	"SWAP14":         0x9d,
+	"SWAP15":         0x9e,
+	"SWAP16":         0x9f,
+	"LOG0":           0xa0,
+	"LOG1":           0xa1,
+	"LOG2":           0xa2,
+	"LOG3":           0xa3,
+	"LOG4":           0xa4,
+	"CREATE":         0xf0,
+	"CREATE2":        0xf5,
+	"CALL":           0xf1,
+	"RETURN":         0xf3,
+	"CALLCODE":       0xf2,
+	"REVERT":         0xfd,
+	"SELFDESTRUCT":   0xff,
 
 	//add plugin
-	"EXTERNALINFOSTART":	0,
-	"EXTERNALINFOEND":		0,
-	"CREATESTART":			0,
-	"CREATEEND":			0,
-	"CREATE2START":			0,
-	"CREATE2END":			0,
-	"CALLSTART":			0,
-	"CALLEND":				0,
-	"CALLCODESTART":		0,
-	"CALLCODEEND":			0,
-	"DELEGATECALLSTART":	0,
-	"DELEGATECALLEND":		0,
-	"STATICCALLSTART":		0,
-	"STATICCALLEND":		0,
-	"ENDSIGNAL":			0,
-	"BLOCK_INFO":			0,
-	"TXSTART":				0,
-	"TXEND":				0,
-	"TRANS_CREATE":			0,
-	"TRANS_CREATE2":		0,
-	"TRANS_CALL":			0,
-	"TRANS_CALLCODE":		0,
-	"TRANS_DELEGATECALL":	0,
-	"TRANS_STATICCALL":		0,
-	"TRANS_SUICIDE":		0,
+	"EXTERNALINFOSTART":	opcodeCount + int(EvExternalInfoStart),
+	"EXTERNALINFOEND":		opcodeCount + int(EvExternalInfoEnd),
+	"CREATESTART":			opcodeCount + int(EvCreateStart),
+	"CREATEEND":			opcodeCount + int(EvCreateEnd),
+	"CREATE2START":			opcodeCount + int(EvCreate2Start),
+	"CREATE2END":			opcodeCount + int(EvCreate2End),
+	"CALLSTART":			opcodeCount + int(EvCallStart),
+	"CALLEND":				opcodeCount + int(EvCallEnd),
+	"CALLCODESTART":		opcodeCount + int(EvCallCodeStart),
+	"CALLCODEEND":			opcodeCount + int(EvCallCodeEnd),
+	"DELEGATECALLSTART":	opcodeCount + int(EvDelegateCallStart),
+	"DELEGATECALLEND":		opcodeCount + int(EvDelegateCallEnd),
+	"STATICCALLSTART":		opcodeCount + int(EvStaticCallStart),
+	"STATICCALLEND":		opcodeCount + int(EvStaticCallEnd),
+	"ENDSIGNAL":			opcodeCount + int(EvEndSignal),
+	"BLOCK_INFO":			opcodeCount + int(EvBlockInfo),
+	"TXSTART":				opcodeCount + int(EvTxStart),
+	"TXEND":				opcodeCount + int(EvTxEnd),
+	"TRANS_CREATE":			opcodeCount + int(EvTransCreate),
+	"TRANS_CREATE2":		opcodeCount + int(EvTransCreate2),
+	"TRANS_CALL":			opcodeCount + int(EvTransCall),
+	"TRANS_CALLCODE":		opcodeCount + int(EvTransCallCode),
+	"TRANS_DELEGATECALL":	opcodeCount + int(EvTransDelegateCall),
+	"TRANS_STATICCALL":		opcodeCount + int(EvTransStaticCall),
+	"TRANS_SUICIDE":		opcodeCount + int(EvTransSuicide),
+	"CALLTREE":			opcodeCount + int(EvCallTree),
+	"TXSTATEDIFF":		opcodeCount + int(EvTxStateDiff),
+	"BALANCE_TRANSFER":	opcodeCount + int(EvBalanceTransfer),
+	"TOKEN_TRANSFER":	opcodeCount + int(EvTokenTransfer),
+	"PRECOMPILE":		opcodeCount + int(EvPrecompile),
+	"CONTRACT_CREATED":	opcodeCount + int(EvContractCreated),
+	"BRANCH":			opcodeCount + int(EvBranch),
+	"BASICBLOCK":		opcodeCount + int(EvBasicBlock),
+	"MEMORY":			opcodeCount + int(EvMemory),
+}
+
+// registerPairOp lists the opcodes that are reported as a start/end pair of
+// synthetic events instead of under their own name. The interpreter collects
+// such an opcode when either event is subscribed and dispatches the end event.
+var registerPairOp = map[string][2]string{
+	"CREATE":       {"CREATESTART", "CREATEEND"},
+	"CREATE2":      {"CREATE2START", "CREATE2END"},
+	"CALL":         {"CALLSTART", "CALLEND"},
+	"CALLCODE":     {"CALLCODESTART", "CALLCODEEND"},
+	"DELEGATECALL": {"DELEGATECALLSTART", "DELEGATECALLEND"},
+	"STATICCALL":   {"STATICCALLSTART", "STATICCALLEND"},
 }
 
 var registerIALOp = map[string][]string {
-	"IAL_BYTECODE":			[]string{"EXTERNALINFOEND","EXTERNALINFOEND","TRANS_CREATE","TRANS_CREATE2"},
+	"IAL_BYTECODE":			[]string{"EXTERNALINFOEND","TRANS_CREATE","TRANS_CREATE2"},
 	"IAL_INVOKE":			[]string{"EXTERNALINFOSTART","EXTERNALINFOEND","TRANS_CALL","TRANS_CALLCODE","TRANS_DELEGATECALL","TRANS_STATICCALL"},
-	"IAL_MEMORY":			[]string{"SHA3","CALLDATACOPY","CODECOPY","RETURNDATACOPY","MLAOD","MSTORE","MSTORE8","CREATESTART","CREATEEND","CREATE2START","CREATE2END","CALLSTART","CALLEND","CALLCODESTART","CALLCODEEND","DELEGATECALLSTART","DELEGATECALLEND","STATICCALLSTART","STATICCALLEND","RETURN"},
+	"IAL_MEMORY":			[]string{"SHA3","CALLDATACOPY","CODECOPY","RETURNDATACOPY","MLOAD","MSTORE","MSTORE8","CREATESTART","CREATEEND","CREATE2START","CREATE2END","CALLSTART","CALLEND","CALLCODESTART","CALLCODEEND","DELEGATECALLSTART","DELEGATECALLEND","STATICCALLSTART","STATICCALLEND","RETURN","MEMORY"},
 	"IAL_STORAGE":			[]string{"SLOAD","SSTORE"},
 	"IAL_ETH":				[]string{"TRANS_CREATE","TRANS_CALL","TRANS_CALLCODE","TRANS_SUICIDE"},
-	"IAL_BALANCE":			[]string{"EXTERNALINFOSTART","EXTERNALINFOEND","CALLSTART","CALLEND","CALLCODESTART","CALLCODEEND","CREATESTART","CREATEEND","CREATE2START","CREATE2END","SELFDESTRUCT"},
+	"IAL_BALANCE":			[]string{"EXTERNALINFOSTART","EXTERNALINFOEND","CALLSTART","CALLEND","CALLCODESTART","CALLCODEEND","CREATESTART","CREATEEND","CREATE2START","CREATE2END","SELFDESTRUCT","BALANCE_TRANSFER"},
 	"IAL_CONTROLFLOW":		[]string{"JUMP","JUMPI"},
 	"IAL_COMPARISON":		[]string{"LT","GT","SLT","SGT","NOT","EQ","ISZERO"},
 	"IAL_ARITHMETIC":		[]string{"ADD","MUL","SUB","DIV","SDIV","MOD","SMOD","ADDMOD","MULMOD","EXP"},
@@ -201,4 +268,15 @@ func ReturnIALArray(opcode string) []string {
 
 func RetunOpcodeMap() map[string]int {
 	return registerOp
-}
\ No newline at end of file
+}
+// EventIndex returns the dispatch table slot of an opcode or synthetic event
+// name.
+func EventIndex(name string) (int, bool) {
+	index, ok := registerOp[name]
+	return index, ok
+}
+
+// eventIndex returns the dispatch table slot of a synthetic event.
+func eventIndex(ev EventID) int {
+	return opcodeCount + int(ev)
+}
@@ -13,14 +13,18 @@ import (
 
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
+// LogDir is the folder the per-plugin warning logs are written to.
+var LogDir = "./plugin_log"
+
 type RegisterInfo struct {
 	PluginName string   `json:"pluginname"`
 	OpCode     map[string]string `json:"option"`
+	Taint      bool              `json:"taint"` // whether the plugin needs taint tracking
 }
 
 func SetUpPlugin(manage *PluginManages){
 	pluginFiles,_ := filepath.Glob("./plugin/*.so")
-	log_path := "./plugin_log"
+	log_path := LogDir
 	_,err := os.Stat(log_path)
 	if err == nil || os.IsNotExist(err){
 		os.Mkdir(log_path,os.ModePerm)
@@ -29,7 +33,11 @@ func SetUpPlugin(manage *PluginManages){
 		fmt.Println("plugin:", value)
 		RegisterPlugin(manage, value)
 	}
-	
+	//add new
+	if err := Signatures.LoadDir(SignatureDir); err != nil {
+		fmt.Println("error loading signatures:", err)
+	}
+
 }
 
 func RegisterPlugin(manage *PluginManages, path string) bool {
@@ -38,44 +46,57 @@ func RegisterPlugin(manage *PluginManages, path string) bool {
 		fmt.Println("error open plugin: ", err, "from path :", path)
 		os.Exit(-1)
 	}
-	// fmt.Println("ex",plugin)
-	register_method, err := plugin.Lookup("Register")
-	if err != nil {
-		fmt.Println("Can not find register function:Register() in plugin", err, "from path :", path)
+	lookup := func(symbol string) (interface{}, error) {
+		return plugin.Lookup(symbol)
+	}
+	if err := RegisterDetector(manage, lookup); err != nil {
+		fmt.Println(err, "from path :", path)
 		panic(err)
 	}
-	register_res, b_err := register_method.(func() []byte)
-	if !b_err{
-		panic(b_err)
+	return true
+}
+
+// RegisterDetector registers a detector whose Register function and handlers
+// are resolved by lookup. RegisterPlugin resolves them in a loaded plugin,
+// tests can resolve them in a detector compiled into the test binary.
+func RegisterDetector(manage *PluginManages, lookup func(symbol string) (interface{}, error)) error {
+	register_method, err := lookup("Register")
+	if err != nil {
+		return fmt.Errorf("Can not find register function:Register() in plugin: %v", err)
+	}
+	register_res, ok := register_method.(func() []byte)
+	if !ok {
+		return fmt.Errorf("Register() in plugin has unexpected type %T", register_method)
 	}
 	var register_info RegisterInfo
 	err = json.Unmarshal(register_res(), &register_info)
 	if err != nil {
-		fmt.Println("Can not parse the struct RegisterInfo from the function:Register() in plugin", err, "from path :", path)
-		panic(err)
+		return fmt.Errorf("Can not parse the struct RegisterInfo from the function:Register() in plugin: %v", err)
 	}
-	fmt.Println("Data log path:./plugin_log/" , register_info.PluginName , "datalog")
+	fmt.Println("Data log path:"+LogDir+"/" , register_info.PluginName , "datalog")
 	register_map := register_info.OpCode
 	for opcode,sendfunc := range(register_map){
 		var monitor MonitorType
 		monitor.SetPluginName(register_info.PluginName)
 		monitor.SetLogger(register_info.PluginName)
-		// fmt.Println("opcode:",opcode,"sendfunc:",sendfunc)
-		symGreeter, err := plugin.Lookup(sendfunc)
+		symGreeter, err := lookup(sendfunc)
 		if err != nil {
-			fmt.Println("Can not find function",sendfunc," in plugin", err, "from path :", path)
-			panic(err)
+			return fmt.Errorf("Can not find function %s in plugin: %v", sendfunc, err)
 		}
-		rcvefunc, ok := symGreeter.(func(*collector.AllCollector) (byte,string))
-		if !ok {
-			fmt.Println("unexpected type from module symbol")
-			os.Exit(0)
+		switch rcvefunc := symGreeter.(type) {
+		case func(*collector.AllCollector) (byte,string):
+			monitor.SetSendFunc(rcvefunc)
+		case func(*collector.Event) (byte,string):
+			monitor.SetEventFunc(rcvefunc)
+		case func(*collector.Event, collector.Host) (byte,string):
+			monitor.SetHostFunc(rcvefunc)
+		default:
+			return fmt.Errorf("unexpected type %T from module symbol %s", symGreeter, sendfunc)
 		}
-		// fmt.Println("rcve",rcvefunc)
-		monitor.SetSendFunc(rcvefunc)
 		monitor.SetOpcode(opcode)
 		monitor.SetIAL_Optinon(opcode)
+		monitor.SetTaint(register_info.Taint)
 		manage.RegisterOpcode(opcode,&monitor)
 	}
-	return true
+	return nil
 }
@@ -0,0 +1,133 @@
+// Package plugintest builds the SODA detector plugins from source and loads
+// them into a plugin manager, so tests and benchmarks can run the detectors
+// without a prepared ./plugin folder.
+package plugintest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+)
+
+// Plugins lists the detectors shipped with SODA.
+var Plugins = []string{"P1", "P2", "P3", "P4", "P5", "P6", "P7", "P8"}
+
+// Suite is a named set of plugins to run together.
+type Suite struct {
+	Name    string
+	Plugins []string
+}
+
+// Suites returns the configurations the SODA benchmarks are run with: no
+// plugins at all, every plugin on its own and all of them together.
+func Suites() []Suite {
+	suites := []Suite{{Name: "none"}}
+	for _, name := range Plugins {
+		suites = append(suites, Suite{Name: name, Plugins: []string{name}})
+	}
+	return append(suites, Suite{Name: "all", Plugins: Plugins})
+}
+
+var (
+	workOnce sync.Once
+	workDir  string
+	workErr  error
+
+	buildLock sync.Mutex
+	built     = make(map[string]string)
+)
+
+// SourceDir returns the folder holding the plugin sources. It can be
+// overridden with the SODA_PLUGIN_DIR environment variable.
+func SourceDir() string {
+	if dir := os.Getenv("SODA_PLUGIN_DIR"); dir != "" {
+		return dir
+	}
+	_, file, _, _ := runtime.Caller(0)
+	dir, err := filepath.EvalSymlinks(filepath.Dir(file))
+	if err != nil {
+		dir = filepath.Dir(file)
+	}
+	return filepath.Join(dir, "..", "..", "..", "..", "plugin", "plugin")
+}
+
+// WorkDir returns the scratch folder the plugins are built into. The first
+// call creates it and points the plugin logs there, keeping the source tree
+// clean.
+func WorkDir() (string, error) {
+	workOnce.Do(func() {
+		if workDir, workErr = ioutil.TempDir("", "soda-plugins"); workErr != nil {
+			return
+		}
+		pluginManage.LogDir = filepath.Join(workDir, "plugin_log")
+		workErr = os.MkdirAll(pluginManage.LogDir, os.ModePerm)
+	})
+	return workDir, workErr
+}
+
+// Build compiles the named plugin with -buildmode=plugin and returns the path
+// of the shared object. Every plugin is only built once per process.
+func Build(name string) (string, error) {
+	dir, err := WorkDir()
+	if err != nil {
+		return "", err
+	}
+	buildLock.Lock()
+	defer buildLock.Unlock()
+
+	if path, ok := built[name]; ok {
+		return path, nil
+	}
+	src := filepath.Join(SourceDir(), name)
+	path := filepath.Join(dir, name+".so")
+
+	cmd := exec.Command("go", "build", "-buildmode=plugin", "-o", path, name+".go")
+	cmd.Dir = src
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to build plugin %s: %v\n%s", name, err, out)
+	}
+	built[name] = path
+	return path, nil
+}
+
+// NewManager returns a plugin manager with the named plugins registered. The
+// test is skipped if a plugin can't be built, since that needs a go toolchain
+// and plugin support on the host.
+func NewManager(tb testing.TB, names ...string) *pluginManage.PluginManages {
+	tb.Helper()
+
+	manage := pluginManage.NewPluginManages()
+	for _, name := range names {
+		path, err := Build(name)
+		if err != nil {
+			tb.Skip(err)
+		}
+		pluginManage.RegisterPlugin(manage, path)
+	}
+	return manage
+}
+
+// ReportCost adds the per-event and per-block cost of a finished benchmark
+// run to its results. Elapsed is the time spent in the measured code, events
+// the number of events dispatched meanwhile and blocks the number of blocks
+// processed by a single iteration, or zero if the benchmark doesn't work on
+// blocks.
+func ReportCost(b *testing.B, elapsed time.Duration, events uint64, blocks int) {
+	ns := float64(elapsed.Nanoseconds())
+
+	b.ReportMetric(float64(events)/float64(b.N), "events/op")
+	if events > 0 {
+		b.ReportMetric(ns/float64(events), "ns/event")
+	}
+	if blocks > 0 {
+		b.ReportMetric(ns/float64(b.N*blocks), "ns/block")
+	}
+}
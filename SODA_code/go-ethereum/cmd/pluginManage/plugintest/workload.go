@@ -0,0 +1,25 @@
+package plugintest
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Workload is the runtime code of a small token-like contract that touches the
+// events every shipped detector listens to. Called with a 32-byte amount it
+//
+//	PUSH1 0 CALLDATALOAD DUP1 CALLER SLOAD ADD CALLER SSTORE   ; balance[caller] += amount
+//	NUMBER TIMESTAMP LT PUSH2 @done JUMPI                      ; timestamp dependency
+//	ADDRESS BALANCE CALLVALUE EQ POP                           ; strict balance equality
+//	PUSH1 0 MSTORE CALLER ADDRESS PUSH32 <Transfer> PUSH1 32 PUSH1 0 LOG3
+//	PUSH1 0 (x5) CALLER GAS CALL POP                           ; unchecked call back
+//	done: JUMPDEST STOP
+var Workload = common.Hex2Bytes("600035803354013355434210" + "61004e57" + "3031341450" +
+	"6000523330" + "7fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" + "60206000a3" +
+	"60006000600060006000335af150" + "5b00")
+
+// WorkloadInput returns the call data transferring amount through Workload.
+func WorkloadInput(amount int64) []byte {
+	return common.LeftPadBytes(big.NewInt(amount).Bytes(), 32)
+}
@@ -0,0 +1,317 @@
+package pluginManage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// A recording is a stream of RLP lists [kind, payload]. It starts with a
+// header entry, followed by a transaction entry for every recorded
+// transaction, a call stack entry whenever the SODA call stack changed and an
+// event entry for every event dispatched by the manager. Events are stored in
+// the JSON encoding of the collector package together with their dispatch
+// slot, so replaying them reaches exactly the handlers the live node would
+// call. JSON keeps recordings readable across schema versions: members the
+// replaying collector doesn't know are dropped and new ones stay zero.
+const (
+	recordHeader = iota
+	recordTx
+	recordStack
+	recordEvent
+)
+
+// recordMagic and recordVersion identify the recording format. The version
+// changes whenever the dispatch slots of the manager are renumbered or the
+// entries change. Version 1 stored the events in RLP, which only decodes under
+// the schema version it was written with.
+const (
+	recordMagic   = "sodarec"
+	recordVersion = 2
+)
+
+var errRecordFormat = errors.New("pluginManage: not a SODA event recording")
+
+type recordEntry struct {
+	Kind    uint8
+	Payload rlp.RawValue
+}
+
+type recordHeaderEntry struct {
+	Magic   string
+	Version uint64
+}
+
+type recordTxEntry struct {
+	TxHash common.Hash
+	Block  uint64
+}
+
+type recordEventEntry struct {
+	Slot  uint64
+	Event []byte // JSON encoding of the event
+}
+
+type recordEventEntryV1 struct {
+	Slot  uint64
+	Event *collector.Event
+}
+
+// RecordFilter selects the transactions a Recorder writes. A transaction is
+// recorded if its hash is listed in Txs or its block lies within From and To,
+// both inclusive, where a zero To means no upper bound. An empty filter
+// records everything.
+type RecordFilter struct {
+	Txs  []common.Hash
+	From uint64
+	To   uint64
+}
+
+func (f *RecordFilter) empty() bool {
+	return len(f.Txs) == 0 && !f.hasRange()
+}
+
+func (f *RecordFilter) hasRange() bool {
+	return f.From != 0 || f.To != 0
+}
+
+func (f *RecordFilter) inRange(block uint64) bool {
+	return f.hasRange() && block >= f.From && (f.To == 0 || block <= f.To)
+}
+
+// Recorder writes the events dispatched by a manager into a recording.
+type Recorder struct {
+	w      *bufio.Writer
+	closer io.Closer
+
+	filter   RecordFilter
+	pending  map[common.Hash]bool // listed transactions not recorded yet
+	selected bool                 // whether the current transaction is recorded
+	stack    []string             // call stack as last written
+	events   uint64
+	err      error
+}
+
+// NewRecorder starts a recording on w. A nil filter records everything.
+func NewRecorder(w io.Writer, filter *RecordFilter) (*Recorder, error) {
+	r := &Recorder{w: bufio.NewWriter(w), pending: make(map[common.Hash]bool)}
+	if filter != nil {
+		r.filter = *filter
+	}
+	for _, hash := range r.filter.Txs {
+		r.pending[hash] = true
+	}
+	if err := r.write(recordHeader, &recordHeaderEntry{recordMagic, recordVersion}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CreateRecording starts a recording in a new file at path.
+func CreateRecording(path string, filter *RecordFilter) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewRecorder(file, filter)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	r.closer = file
+	return r, nil
+}
+
+// Events returns the number of events recorded so far.
+func (r *Recorder) Events() uint64 {
+	return r.events
+}
+
+// Close flushes the recording and closes its file, if the recorder created one.
+func (r *Recorder) Close() error {
+	err := r.w.Flush()
+	if r.err == nil {
+		r.err = err
+	}
+	if r.closer != nil {
+		if err := r.closer.Close(); r.err == nil {
+			r.err = err
+		}
+		r.closer = nil
+	}
+	return r.err
+}
+
+func (r *Recorder) write(kind uint8, payload interface{}) error {
+	blob, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(r.w, &recordEntry{kind, blob})
+}
+
+// done reports whether nothing the filter selects can follow the given block.
+func (r *Recorder) done(block uint64) bool {
+	if r.filter.empty() {
+		return false
+	}
+	pastRange := !r.filter.hasRange() || (r.filter.To != 0 && block > r.filter.To)
+	return pastRange && len(r.pending) == 0
+}
+
+// record writes the event dispatched to slot if its transaction is selected.
+// It returns false once the recording is complete or failed.
+func (r *Recorder) record(slot int, data *collector.Event) bool {
+	if r.err != nil {
+		return false
+	}
+	switch {
+	case data.Block != nil:
+		if r.done(data.Block.Number) {
+			return false
+		}
+		r.selected = r.filter.empty() || r.filter.inRange(data.Block.Number)
+	case data.Option == "TXSTART":
+		hash, block := common.HexToHash(tingrong.TxHash), tingrong.BlockNumber
+		if r.done(block) {
+			return false
+		}
+		r.selected = r.filter.empty() || r.pending[hash] || r.filter.inRange(block)
+		if !r.selected {
+			return true
+		}
+		delete(r.pending, hash)
+		if r.err = r.write(recordTx, &recordTxEntry{hash, block}); r.err != nil {
+			return false
+		}
+		r.stack = nil
+	}
+	if !r.selected {
+		return true
+	}
+	if !equalStack(r.stack, tingrong.CALL_STACK) {
+		r.stack = append(r.stack[:0], tingrong.CALL_STACK...)
+		if r.err = r.write(recordStack, r.stack); r.err != nil {
+			return false
+		}
+	}
+	blob, err := data.MarshalJSON()
+	if err != nil {
+		r.err = err
+		return false
+	}
+	if r.err = r.write(recordEvent, &recordEventEntry{uint64(slot), blob}); r.err != nil {
+		return false
+	}
+	r.events++
+	return true
+}
+
+func equalStack(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ReplayStats summarises a replayed recording.
+type ReplayStats struct {
+	Txs    int // transactions replayed
+	Events int // events replayed
+}
+
+// Replay feeds a recording into the plugins registered with plg, restoring
+// the transaction and call stack state the plugins and the warning log read
+// from tingrong along the way.
+func Replay(plg *PluginManages, r io.Reader) (*ReplayStats, error) {
+	var (
+		stream = rlp.NewStream(bufio.NewReader(r), 0)
+		stats  = new(ReplayStats)
+		entry  recordEntry
+		header recordHeaderEntry
+	)
+	if err := stream.Decode(&entry); err != nil || entry.Kind != recordHeader {
+		return stats, errRecordFormat
+	}
+	if err := rlp.DecodeBytes(entry.Payload, &header); err != nil || header.Magic != recordMagic {
+		return stats, errRecordFormat
+	}
+	if header.Version != 1 && header.Version != recordVersion {
+		return stats, fmt.Errorf("pluginManage: unsupported recording version %d, want %d", header.Version, recordVersion)
+	}
+	for {
+		if err := stream.Decode(&entry); err == io.EOF {
+			return stats, nil
+		} else if err != nil {
+			return stats, err
+		}
+		switch entry.Kind {
+		case recordTx:
+			var tx recordTxEntry
+			if err := rlp.DecodeBytes(entry.Payload, &tx); err != nil {
+				return stats, err
+			}
+			tingrong.TxHash = tx.TxHash.String()
+			tingrong.BlockNumber = tx.Block
+			tingrong.CALL_STACK = nil
+			tingrong.CALL_TREE.Reset()
+			tingrong.PREIMAGES.Reset()
+			tingrong.BLOCKING_FLAG = false
+			plg.Start()
+			stats.Txs++
+
+		case recordStack:
+			var stack []string
+			if err := rlp.DecodeBytes(entry.Payload, &stack); err != nil {
+				return stats, err
+			}
+			tingrong.CALL_STACK = stack
+
+		case recordEvent:
+			slot, ev, err := decodeEventEntry(header.Version, entry.Payload)
+			if err != nil {
+				return stats, err
+			}
+			if ev == nil || slot >= uint64(len(plg.table)) {
+				return stats, fmt.Errorf("pluginManage: invalid event entry in slot %d", slot)
+			}
+			plg.dispatch(int(slot), ev.Option, ev)
+			if ev.Option == "TXEND" && plg.HasEvent(EvTxEnd) {
+				plg.Stop()
+			}
+			stats.Events++
+		}
+	}
+}
+
+// decodeEventEntry decodes an event entry of a recording of the given version.
+func decodeEventEntry(version uint64, payload rlp.RawValue) (uint64, *collector.Event, error) {
+	if version == 1 {
+		var entry recordEventEntryV1
+		if err := rlp.DecodeBytes(payload, &entry); err != nil {
+			return 0, nil, err
+		}
+		return entry.Slot, entry.Event, nil
+	}
+	var entry recordEventEntry
+	if err := rlp.DecodeBytes(payload, &entry); err != nil {
+		return 0, nil, err
+	}
+	ev := new(collector.Event)
+	if err := ev.UnmarshalAnyJSON(entry.Event); err != nil {
+		return 0, nil, err
+	}
+	return entry.Slot, ev, nil
+}
@@ -0,0 +1,362 @@
+package pluginManage
+
+//add new file
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// SignatureDir is the folder SetUpPlugin loads signatures from. Every JSON
+// file in it is either a contract ABI or a 4byte database in the format of
+// signer/fourbyte, an object mapping hex selectors to method signatures. The
+// object may also map 32-byte hex topics to event signatures.
+var SignatureDir = "./plugin_abi"
+
+// Signatures is the database the manager decodes call data and logs with
+// before dispatching them, and the calls alerts are raised in. It knows the
+// methods and events of the ERC20 and ERC721 standards out of the box.
+var Signatures = NewSignatureDB()
+
+// builtinSignatures are known without any database. The node hands the
+// database embedded in signer/fourbyte to SetSelectors; that package can't be
+// imported from here, as it depends on core through signer/core.
+var builtinSignatures = []string{
+	"totalSupply()",
+	"balanceOf(address)",
+	"allowance(address,address)",
+	"transfer(address,uint256)",
+	"transferFrom(address,address,uint256)",
+	"approve(address,uint256)",
+	"mint(address,uint256)",
+	"ownerOf(uint256)",
+	"safeTransferFrom(address,address,uint256)",
+	"safeTransferFrom(address,address,uint256,bytes)",
+	"setApprovalForAll(address,bool)",
+	"deposit()",
+	"withdraw(uint256)",
+}
+
+var builtinEvents = []string{
+	"Transfer(address,address,uint256)",
+	"Approval(address,address,uint256)",
+	"ApprovalForAll(address,address,bool)",
+}
+
+// SignatureDB resolves method selectors and event topics to their
+// signatures and decodes the arguments.
+type SignatureDB struct {
+	methods map[[4]byte]abi.Method
+	events  map[common.Hash]abi.Event
+	guessed map[common.Hash]bool // events whose indexed arguments aren't known
+
+	selectors func(id []byte) (string, error) // fallback for unknown methods
+}
+
+// NewSignatureDB returns a database holding the builtin signatures.
+func NewSignatureDB() *SignatureDB {
+	db := &SignatureDB{
+		methods: make(map[[4]byte]abi.Method),
+		events:  make(map[common.Hash]abi.Event),
+		guessed: make(map[common.Hash]bool),
+	}
+	for _, sig := range builtinSignatures {
+		if err := db.AddMethod(sig); err != nil {
+			panic(err)
+		}
+	}
+	for _, sig := range builtinEvents {
+		if err := db.AddEvent(sig); err != nil {
+			panic(err)
+		}
+	}
+	return db
+}
+
+// signatureRegexp splits a text signature into its name and argument types.
+var signatureRegexp = regexp.MustCompile(`^([A-Za-z_$][A-Za-z0-9_$]*)\(([A-Za-z0-9,\[\]]*)\)$`)
+
+// parseSignature parses a text signature such as transfer(address,uint256).
+func parseSignature(sig string) (string, abi.Arguments, error) {
+	groups := signatureRegexp.FindStringSubmatch(sig)
+	if groups == nil {
+		return "", nil, fmt.Errorf("invalid signature %q", sig)
+	}
+	var args abi.Arguments
+	if groups[2] != "" {
+		for _, name := range strings.Split(groups[2], ",") {
+			typ, err := abi.NewType(name, nil)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid signature %q: %v", sig, err)
+			}
+			args = append(args, abi.Argument{Type: typ})
+		}
+	}
+	return groups[1], args, nil
+}
+
+// AddMethod adds a method given by its text signature.
+func (db *SignatureDB) AddMethod(sig string) error {
+	name, args, err := parseSignature(sig)
+	if err != nil {
+		return err
+	}
+	db.addMethod(abi.Method{Name: name, Inputs: args})
+	return nil
+}
+
+// AddEvent adds an event given by its text signature. As the signature
+// doesn't tell which arguments are indexed, the leading arguments are taken
+// to be, as many as a log has topics.
+func (db *SignatureDB) AddEvent(sig string) error {
+	name, args, err := parseSignature(sig)
+	if err != nil {
+		return err
+	}
+	ev := abi.Event{Name: name, Inputs: args}
+	db.events[ev.Id()], db.guessed[ev.Id()] = ev, true
+	return nil
+}
+
+func (db *SignatureDB) addMethod(method abi.Method) {
+	var selector [4]byte
+	copy(selector[:], method.Id())
+	db.methods[selector] = method
+}
+
+// SetSelectors makes the database resolve the methods it doesn't know with
+// lookup, such as the Selector method of a signer/fourbyte database. Lookup
+// is called from every goroutine executing transactions, so it must not
+// modify its data.
+func (db *SignatureDB) SetSelectors(lookup func(id []byte) (string, error)) {
+	db.selectors = lookup
+}
+
+// method returns the method of selector, looking it up with the fallback if
+// it isn't known.
+func (db *SignatureDB) method(selector [4]byte) (abi.Method, bool) {
+	if method, ok := db.methods[selector]; ok || db.selectors == nil {
+		return method, ok
+	}
+	sig, err := db.selectors(selector[:])
+	if err != nil {
+		return abi.Method{}, false
+	}
+	name, args, err := parseSignature(sig)
+	if err != nil {
+		return abi.Method{}, false
+	}
+	method := abi.Method{Name: name, Inputs: args}
+	if !bytes.Equal(method.Id(), selector[:]) {
+		return abi.Method{}, false
+	}
+	return method, true
+}
+
+// LoadFile adds the signatures of a contract ABI or a 4byte database.
+// Entries of a 4byte database whose signature doesn't hash to their key are
+// skipped.
+func (db *SignatureDB) LoadFile(path string) error {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if blob = bytes.TrimSpace(blob); len(blob) > 0 && blob[0] == '[' {
+		spec, err := abi.JSON(bytes.NewReader(blob))
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		for _, method := range spec.Methods {
+			db.addMethod(method)
+		}
+		for _, ev := range spec.Events {
+			db.events[ev.Id()] = ev
+			delete(db.guessed, ev.Id())
+		}
+		return nil
+	}
+	var entries map[string]string
+	if err := json.Unmarshal(blob, &entries); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	for key, sig := range entries {
+		id, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+		if err != nil || !bytes.HasPrefix(crypto.Keccak256([]byte(sig)), id) {
+			continue
+		}
+		switch len(id) {
+		case 4:
+			db.AddMethod(sig)
+		case 32:
+			db.AddEvent(sig)
+		}
+	}
+	return nil
+}
+
+// LoadDir adds the signatures of every JSON file in dir.
+func (db *SignatureDB) LoadDir(dir string) error {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, file := range files {
+		if err := db.LoadFile(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeCall decodes call data, nil if the method is unknown or the data
+// doesn't match its signature.
+func (db *SignatureDB) DecodeCall(input []byte) *collector.DecodedCall {
+	if db == nil || len(input) < 4 {
+		return nil
+	}
+	var selector [4]byte
+	copy(selector[:], input)
+	method, ok := db.method(selector)
+	if !ok {
+		return nil
+	}
+	values, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil
+	}
+	call := &collector.DecodedCall{Signature: method.Sig(), Name: method.Name}
+	for i, arg := range method.Inputs {
+		call.Args = append(call.Args, collector.DecodedArg{Name: arg.Name, Type: arg.Type.String(), Value: renderValue(values[i])})
+	}
+	return call
+}
+
+// DecodeLog decodes a log, nil if its event is unknown or the log doesn't
+// match its signature. Indexed arguments of a dynamic type are given as the
+// hash in their topic.
+func (db *SignatureDB) DecodeLog(topics []common.Hash, data []byte) *collector.DecodedCall {
+	if db == nil || len(topics) == 0 {
+		return nil
+	}
+	ev, ok := db.events[topics[0]]
+	if !ok {
+		return nil
+	}
+	args := ev.Inputs
+	if db.guessed[topics[0]] {
+		if len(topics)-1 > len(args) {
+			return nil
+		}
+		args = make(abi.Arguments, len(ev.Inputs))
+		for i, arg := range ev.Inputs {
+			arg.Indexed = i < len(topics)-1
+			args[i] = arg
+		}
+	}
+	values, err := args.UnpackValues(data)
+	if err != nil {
+		return nil
+	}
+	call := &collector.DecodedCall{Name: ev.Name}
+	types := make([]string, len(args))
+	topic := 1
+	for i, arg := range args {
+		types[i] = arg.Type.String()
+		decoded := collector.DecodedArg{Name: arg.Name, Type: types[i]}
+		switch {
+		case !arg.Indexed:
+			decoded.Value, values = renderValue(values[0]), values[1:]
+		case topic >= len(topics):
+			return nil
+		case arg.Type.T == abi.StringTy || arg.Type.T == abi.BytesTy || arg.Type.T == abi.SliceTy || arg.Type.T == abi.ArrayTy || arg.Type.T == abi.TupleTy:
+			decoded.Value, topic = topics[topic].Hex(), topic+1
+		default:
+			value, err := abi.Arguments{{Type: arg.Type}}.UnpackValues(topics[topic][:])
+			if err != nil {
+				return nil
+			}
+			decoded.Value, topic = renderValue(value[0]), topic+1
+		}
+		call.Args = append(call.Args, decoded)
+	}
+	call.Signature = ev.Name + "(" + strings.Join(types, ",") + ")"
+	return call
+}
+
+// renderValue prints a decoded value: addresses, hashes and bytes in hex,
+// integers in decimal.
+func renderValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return hexutil.Encode(v)
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hexutil.Encode(b)
+	}
+	return fmt.Sprint(value)
+}
+
+// annotate attaches the decoded call data or logs to an event about to be
+// dispatched, if the registry knows its signature.
+func annotate(data *collector.Event) {
+	switch {
+	case data.Message != nil:
+		switch data.Message.Type {
+		case collector.MessageCreate, collector.MessageCreate2, collector.MessageSuicide:
+		default:
+			if data.Message.Decoded == nil {
+				data.Message.Decoded = Signatures.DecodeCall(data.Message.Input)
+			}
+		}
+	case data.TxStart != nil:
+		if !data.TxStart.Create && data.TxStart.Decoded == nil {
+			data.TxStart.Decoded = Signatures.DecodeCall(data.TxStart.Input)
+		}
+	case data.Ins != nil:
+		if strings.HasPrefix(data.Ins.OpName, "LOG") && len(data.Ins.Args) > 2 && data.Ins.Decoded == nil {
+			topics := make([]common.Hash, len(data.Ins.Args)-2)
+			for i, topic := range data.Ins.Args[2:] {
+				topics[i] = common.Hash(topic)
+			}
+			data.Ins.Decoded = Signatures.DecodeLog(topics, data.Ins.RetArgs)
+		}
+	case data.TxEnd != nil:
+		for _, log := range data.TxEnd.Logs {
+			if log.Decoded == nil {
+				log.Decoded = Signatures.DecodeLog(log.Topics, log.Data)
+			}
+		}
+	}
+}
+
+// currentCall decodes the input of the innermost call being executed, nil
+// outside of a transaction or for a creation.
+func currentCall() *collector.DecodedCall {
+	frame := tingrong.CALL_TREE.Current()
+	if frame == nil {
+		frame = tingrong.CALL_TREE.Root()
+	}
+	if frame == nil || frame.Type == collector.MessageCreate || frame.Type == collector.MessageCreate2 {
+		return nil
+	}
+	return Signatures.DecodeCall(frame.Input)
+}
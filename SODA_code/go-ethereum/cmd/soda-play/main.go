@@ -0,0 +1,68 @@
+// soda-play replays a SODA event recording into detection plugins, without
+// running a node.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+)
+
+var (
+	logDir = flag.String("logdir", pluginManage.LogDir, "folder the plugin warning logs are written to")
+	quiet  = flag.Bool("quiet", false, "don't print the alerts, only the summary")
+)
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:", os.Args[0], "[-logdir <dir>] [-quiet] <recording> <plugin.so> [<plugin.so> ...]")
+		flag.PrintDefaults()
+		fmt.Fprintln(os.Stderr, `
+Feeds the events of a recording made with debug.recordBlocks or debug.recordTxs
+into the given plugins and prints the alerts they raise.`)
+	}
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	pluginManage.LogDir = *logDir
+	if err := os.MkdirAll(*logDir, os.ModePerm); err != nil {
+		die(err)
+	}
+	plg := pluginManage.NewPluginManages()
+	for _, path := range flag.Args()[1:] {
+		pluginManage.RegisterPlugin(plg, path)
+	}
+
+	fd, err := os.Open(flag.Arg(0))
+	if err != nil {
+		die(err)
+	}
+	defer fd.Close()
+
+	stats, err := pluginManage.Replay(plg, fd)
+	if err != nil {
+		die(err)
+	}
+	alerts, total := pluginManage.AlertsSince(0)
+	if !*quiet {
+		for _, alert := range alerts {
+			fmt.Printf("%-8s %-4s block %d tx %s contract %s: %s\n", alert.Severity, alert.Plugin, alert.Block, alert.TxHash, alert.Contract, alert.Message)
+		}
+		if total > uint64(len(alerts)) {
+			fmt.Printf("(only the last %d alerts are shown)\n", len(alerts))
+		}
+	}
+	fmt.Printf("Replayed %d events of %d transactions, %d alerts\n", stats.Events, stats.Txs, total)
+}
+
+func die(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
+}
@@ -34,6 +34,10 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"golang.org/x/crypto/sha3"
+
+	//add new
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
 )
 
 // Ethash proof-of-work protocol constants.
@@ -629,9 +633,25 @@ func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header
 		r.Mul(r, blockReward)
 		r.Div(r, big8)
 		state.AddBalance(uncle.Coinbase, r)
+		sendReward(config, header, collector.TransferUncle, uncle.Coinbase, r) //add new
 
 		r.Div(blockReward, big32)
 		reward.Add(reward, r)
 	}
 	state.AddBalance(header.Coinbase, reward)
+	sendReward(config, header, collector.TransferReward, header.Coinbase, reward) //add new
+}
+
+// sendReward reports a block or uncle reward to the SODA plugins.
+func sendReward(config *params.ChainConfig, header *types.Header, reason string, to common.Address, amount *big.Int) {
+	if !config.TransferDataPlg.HasEvent(pluginManage.EvBalanceTransfer) {
+		return
+	}
+	tr := &collector.BalanceTransfer{
+		Reason:      reason,
+		BlockNumber: header.Number.Uint64(),
+		To:          to,
+		Amount:      collector.BigToWord(amount),
+	}
+	config.TransferDataPlg.SendEvent(pluginManage.EvBalanceTransfer, tr.SendBalanceTransferEvent())
 }
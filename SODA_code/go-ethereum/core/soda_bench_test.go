@@ -0,0 +1,100 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/plugintest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	sodaCorpusBlocks = 16 // blocks in the replayed corpus
+	sodaCorpusTxs    = 20 // workload calls per block
+)
+
+var sodaWorkloadAddr = common.BytesToAddress([]byte("workload"))
+
+// BenchmarkSODAReplay imports a fixed corpus of blocks calling a token-like
+// contract into a fresh chain with no plugins, with every detector on its own
+// and with all of them together, reporting the cost per block and per
+// dispatched event.
+func BenchmarkSODAReplay(b *testing.B) {
+	gspec, blocks := sodaCorpus(b)
+
+	for _, suite := range plugintest.Suites() {
+		// Register before starting the sub-benchmark, the plugins print
+		// their log paths while loading.
+		plg := plugintest.NewManager(b, suite.Plugins...)
+		b.Run(suite.Name, func(b *testing.B) {
+			benchSODAReplay(b, gspec, blocks, plg)
+		})
+	}
+}
+
+// sodaCorpus generates the replayed blocks. The corpus only depends on fixed
+// keys and code, so every run replays exactly the same transactions.
+func sodaCorpus(b *testing.B) (*Genesis, []*types.Block) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		gspec = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: GenesisAlloc{
+				benchRootAddr:    {Balance: benchRootFunds},
+				sodaWorkloadAddr: {Balance: new(big.Int), Code: plugintest.Workload},
+			},
+		}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, sodaCorpusBlocks, func(i int, gen *BlockGen) {
+		for j := 0; j < sodaCorpusTxs; j++ {
+			data := plugintest.WorkloadInput(int64(i*sodaCorpusTxs + j + 1))
+			tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(benchRootAddr), sodaWorkloadAddr, new(big.Int), 200000, new(big.Int), data), types.HomesteadSigner{}, benchRootKey)
+			if err != nil {
+				b.Fatal(err)
+			}
+			gen.AddTx(tx)
+		}
+	})
+	return gspec, blocks
+}
+
+// benchSODAReplay times InsertChain of the corpus, leaving out setting up and
+// stopping the chain of every iteration.
+func benchSODAReplay(b *testing.B, gspec *Genesis, blocks []*types.Block, plg *pluginManage.PluginManages) {
+	config := *params.TestChainConfig
+	config.TransferDataPlg = plg
+
+	var elapsed time.Duration
+	b.ResetTimer()
+	start := plg.Dispatched()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db := rawdb.NewMemoryDatabase()
+		gspec.MustCommit(db)
+		chain, err := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		begin := time.Now()
+		if _, err := chain.InsertChain(blocks); err != nil {
+			b.Fatal(err)
+		}
+		elapsed += time.Since(begin)
+
+		b.StopTimer()
+		chain.Stop()
+		b.StartTimer()
+	}
+	b.StopTimer()
+	plugintest.ReportCost(b, elapsed, plg.Dispatched()-start, len(blocks))
+}
@@ -0,0 +1,630 @@
+package core_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/detectortest"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/plugintest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+var (
+	sodaCorpusDir = filepath.Join("testdata", "soda-corpus")
+
+	updateSODACorpus = flag.Bool("update-soda-corpus", false, "regenerate the fixtures of the SODA attack corpus")
+)
+
+// sodaNetwork is the fork the corpus fixtures run on.
+const sodaNetwork = "ConstantinopleFix"
+
+// sodaAlert is an alert a corpus fixture expects the detectors to raise.
+type sodaAlert struct {
+	Plugin   string `json:"plugin"`
+	Severity string `json:"severity"`
+	Block    uint64 `json:"block"`
+}
+
+// sodaFixture is a block test of the tests package rebuilding a historic
+// incident, together with the alerts the SODA detectors must raise while its
+// blocks are imported. The fields are stored next to those of the test.
+type sodaFixture struct {
+	test     *tests.BlockTest
+	Incident string      `json:"incident"`
+	Alerts   []sodaAlert `json:"sodaAlerts"`
+}
+
+func (f *sodaFixture) UnmarshalJSON(in []byte) error {
+	type fields sodaFixture
+	if err := json.Unmarshal(in, (*fields)(f)); err != nil {
+		return err
+	}
+	f.test = new(tests.BlockTest)
+	return json.Unmarshal(in, f.test)
+}
+
+func (f *sodaFixture) MarshalJSON() ([]byte, error) {
+	test, err := json.Marshal(f.test)
+	if err != nil {
+		return nil, err
+	}
+	type fields sodaFixture
+	own, err := json.Marshal((*fields)(f))
+	if err != nil {
+		return nil, err
+	}
+	// Join the two objects, the fields of the test first.
+	return append(append(test[:len(test)-1], ','), own[1:]...), nil
+}
+
+// TestSODACorpus imports every fixture of the attack corpus with all the
+// detectors loaded and checks that exactly the expected alerts are raised.
+// Run it with -update-soda-corpus to regenerate the fixtures first.
+func TestSODACorpus(t *testing.T) {
+	if *updateSODACorpus {
+		for _, scenario := range sodaScenarios(t) {
+			writeSODAFixture(t, scenario)
+		}
+	}
+	files, err := filepath.Glob(filepath.Join(sodaCorpusDir, "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no fixtures in %s: %v", sodaCorpusDir, err)
+	}
+	for _, file := range files {
+		blob, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fixtures map[string]*sodaFixture
+		if err := json.Unmarshal(blob, &fixtures); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		for name, fixture := range fixtures {
+			fixture := fixture
+			t.Run(name, func(t *testing.T) {
+				runSODAFixture(t, fixture)
+			})
+		}
+	}
+}
+
+func runSODAFixture(t *testing.T, fixture *sodaFixture) {
+	plg := plugintest.NewManager(t, plugintest.Plugins...)
+	_, seq := pluginManage.AlertsSince(^uint64(0))
+
+	if err := fixture.test.RunWithPlugins(plg); err != nil {
+		t.Fatal(err)
+	}
+	raised, _ := pluginManage.AlertsSince(seq)
+
+	have := make([]sodaAlert, len(raised))
+	for i, alert := range raised {
+		have[i] = sodaAlert{alert.Plugin, alert.Severity, alert.Block}
+	}
+	want := append([]sodaAlert{}, fixture.Alerts...)
+	sortSODAAlerts(have)
+	sortSODAAlerts(want)
+	if !reflect.DeepEqual(have, want) {
+		var messages []string
+		for _, alert := range raised {
+			messages = append(messages, fmt.Sprintf("%s %s in block %d: %s", alert.Severity, alert.Plugin, alert.Block, alert.Message))
+		}
+		t.Errorf("alert mismatch for %s\nhave %v\nwant %v\nraised:\n\t%s", fixture.Incident, have, want, strings.Join(messages, "\n\t"))
+	}
+}
+
+func sortSODAAlerts(alerts []sodaAlert) {
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Block != alerts[j].Block {
+			return alerts[i].Block < alerts[j].Block
+		}
+		if alerts[i].Plugin != alerts[j].Plugin {
+			return alerts[i].Plugin < alerts[j].Plugin
+		}
+		return alerts[i].Severity < alerts[j].Severity
+	})
+}
+
+// sodaScenario describes how to rebuild an incident on a local chain.
+type sodaScenario struct {
+	name     string // fixture file and test name
+	incident string // what happened on mainnet
+	time     uint64 // genesis timestamp
+	alloc    core.GenesisAlloc
+	blocks   int
+	gen      func(int, *core.BlockGen)
+	post     []common.Address // accounts created by the transactions
+	alerts   []sodaAlert
+}
+
+var (
+	sodaOwnerKey    = sodaKey("owner")
+	sodaAttackerKey = sodaKey("attacker")
+	sodaUserKey     = sodaKey("user")
+
+	sodaOwner    = crypto.PubkeyToAddress(sodaOwnerKey.PublicKey)
+	sodaAttacker = crypto.PubkeyToAddress(sodaAttackerKey.PublicKey)
+	sodaUser     = crypto.PubkeyToAddress(sodaUserKey.PublicKey)
+
+	sodaFunds = new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+)
+
+func sodaKey(name string) *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("soda corpus " + name)))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// sodaTx signs a transaction with the next nonce of key and adds it to the
+// block. A nil to creates a contract.
+func sodaTx(gen *core.BlockGen, key *ecdsa.PrivateKey, to *common.Address, value int64, data []byte) {
+	var (
+		nonce = gen.TxNonce(crypto.PubkeyToAddress(key.PublicKey))
+		tx    *types.Transaction
+	)
+	if to == nil {
+		tx = types.NewContractCreation(nonce, big.NewInt(value), 1000000, new(big.Int), data)
+	} else {
+		tx = types.NewTransaction(nonce, *to, big.NewInt(value), 1000000, new(big.Int), data)
+	}
+	signed, err := types.SignTx(tx, types.HomesteadSigner{}, key)
+	if err != nil {
+		panic(err)
+	}
+	gen.AddTx(signed)
+}
+
+// sodaDeployCode prefixes runtime code with init code returning it.
+func sodaDeployCode(runtime []byte) []byte {
+	init := []byte{
+		0x61, byte(len(runtime) >> 8), byte(len(runtime)), // PUSH2 len
+		0x80,       // DUP1
+		0x60, 0x0c, // PUSH1 12, the length of this init code
+		0x60, 0x00, // PUSH1 0
+		0x39,       // CODECOPY
+		0x60, 0x00, // PUSH1 0
+		0xf3, // RETURN
+	}
+	return append(init, runtime...)
+}
+
+func sodaScenarios(t *testing.T) []*sodaScenario {
+	asm := func(format string, args ...interface{}) []byte {
+		return detectortest.Assemble(t, fmt.Sprintf(format, args...))
+	}
+	word := func(addr common.Address) common.Hash {
+		return common.BytesToHash(addr.Bytes())
+	}
+	var (
+		bank     = common.HexToAddress("0x000000000000000000000000000000000000ba2c")
+		thief    = common.HexToAddress("0x000000000000000000000000000000000000da0a")
+		wallet   = common.HexToAddress("0x000000000000000000000000000000000000fa11")
+		phisher  = common.HexToAddress("0x000000000000000000000000000000000000f154")
+		token    = common.HexToAddress("0x000000000000000000000000000000000000704e")
+		lottery  = common.HexToAddress("0x00000000000000000000000000000000000001a7")
+		king     = crypto.CreateAddress(sodaOwner, 0)
+		throne   = common.HexToAddress("0x00000000000000000000000000000000000007e0")
+		shortTo  = common.HexToAddress("0x5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a00")
+		deposit  = big.NewInt(1000)
+		genesisT = uint64(1466000000)
+	)
+	// kingCode sends the previous king the price of the throne with the
+	// bare stipend, ignores whether it arrived and crowns the caller.
+	kingCode := asm(`
+		push 0
+		push 0
+		push 0
+		push 0
+		push 1
+		sload
+		push 0
+		sload
+		push 0
+		call
+		pop
+		caller
+		push 0
+		sstore
+		callvalue
+		push 1
+		sstore
+	`)
+	// kingWallet claims the throne for its owner; receiving ether writes
+	// storage, which the 2300 gas stipend can't pay.
+	kingWallet := func(king common.Address) []byte {
+		return asm(`
+			caller
+			push %s
+			eq
+			jumpi @paid
+			push 0
+			push 0
+			push 0
+			push 0
+			callvalue
+			push %s
+			gas
+			call
+			pop
+			stop
+		paid:
+			push 1
+			push 0
+			sstore
+		`, king.Hex(), king.Hex())
+	}
+	return []*sodaScenario{
+		{
+			name:     "TheDAO",
+			incident: "The DAO, June 2016: splitDAO paid out before clearing the balance, so a recursive call drained it repeatedly",
+			time:     genesisT,
+			alloc: core.GenesisAlloc{
+				sodaAttacker: {Balance: sodaFunds},
+				bank: {
+					Balance: new(big.Int).Mul(deposit, big.NewInt(3)),
+					Storage: map[common.Hash]common.Hash{word(thief): common.BigToHash(deposit)},
+					// Pays the caller its balance, then clears it.
+					Code: asm(`
+						callvalue
+						jumpi @deposit
+						push 0
+						push 0
+						push 0
+						push 0
+						caller
+						sload
+						caller
+						gas
+						call
+						pop
+						push 0
+						caller
+						sstore
+						stop
+					deposit:
+						callvalue
+						caller
+						sload
+						add
+						caller
+						sstore
+					`),
+				},
+				thief: {
+					Balance: new(big.Int),
+					// Withdraws from the bank and again from its fallback,
+					// twice at most.
+					Code: asm(`
+						push %s
+						caller
+						eq
+						jumpi @reenter
+						jump @withdraw
+					reenter:
+						push 2
+						push 0
+						sload
+						lt
+						iszero
+						jumpi @done
+						push 0
+						sload
+						push 1
+						add
+						push 0
+						sstore
+					withdraw:
+						push 0
+						push 0
+						push 0
+						push 0
+						push 0
+						push %s
+						gas
+						call
+						pop
+					done:
+						stop
+					`, bank.Hex(), bank.Hex()),
+				},
+			},
+			blocks: 1,
+			gen: func(i int, gen *core.BlockGen) {
+				sodaTx(gen, sodaAttackerKey, &thief, 0, nil)
+			},
+			alerts: []sodaAlert{{"P1", "warning", 1}},
+		},
+		{
+			name:     "KingOfTheEther",
+			incident: "King of the Ether Throne, February 2016: the compensation send to a contract wallet ran out of gas and the unchecked failure kept the ether",
+			time:     genesisT,
+			alloc: core.GenesisAlloc{
+				sodaOwner: {Balance: sodaFunds},
+				sodaUser:  {Balance: sodaFunds},
+				wallet: {
+					Balance: new(big.Int),
+					Code:    kingWallet(king),
+				},
+			},
+			blocks: 3,
+			gen: func(i int, gen *core.BlockGen) {
+				switch i {
+				case 0:
+					sodaTx(gen, sodaOwnerKey, nil, 0, sodaDeployCode(kingCode))
+				case 1:
+					sodaTx(gen, sodaOwnerKey, &wallet, 100, nil)
+				case 2:
+					sodaTx(gen, sodaUserKey, &king, 200, nil)
+				}
+			},
+			post:   []common.Address{king},
+			alerts: []sodaAlert{{"P5", "warning", 3}},
+		},
+		{
+			name:     "KingOfTheEtherPredeployed",
+			incident: "King of the Ether Throne with the throne deployed before the detectors were loaded, so they only know its code from the state",
+			time:     genesisT,
+			alloc: core.GenesisAlloc{
+				sodaUser: {Balance: sodaFunds},
+				throne: {
+					Balance: big.NewInt(100),
+					Storage: map[common.Hash]common.Hash{
+						common.BigToHash(big.NewInt(0)): word(wallet),
+						common.BigToHash(big.NewInt(1)): common.BigToHash(big.NewInt(100)),
+					},
+					Code: kingCode,
+				},
+				wallet: {
+					Balance: new(big.Int),
+					Code:    kingWallet(throne),
+				},
+			},
+			blocks: 1,
+			gen: func(i int, gen *core.BlockGen) {
+				sodaTx(gen, sodaUserKey, &throne, 200, nil)
+			},
+			alerts: []sodaAlert{{"P5", "warning", 1}},
+		},
+		{
+			name:     "TxOriginPhishing",
+			incident: "tx.origin phishing: a wallet authorising its owner by tx.origin is emptied by a contract the owner was lured into calling",
+			time:     genesisT,
+			alloc: core.GenesisAlloc{
+				sodaOwner: {Balance: sodaFunds},
+				wallet: {
+					Balance: sodaFunds,
+					// Sends its balance to the address in the call data
+					// if the transaction comes from the owner.
+					Code: asm(`
+						origin
+						push %s
+						eq
+						iszero
+						jumpi @deny
+						push 0
+						push 0
+						push 0
+						push 0
+						address
+						balance
+						push 0
+						calldataload
+						gas
+						call
+						pop
+						stop
+					deny:
+						push 0
+						dup1
+						revert
+					`, sodaOwner.Hex()),
+				},
+				phisher: {
+					Balance: new(big.Int),
+					// Asks the wallet to send everything to itself,
+					// unless the wallet is paying it.
+					Code: asm(`
+						caller
+						push %s
+						eq
+						jumpi @done
+						address
+						push 0
+						mstore
+						push 0
+						push 0
+						push 32
+						push 0
+						push 0
+						push %s
+						gas
+						call
+						pop
+					done:
+						stop
+					`, wallet.Hex(), wallet.Hex()),
+				},
+			},
+			blocks: 1,
+			gen: func(i int, gen *core.BlockGen) {
+				sodaTx(gen, sodaOwnerKey, &phisher, 1, nil)
+			},
+			alerts: []sodaAlert{{"P4", "warning", 1}},
+		},
+		{
+			name:     "ShortAddress",
+			incident: "Short address attack, 2017: an exchange sent transfer() with a 19 byte recipient, so the EVM shifted the amount by a byte",
+			time:     genesisT,
+			alloc: core.GenesisAlloc{
+				sodaOwner: {Balance: sodaFunds},
+				token: {
+					Balance: new(big.Int),
+					Storage: map[common.Hash]common.Hash{word(sodaOwner): common.BigToHash(big.NewInt(1000000))},
+					// transfer(address,uint256) moving balances and
+					// logging Transfer.
+					Code: asm(`
+						push 36
+						calldataload
+						dup1
+						caller
+						sload
+						lt
+						jumpi @fail
+						dup1
+						caller
+						sload
+						sub
+						caller
+						sstore
+						dup1
+						push 4
+						calldataload
+						sload
+						add
+						push 4
+						calldataload
+						sstore
+						push 0
+						mstore
+						push 4
+						calldataload
+						caller
+						push 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+						push 32
+						push 0
+						log3
+						push 1
+						push 0
+						mstore
+						push 32
+						push 0
+						return
+					fail:
+						push 0
+						dup1
+						revert
+					`),
+				},
+			},
+			blocks: 1,
+			gen: func(i int, gen *core.BlockGen) {
+				input := hexutil.MustDecode("0xa9059cbb")
+				input = append(input, common.LeftPadBytes(shortTo.Bytes()[:19], 32)[1:]...)
+				input = append(input, common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...)
+				sodaTx(gen, sodaOwnerKey, &token, 0, input)
+			},
+			alerts: []sodaAlert{{"P3", "warning", 1}},
+		},
+		{
+			name:     "TimestampLottery",
+			incident: "GovernMental, 2016: the jackpot went to the last investor once block.timestamp passed a deadline the miner controls",
+			time:     genesisT,
+			alloc: core.GenesisAlloc{
+				sodaUser: {Balance: sodaFunds},
+				lottery: {
+					Balance: sodaFunds,
+					Storage: map[common.Hash]common.Hash{{}: common.BigToHash(new(big.Int).SetUint64(genesisT - 86400))},
+					// Pays out the jackpot if nobody invested for 12
+					// hours, records the investment otherwise.
+					Code: asm(`
+						push 43200
+						push 0
+						sload
+						add
+						timestamp
+						gt
+						iszero
+						jumpi @invest
+						push 0
+						push 0
+						push 0
+						push 0
+						address
+						balance
+						caller
+						gas
+						call
+						pop
+						stop
+					invest:
+						timestamp
+						push 0
+						sstore
+					`),
+				},
+			},
+			blocks: 1,
+			gen: func(i int, gen *core.BlockGen) {
+				sodaTx(gen, sodaUserKey, &lottery, 0, nil)
+			},
+			alerts: []sodaAlert{{"P8", "warning", 1}},
+		},
+	}
+}
+
+// writeSODAFixture generates the blocks of a scenario and writes them as a
+// block test together with the expected alerts.
+func writeSODAFixture(t *testing.T, s *sodaScenario) {
+	var (
+		config = tests.Forks[sodaNetwork]
+		db     = rawdb.NewMemoryDatabase()
+		gspec  = &core.Genesis{
+			Config:     config,
+			Timestamp:  s.time,
+			GasLimit:   8000000,
+			Difficulty: big.NewInt(131072),
+			Alloc:      s.alloc,
+		}
+		genesis   = gspec.MustCommit(db)
+		blocks, _ = core.GenerateChain(config, genesis, ethash.NewFaker(), db, s.blocks, s.gen)
+		last      = blocks[len(blocks)-1]
+	)
+	statedb, err := state.New(last.Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := make(core.GenesisAlloc)
+	for addr := range s.alloc {
+		s.post = append(s.post, addr)
+	}
+	for _, addr := range s.post {
+		post[addr] = core.GenesisAccount{
+			Code:    statedb.GetCode(addr),
+			Balance: statedb.GetBalance(addr),
+			Nonce:   statedb.GetNonce(addr),
+		}
+	}
+	test, err := tests.NewBlockTest(sodaNetwork, genesis, s.alloc, post, blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture := &sodaFixture{test: test, Incident: s.incident, Alerts: s.alerts}
+	blob, err := json.MarshalIndent(map[string]*sodaFixture{s.name: fixture}, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(sodaCorpusDir, s.name+".json"), append(blob, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/plugintest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// sodaApply applies a transfer of one wei from benchRootAddr with the given
+// nonce and gas price to a chain whose manager runs handlers. It returns the
+// state and the error of ApplyTransaction.
+func sodaApply(t *testing.T, nonce uint64, gasPrice *big.Int, handlers map[string]interface{}) (*state.StateDB, error) {
+	if _, err := plugintest.WorkDir(); err != nil {
+		t.Fatal(err)
+	}
+	plg := pluginManage.NewPluginManages()
+	info := &pluginManage.RegisterInfo{PluginName: "sodatest", OpCode: make(map[string]string)}
+	for opcode := range handlers {
+		info.OpCode[opcode] = opcode
+	}
+	blob, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(symbol string) (interface{}, error) {
+		if symbol == "Register" {
+			return func() []byte { return blob }, nil
+		}
+		if fn, ok := handlers[symbol]; ok {
+			return fn, nil
+		}
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	}
+	if err := pluginManage.RegisterDetector(plg, lookup); err != nil {
+		t.Fatal(err)
+	}
+	config := *params.TestChainConfig
+	config.TransferDataPlg = plg
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	statedb.AddBalance(benchRootAddr, big.NewInt(1000000))
+	tx, err := types.SignTx(types.NewTransaction(nonce, sodaWorkloadAddr, big.NewInt(1), params.TxGas, gasPrice, nil), types.HomesteadSigner{}, benchRootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &types.Header{Number: big.NewInt(1), GasLimit: params.TxGas, Difficulty: big.NewInt(1)}
+	statedb.Prepare(tx.Hash(), common.Hash{}, 0)
+	_, _, err = ApplyTransaction(&config, nil, &common.Address{}, new(GasPool).AddGas(params.TxGas), statedb, header, tx, new(uint64), vm.Config{})
+	return statedb, err
+}
+
+// A rejected transaction never ran, so it reports nothing but its end.
+func TestSODARejectedTx(t *testing.T) {
+	tests := []struct {
+		nonce    uint64
+		gasPrice *big.Int
+		failure  string
+	}{
+		{5, new(big.Int), collector.FailureOther},
+		{0, big.NewInt(1000), collector.FailureInsufficientBalance},
+	}
+	for i, tt := range tests {
+		var seen []string
+		record := func(ev *collector.Event) (byte, string) {
+			seen = append(seen, ev.Option)
+			if ev.TxEnd != nil && ev.TxEnd.Failure != tt.failure {
+				t.Errorf("test %d: failure mismatch: have %s, want %s", i, ev.TxEnd.Failure, tt.failure)
+			}
+			return 0, ""
+		}
+		_, err := sodaApply(t, tt.nonce, tt.gasPrice, map[string]interface{}{
+			"BALANCE_TRANSFER": record,
+			"TOKEN_TRANSFER":   record,
+			"CALLTREE":         record,
+			"TXSTATEDIFF":      record,
+			"EXTERNALINFOEND":  record,
+		})
+		if err == nil {
+			t.Fatalf("test %d: transaction not rejected", i)
+		}
+		if len(seen) != 1 || seen[0] != "EXTERNALINFOEND" {
+			t.Errorf("test %d: events mismatch: have %v, want [EXTERNALINFOEND]", i, seen)
+		}
+	}
+}
+
+// The diff of a transaction a plugin blocked holds what it did before it was
+// reverted.
+func TestSODABlockedTxDiff(t *testing.T) {
+	var diff *collector.StateDiff
+	statedb, err := sodaApply(t, 0, new(big.Int), map[string]interface{}{
+		"TXSTART": func(*collector.Event) (byte, string) {
+			return 0x02, "blocked"
+		},
+		"TXSTATEDIFF": func(ev *collector.Event) (byte, string) {
+			diff = ev.StateDiff
+			return 0, ""
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statedb.GetBalance(sodaWorkloadAddr).Sign() != 0 {
+		t.Errorf("blocked transfer not reverted")
+	}
+	if diff == nil {
+		t.Fatal("no TXSTATEDIFF")
+	}
+	for _, account := range diff.Accounts {
+		if account.Address == sodaWorkloadAddr && account.BalanceChanged {
+			return
+		}
+	}
+	t.Errorf("blocked transfer missing from the diff: %+v", diff.Accounts)
+}
@@ -38,6 +38,11 @@ type journalEntry interface {
 type journal struct {
 	entries []journalEntry         // Current changes tracked by the journal
 	dirties map[common.Address]int // Dirty accounts and the number of changes
+
+	//add new
+	frames  []uint64 // SODA call frame that made each change
+	frame   uint64   // SODA call frame making changes now
+	txStart int      // first entry of the current transaction
 }
 
 // newJournal create a new initialized journal.
@@ -50,6 +55,7 @@ func newJournal() *journal {
 // append inserts a new modification entry to the end of the change journal.
 func (j *journal) append(entry journalEntry) {
 	j.entries = append(j.entries, entry)
+	j.frames = append(j.frames, j.frame)
 	if addr := entry.dirtied(); addr != nil {
 		j.dirties[*addr]++
 	}
@@ -70,6 +76,7 @@ func (j *journal) revert(statedb *StateDB, snapshot int) {
 		}
 	}
 	j.entries = j.entries[:snapshot]
+	j.frames = j.frames[:snapshot]
 }
 
 // dirty explicitly sets an address to dirty, even if the change entries would
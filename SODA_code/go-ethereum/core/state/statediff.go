@@ -0,0 +1,162 @@
+package state
+
+//add new file
+
+import (
+	"bytes"
+	"math/big"
+
+	soda "github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SetJournalFrame attributes the changes made from now on to the given call
+// frame, 0 standing for changes made outside of any frame.
+func (self *StateDB) SetJournalFrame(frame uint64) {
+	self.journal.frame = frame
+}
+
+// BeginTxDiff marks the start of a transaction for TxDiff.
+func (self *StateDB) BeginTxDiff() {
+	self.journal.txStart = self.journal.length()
+	self.journal.frame = 0
+}
+
+// accountPre holds the state of an account before the transaction.
+type accountPre struct {
+	diff *soda.AccountDiff
+
+	balanceSeen, nonceSeen, codeSeen bool
+	balance                          *big.Int
+	nonce                            uint64
+	code                             []byte
+
+	storage map[common.Hash]*soda.StorageDiff
+}
+
+// TxDiff returns the changes the current transaction made to the state, read
+// from the journal entries recorded since BeginTxDiff. It must be called
+// before the state is finalised.
+func (self *StateDB) TxDiff() *soda.StateDiff {
+	var (
+		order []*accountPre
+		pres  = make(map[common.Address]*accountPre)
+	)
+	account := func(addr common.Address) *accountPre {
+		pre, ok := pres[addr]
+		if !ok {
+			pre = &accountPre{
+				diff:    &soda.AccountDiff{Address: addr},
+				storage: make(map[common.Hash]*soda.StorageDiff),
+			}
+			pres[addr] = pre
+			order = append(order, pre)
+		}
+		return pre
+	}
+	start := self.journal.txStart
+	if start > self.journal.length() {
+		start = 0
+	}
+	for i := start; i < self.journal.length(); i++ {
+		frame := self.journal.frames[i]
+		switch ch := self.journal.entries[i].(type) {
+		case createObjectChange:
+			pre := account(*ch.account)
+			pre.diff.Created = true
+			pre.setBalance(new(big.Int), frame)
+			pre.setNonce(0, frame)
+			pre.setCode(nil, frame)
+		case resetObjectChange:
+			pre := account(ch.prev.address)
+			pre.diff.Created = true
+			pre.setBalance(ch.prev.data.Balance, frame)
+			pre.setNonce(ch.prev.data.Nonce, frame)
+			pre.setCode(ch.prev.Code(self.db), frame)
+		case suicideChange:
+			pre := account(*ch.account)
+			pre.diff.Suicided = true
+			pre.setBalance(ch.prevbalance, frame)
+		case balanceChange:
+			account(*ch.account).setBalance(ch.prev, frame)
+		case nonceChange:
+			account(*ch.account).setNonce(ch.prev, frame)
+		case codeChange:
+			account(*ch.account).setCode(ch.prevcode, frame)
+		case storageChange:
+			pre := account(*ch.account)
+			if slot, ok := pre.storage[ch.key]; ok {
+				slot.Frame = frame
+				continue
+			}
+			slot := &soda.StorageDiff{Key: ch.key, Pre: ch.prevalue, Frame: frame}
+			pre.storage[ch.key] = slot
+			pre.diff.Storage = append(pre.diff.Storage, slot)
+		}
+	}
+	diff := &soda.StateDiff{TxHash: self.thash}
+	for _, pre := range order {
+		if acc := pre.finish(self); acc != nil {
+			diff.Accounts = append(diff.Accounts, acc)
+		}
+	}
+	return diff
+}
+
+func (pre *accountPre) setBalance(balance *big.Int, frame uint64) {
+	if !pre.balanceSeen {
+		pre.balanceSeen, pre.balance = true, new(big.Int).Set(balance)
+	}
+	pre.diff.BalanceFrame = frame
+}
+
+func (pre *accountPre) setNonce(nonce uint64, frame uint64) {
+	if !pre.nonceSeen {
+		pre.nonceSeen, pre.nonce = true, nonce
+	}
+	pre.diff.NonceFrame = frame
+}
+
+func (pre *accountPre) setCode(code []byte, frame uint64) {
+	if !pre.codeSeen {
+		pre.codeSeen, pre.code = true, code
+	}
+	pre.diff.CodeFrame = frame
+}
+
+// finish fills in the post state and drops the values the transaction left
+// unchanged. It returns nil if nothing is left to report.
+func (pre *accountPre) finish(s *StateDB) *soda.AccountDiff {
+	diff, addr := pre.diff, pre.diff.Address
+	if post := s.GetBalance(addr); pre.balanceSeen && pre.balance.Cmp(post) != 0 {
+		diff.BalanceChanged = true
+		diff.PreBalance, diff.PostBalance = soda.BigToWord(pre.balance), soda.BigToWord(post)
+	} else {
+		diff.BalanceFrame = 0
+	}
+	if post := s.GetNonce(addr); pre.nonceSeen && pre.nonce != post {
+		diff.NonceChanged = true
+		diff.PreNonce, diff.PostNonce = pre.nonce, post
+	} else {
+		diff.NonceFrame = 0
+	}
+	if post := s.GetCode(addr); pre.codeSeen && !bytes.Equal(pre.code, post) {
+		diff.CodeChanged = true
+		diff.PreCode, diff.PostCode = common.CopyBytes(pre.code), common.CopyBytes(post)
+	} else {
+		diff.CodeFrame = 0
+	}
+	storage := diff.Storage[:0]
+	for _, slot := range diff.Storage {
+		if slot.Post = s.GetState(addr, slot.Key); slot.Post != slot.Pre {
+			storage = append(storage, slot)
+		}
+	}
+	if diff.Storage = storage; len(storage) == 0 {
+		diff.Storage = nil
+	}
+	if !diff.Created && !diff.Suicided && !diff.BalanceChanged && !diff.NonceChanged && !diff.CodeChanged && diff.Storage == nil {
+		return nil
+	}
+	return diff
+}
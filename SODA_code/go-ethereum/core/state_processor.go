@@ -28,11 +28,7 @@ import (
 	//add new 
 	"github.com/ethereum/collector"
 	// "syscall"
-	"strconv"
-	"fmt"
-	"github.com/ethereum/go-ethereum/tingrong"
 	"github.com/ethereum/go-ethereum/cmd/pluginManage"
-	"github.com/ethereum/go-ethereum/fei"
 )
 
 // StateProcessor is a basic Processor, which takes care of transitioning
@@ -75,24 +71,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	}
 
 	//add new
-	if p.config.TransferDataPlg.GetOpcodeRegister("handle_BLOCK_INFO"){
-		blockcollector := collector.NewBlockCollector()
-		blockcollector.Op = "Block"+fmt.Sprintf("%v", header.Number)
-		blockcollector.ParentHash = header.ParentHash.String()
-		blockcollector.UncleHash = header.UncleHash.String()
-		blockcollector.Coinbase = header.Coinbase.String()
-		blockcollector.StateRoot = header.Root.String()
-		blockcollector.TxHashRoot = header.TxHash.String()
-		blockcollector.ReceiptHash = header.ReceiptHash.String()
-		blockcollector.Difficulty = header.Difficulty.String()
-		blockcollector.Number = header.Number.String()
-		blockcollector.GasLimit = header.GasLimit
-		blockcollector.GasUsed = header.GasUsed
-		blockcollector.Time = header.Time
-		blockcollector.Extra = header.Extra
-		blockcollector.MixDigest = header.MixDigest.String()
-		blockcollector.Nonce = header.Nonce.Uint64()
-		p.config.TransferDataPlg.SendDataToPlugin("handle_BLOCK_INFO",blockcollector.SendBlockInfo("handle_BLOCK_INFO"))
+	if p.config.TransferDataPlg.HasEvent(pluginManage.EvBlockInfo){
+		blockevent := vm.NewBlockEvent(header)
+		p.config.TransferDataPlg.SendEvent(pluginManage.EvBlockInfo, blockevent.SendBlockEvent())
 	}
 	//add new
 
@@ -132,118 +113,35 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 
 	//add new 
-	vmenv.SetTxStart(true)
-	vmenv.ChainConfig().TransferDataPlg.Start()
-
-	//feifei add new --api
-	if fei.IsReg {
-		// //whole folder fresh有问题，如果全部移除，是无法删掉旧的的。需要用new去新增
-		// pluginManage.StartRun(vmenv.ChainConfig().TransferDataPlg)
-		// fei.IsReg = false
-
-		//single plugin
-		pluginManage.RegisterPlugin(vmenv.ChainConfig().TransferDataPlg, fei.RegPath)
-		fei.RegPath = fei.Clear
-		fei.IsReg = false
-	}
-
-	if fei.IsUn {
-		vmenv.ChainConfig().TransferDataPlg.UnRegisterPlg()
-		fei.IsUn = false
-		fei.UnPlg = fei.Clear
-	}
-
-	tingrong.CALL_LAYER = 0
-	tingrong.CALL_STACK = nil
-	tingrong.ALL_STACK = nil
-	tingrong.EXTERNAL_FLAG = true
-	tingrong.BLOCKING_FLAG = false
-	tingrong.PLUGIN_SNAPSHOT_ID = 0 
-	tingrong.CALLVALID_MAP = make(map[int]bool)
-	tingrong.TxHash =  tx.Hash().String()
-
-	// if vmenv.BlockNumber.Int64() >= 2300001{
-	// 	if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("ENDSIGNAL") {
-	// 		vmenv.ChainConfig().TransferDataPlg.SendDataToPlugin("ENDSIGNAL", collector.SendFlag("ENDSIGNAL"))
-	// 	}
-	// 	fmt.Println("!!!!!!!!!!!!!!   ", vmenv.BlockNumber, "   EXIT")
-	// 	syscall.Kill(syscall.Getpid(), syscall.SIGINT)
-	// }
-
-
-	if msg.To() != nil{
-		tingrong.CALL_LAYER += 1
-		tingrong.CALL_STACK = append(tingrong.CALL_STACK,msg.To().String()+"#"+strconv.Itoa(tingrong.CALL_LAYER))
-		tingrong.ALL_STACK = append(tingrong.ALL_STACK,msg.To().String())
-	}
-
-	if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("TXSTART"){
-		vmenv.ChainConfig().TransferDataPlg.SendDataToPlugin("TXSTART", collector.SendFlag("TXSTART"))
-	}
-
-	tcstart := collector.NewTransCollector()
-
-	//external collector
-	if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("EXTERNALINFOSTART"){
-		tcstart.Op = "EXTERNALINFOSTART"
-		tcstart.TxHash = tx.Hash().String()
-		tcstart.BlockNumber = vmenv.BlockNumber.String()
-		tcstart.BlockTime = vmenv.Time.String()
-		tcstart.From = msg.From().String()
-		tcstart.Value = msg.Value().String()
-		tcstart.GasPrice = msg.GasPrice().String()
-		tcstart.GasLimit = msg.Gas()
-		tcstart.Nonce = tx.Nonce()
-		tcstart.CallLayer = 1
-		if msg.To() != nil {
-			tcstart.CallType = "CALL"
-			tcstart.To = msg.To().String()
-			
-			callcollector := collector.NewCallCollector()
-			if vmenv.StateDB.Exist(*msg.To()) {
-				callcollector.ContractCode = vmenv.StateDB.GetCode(*msg.To())
-			}
-			callcollector.InputData = msg.Data()			
-			tcstart.CallInfo = *callcollector
-		}
-		vmenv.ChainConfig().TransferDataPlg.SendDataToPlugin("EXTERNALINFOSTART", tcstart.SendTransInfo("EXTERNALINFOSTART"))
-
-	}
-
-	
+	sodaTx := &vm.SODATx{
+		Hash:     tx.Hash(),
+		From:     msg.From(),
+		To:       msg.To(),
+		Value:    msg.Value(),
+		GasPrice: msg.GasPrice(),
+		Gas:      msg.Gas(),
+		Nonce:    tx.Nonce(),
+		Input:    msg.Data(),
+	}
+	vmenv.StartSODATx(statedb, bc, header, sodaTx)
 	//add new 
 
 	// Apply the transaction to the current state (included in the env)
 	_, gas, failed, err := ApplyMessage(vmenv, msg, gp)
-
-
-	//add new 
-	if tingrong.BLOCKING_FLAG == true{
-		statedb.RevertToSnapshot(tingrong.PLUGIN_SNAPSHOT_ID)
-	}
-	//add new 
-
-
-	tcend := collector.NewTransCollector()
-
-	//add new 
-	if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("EXTERNALINFOEND"){
-		tcend.Op = "EXTERNALINFOEND"
-		tcend.TxHash = tx.Hash().String()
-		tcend.GasUsed = gas
-		tcend.CallLayer = 1
-	}
-	//add new 
-
 	if err != nil {
 		//add new 
-		if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("EXTERNALINFOEND"){
-			tcend.IsSuccess = false
-			vmenv.ChainConfig().TransferDataPlg.SendDataToPlugin("EXTERNALINFOEND", tcend.SendTransInfo("EXTERNALINFOEND"))	
+		failure := vm.Failure(err)
+		if err == errInsufficientBalanceForGas {
+			failure = collector.FailureInsufficientBalance
 		}
+		vmenv.EndSODATx(statedb, sodaTx, gas, nil, failure)
 		//add new 
 		return nil, 0, err
 	}
+	//add new 
+	vmenv.ReportSODATx(statedb)
+	//add new 
+
 	// Update the state with pending changes
 	var root []byte
 	if config.IsByzantium(header.Number) {
@@ -261,19 +159,6 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
-		//add new 
-		if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("EXTERNALINFOEND"){
-			tcend.CallType = "CREATE"
-			tcend.To = receipt.ContractAddress.String()
-			createcollector := collector.NewCreateCollector()
-			createcollector.ContractAddr = receipt.ContractAddress.String()
-			createcollector.ContractDeployCode = msg.Data()	
-			if vmenv.StateDB.Exist(receipt.ContractAddress) {
-				createcollector.ContractRuntimeCode = vmenv.StateDB.GetCode(receipt.ContractAddress)
-			}		
-			tcend.CreateInfo = *createcollector
-		}
-		//add new 
 	}
 	// Set the receipt logs and create a bloom for filtering
 	receipt.Logs = statedb.GetLogs(tx.Hash())
@@ -283,26 +168,7 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	receipt.TransactionIndex = uint(statedb.TxIndex())
 
 	//add new 
-	if !failed {
-		if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("EXTERNALINFOEND"){
-			tcend.IsSuccess = true
-			vmenv.ChainConfig().TransferDataPlg.SendDataToPlugin("EXTERNALINFOEND", tcend.SendTransInfo("EXTERNALINFOEND"))
-		}
-	} else {
-		if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("EXTERNALINFOEND"){
-			tcend.IsSuccess = false
-			vmenv.ChainConfig().TransferDataPlg.SendDataToPlugin("EXTERNALINFOEND", tcend.SendTransInfo("EXTERNALINFOEND"))
-		}
-	}
-
-	tingrong.CALL_STACK = tingrong.CALL_STACK[:len(tingrong.CALL_STACK)-1]
-
-	if vmenv.ChainConfig().TransferDataPlg.GetOpcodeRegister("TXEND"){
-		vmenv.ChainConfig().TransferDataPlg.SendDataToPlugin("TXEND", collector.SendFlag("TXEND"))
-		vmenv.ChainConfig().TransferDataPlg.Stop()
-	}
-
-	
+	vmenv.EndSODATx(statedb, sodaTx, gas, receipt, "")
 	//add new 
 
 	return receipt, gas, err
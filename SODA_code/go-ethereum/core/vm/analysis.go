@@ -60,3 +60,26 @@ func codeBitmap(code []byte) bitvec {
 	}
 	return bits
 }
+
+// basicBlockEnd returns the pc of the last instruction of the basic block
+// starting at pc: the next JUMP, JUMPI or halting instruction, or the one
+// before the next JUMPDEST. Bits is the codeBitmap of code, so push data
+// that happens to look like a JUMPDEST doesn't end the block.
+func basicBlockEnd(code []byte, bits bitvec, pc uint64) uint64 {
+	end := pc
+	for i := pc; i < uint64(len(code)); i++ {
+		if !bits.codeSegment(i) {
+			continue
+		}
+		op := OpCode(code[i])
+		if op == JUMPDEST && i != pc {
+			break
+		}
+		end = i
+		switch op {
+		case JUMP, JUMPI, STOP, RETURN, REVERT, SELFDESTRUCT:
+			return end
+		}
+	}
+	return end
+}
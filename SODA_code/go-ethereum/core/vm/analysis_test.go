@@ -73,3 +73,21 @@ func BenchmarkJumpdestHashing_1200k(bench *testing.B) {
 	}
 	bench.StopTimer()
 }
+
+func TestBasicBlockEnd(t *testing.T) {
+	tests := []struct {
+		code       []byte
+		start, exp uint64
+	}{
+		{[]byte{byte(PUSH1), 0x01, byte(JUMPI), byte(STOP)}, 0, 2},
+		{[]byte{byte(PUSH1), byte(JUMPDEST), byte(POP), byte(JUMPDEST), byte(STOP)}, 0, 2},
+		{[]byte{byte(JUMPDEST), byte(PUSH1), 0x01, byte(STOP)}, 0, 3},
+		{[]byte{byte(STOP), byte(JUMPDEST), byte(ADD)}, 1, 2},
+		{[]byte{byte(JUMPDEST), byte(PUSH2), 0x01}, 0, 1},
+	}
+	for i, test := range tests {
+		if end := basicBlockEnd(test.code, codeBitmap(test.code), test.start); end != test.exp {
+			t.Errorf("test %d: expected block end %d, got %d", i, test.exp, end)
+		}
+	}
+}
@@ -81,6 +81,23 @@ func NewContract(caller ContractRef, object ContractRef, value *big.Int, gas uin
 	return c
 }
 
+// bitmap returns the JUMPDEST analysis of the code, cached the way
+// validJumpdest caches it.
+func (c *Contract) bitmap() bitvec {
+	if c.CodeHash != (common.Hash{}) {
+		analysis, exist := c.jumpdests[c.CodeHash]
+		if !exist {
+			analysis = codeBitmap(c.Code)
+			c.jumpdests[c.CodeHash] = analysis
+		}
+		return analysis
+	}
+	if c.analysis == nil {
+		c.analysis = codeBitmap(c.Code)
+	}
+	return c.analysis
+}
+
 func (c *Contract) validJumpdest(dest *big.Int) bool {
 	udest := dest.Uint64()
 	// PC cannot go beyond len(code) and certainly can't be bigger than 63bits.
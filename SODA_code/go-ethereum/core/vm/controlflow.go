@@ -0,0 +1,49 @@
+package vm
+
+//add new file
+
+import (
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// sendBranch reports a JUMP or JUMPI at pc that continued at next. Dest and
+// cond are the stack arguments read before the jump popped them.
+func (in *EVMInterpreter) sendBranch(contract *Contract, op OpCode, pc, next uint64, dest, cond collector.Word) {
+	branch := &collector.Branch{
+		Op:          op.String(),
+		TxHash:      common.HexToHash(tingrong.TxHash),
+		CallLayer:   currentCallLayer(),
+		CodeAddress: codeAddress(contract),
+		CodeHash:    contract.CodeHash,
+		Pc:          pc,
+		Dest:        dest,
+		Condition:   cond,
+		Taken:       op == JUMP || cond != (collector.Word{}),
+		Next:        next,
+	}
+	in.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvBranch, branch.SendBranchEvent())
+}
+
+// sendBasicBlock reports the entry into the basic block starting at pc.
+func (in *EVMInterpreter) sendBasicBlock(contract *Contract, pc uint64) {
+	block := &collector.BasicBlock{
+		TxHash:      common.HexToHash(tingrong.TxHash),
+		CallLayer:   currentCallLayer(),
+		CodeAddress: codeAddress(contract),
+		CodeHash:    contract.CodeHash,
+		Start:       pc,
+		End:         basicBlockEnd(contract.Code, contract.bitmap(), pc),
+	}
+	in.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvBasicBlock, block.SendBasicBlockEvent())
+}
+
+// codeAddress returns the account the code of the contract was loaded from.
+func codeAddress(contract *Contract) common.Address {
+	if contract.CodeAddr != nil {
+		return *contract.CodeAddr
+	}
+	return contract.Address()
+}
@@ -0,0 +1,103 @@
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/plugintest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newTestInterpreter returns an interpreter whose plugin manager runs a
+// detector subscribed to handlers, as at the start of a transaction.
+func newTestInterpreter(t *testing.T, handlers map[string]interface{}) *EVMInterpreter {
+	if _, err := plugintest.WorkDir(); err != nil {
+		t.Fatal(err)
+	}
+	plg := pluginManage.NewPluginManages()
+	info := &pluginManage.RegisterInfo{PluginName: "vmtest", OpCode: make(map[string]string)}
+	for opcode := range handlers {
+		info.OpCode[opcode] = opcode
+	}
+	blob, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(symbol string) (interface{}, error) {
+		if symbol == "Register" {
+			return func() []byte { return blob }, nil
+		}
+		if fn, ok := handlers[symbol]; ok {
+			return fn, nil
+		}
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	}
+	if err := pluginManage.RegisterDetector(plg, lookup); err != nil {
+		t.Fatal(err)
+	}
+	plg.Start()
+	config := *params.TestChainConfig
+	config.TransferDataPlg = plg
+	evm := NewEVM(Context{BlockNumber: new(big.Int)}, nil, &config, Config{})
+	return evm.interpreter.(*EVMInterpreter)
+}
+
+func TestCodeAddress(t *testing.T) {
+	var (
+		self = common.Address{1}
+		code = common.Address{2}
+	)
+	tests := []struct {
+		codeAddr *common.Address
+		want     common.Address
+	}{
+		{nil, self},
+		{&code, code},
+		{&self, self},
+	}
+	for i, tt := range tests {
+		contract := NewContract(AccountRef(common.Address{3}), AccountRef(self), new(big.Int), 0)
+		contract.CodeAddr = tt.codeAddr
+		if have := codeAddress(contract); have != tt.want {
+			t.Errorf("test %d: have %x, want %x", i, have, tt.want)
+		}
+	}
+}
+
+func TestSendBranch(t *testing.T) {
+	var branch *collector.Branch
+	in := newTestInterpreter(t, map[string]interface{}{
+		"BRANCH": func(ev *collector.Event) (byte, string) {
+			branch = ev.Branch
+			return 0, ""
+		},
+	})
+	tests := []struct {
+		op    OpCode
+		cond  collector.Word
+		next  uint64
+		taken bool
+	}{
+		{JUMP, collector.Word{}, 9, true},
+		{JUMPI, collector.Uint64ToWord(1), 9, true},
+		{JUMPI, collector.Uint64ToWord(1 << 40), 9, true},
+		{JUMPI, collector.Word{}, 5, false},
+	}
+	for i, tt := range tests {
+		contract := NewContract(AccountRef(common.Address{3}), AccountRef(common.Address{1}), new(big.Int), 0)
+		branch = nil
+		in.sendBranch(contract, tt.op, 4, tt.next, collector.Uint64ToWord(9), tt.cond)
+		if branch == nil {
+			t.Fatalf("test %d: no branch reported", i)
+		}
+		if branch.Op != tt.op.String() || branch.Pc != 4 || branch.Next != tt.next || branch.Taken != tt.taken ||
+			branch.Dest != collector.Uint64ToWord(9) || branch.Condition != tt.cond || branch.CodeAddress != (common.Address{1}) {
+			t.Errorf("test %d: unexpected branch %+v", i, branch)
+		}
+	}
+}
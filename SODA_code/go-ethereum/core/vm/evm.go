@@ -28,7 +28,8 @@ import (
 
 	//add new 
 	"github.com/ethereum/go-ethereum/tingrong"
-	// "github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/collector"
 	// "fmt"
 	// "strings"
 
@@ -56,6 +57,10 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 			precompiles = PrecompiledContractsByzantium
 		}
 		if p := precompiles[*contract.CodeAddr]; p != nil {
+			//add new
+			if evm.isTxStart && evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvPrecompile) {
+				return evm.runPrecompile(p, input, contract)
+			}
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -136,6 +141,7 @@ type EVM struct {
 
 	//add new 
 	isTxStart bool
+	taintSlots map[taintSlot]collector.Taint // taint of the storage written by the transaction
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -201,7 +207,8 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	//add new
 	if evm.isTxStart{
 		tingrong.CALLVALID_MAP[tingrong.CALL_LAYER] = false
-
+		evm.enterFrame(collector.MessageCall, caller.Address(), addr, addr, input, gas, value)
+		defer func() { evm.exitFrame(ret, gas-leftOverGas, err) }()
 	}
 	//add new
 	
@@ -250,6 +257,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	}
 
 	evm.Transfer(evm.StateDB, caller.Address(), to.Address(), value)
+	evm.recordTransfer(collector.TransferCall, caller.Address(), to.Address(), value) //add new
 	// Initialise a new contract and set the code that is to be used by the EVM.
 	// The contract is a scoped environment for this execution context only.
 	contract := NewContract(caller, to, value, gas)
@@ -293,7 +301,8 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	//add new
 	if evm.isTxStart{
 		tingrong.CALLVALID_MAP[tingrong.CALL_LAYER] = false
-
+		evm.enterFrame(collector.MessageCallCode, caller.Address(), caller.Address(), addr, input, gas, value)
+		defer func() { evm.exitFrame(ret, gas-leftOverGas, err) }()
 	}
 	//add new
 
@@ -348,7 +357,12 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	//add new
 	if evm.isTxStart{
 		tingrong.CALLVALID_MAP[tingrong.CALL_LAYER] = false
-
+		sender := caller.Address()
+		if parent, ok := caller.(*Contract); ok {
+			sender = parent.CallerAddress
+		}
+		evm.enterFrame(collector.MessageDelegateCall, sender, caller.Address(), addr, input, gas, nil)
+		defer func() { evm.exitFrame(ret, gas-leftOverGas, err) }()
 	}
 	//add new
 
@@ -397,7 +411,8 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	//add new
 	if evm.isTxStart{
 		tingrong.CALLVALID_MAP[tingrong.CALL_LAYER] = false
-
+		evm.enterFrame(collector.MessageStaticCall, caller.Address(), addr, addr, input, gas, nil)
+		defer func() { evm.exitFrame(ret, gas-leftOverGas, err) }()
 	}
 	//add new
 	
@@ -485,6 +500,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		evm.StateDB.SetNonce(address, 1)
 	}
 	evm.Transfer(evm.StateDB, caller.Address(), address, value)
+	evm.recordTransfer(collector.TransferCreate, caller.Address(), address, value) //add new
 
 	//add new 
 	if evm.isTxStart && tingrong.EXTERNAL_FLAG{
@@ -554,9 +570,18 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 	contractAddr = crypto.CreateAddress(caller.Address(), evm.StateDB.GetNonce(caller.Address()))
 	//add new 
 	if evm.isTxStart{
+		// contractAddr is zeroed when the creation fails early, the frame
+		// keeps the address it was entered with.
+		address := contractAddr
 		tingrong.CALL_LAYER += 1
-		tingrong.CALL_STACK = append(tingrong.CALL_STACK,contractAddr.String()+"#"+strconv.Itoa(tingrong.CALL_LAYER))
-		tingrong.ALL_STACK = append(tingrong.ALL_STACK,contractAddr.String())
+		tingrong.CALL_STACK = append(tingrong.CALL_STACK,address.String()+"#"+strconv.Itoa(tingrong.CALL_LAYER))
+		tingrong.ALL_STACK = append(tingrong.ALL_STACK,address.String())
+		evm.enterFrame(collector.MessageCreate, caller.Address(), address, address, code, gas, value)
+		layer := uint64(tingrong.CALL_LAYER)
+		defer func() {
+			evm.exitFrame(ret, gas-leftOverGas, err)
+			evm.reportCreation(caller.Address(), address, code, value, nil, layer, err)
+		}()
 	}
 	//add new 
 	return evm.create(caller, &codeAndHash{code: code}, gas, value, contractAddr)
@@ -571,9 +596,16 @@ func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *
 	contractAddr = crypto.CreateAddress2(caller.Address(), common.BigToHash(salt), codeAndHash.Hash().Bytes())
 	//add new 
 	if evm.isTxStart{
+		address := contractAddr
 		tingrong.CALL_LAYER += 1
-		tingrong.CALL_STACK = append(tingrong.CALL_STACK,contractAddr.String()+"#"+strconv.Itoa(tingrong.CALL_LAYER))
-		tingrong.ALL_STACK = append(tingrong.ALL_STACK,contractAddr.String())
+		tingrong.CALL_STACK = append(tingrong.CALL_STACK,address.String()+"#"+strconv.Itoa(tingrong.CALL_LAYER))
+		tingrong.ALL_STACK = append(tingrong.ALL_STACK,address.String())
+		evm.enterFrame(collector.MessageCreate2, caller.Address(), address, address, code, gas, endowment)
+		layer := uint64(tingrong.CALL_LAYER)
+		defer func() {
+			evm.exitFrame(ret, gas-leftOverGas, err)
+			evm.reportCreation(caller.Address(), address, code, endowment, salt, layer, err)
+		}()
 	}
 	//add new 
 	
@@ -586,3 +618,110 @@ func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
 func (evm *EVM) IsTxStart() bool { return evm.isTxStart }
 
 func (evm *EVM) SetTxStart(flag bool) { evm.isTxStart = flag }
+
+// enterFrame opens the frame of a message in the SODA call tree. Callee is
+// the account the code runs on behalf of and codeAddr the one it is loaded
+// from. The frame id is the call layer the message was pushed with.
+func (evm *EVM) enterFrame(typ string, caller, callee, codeAddr common.Address, input []byte, gas uint64, value *big.Int) {
+	frame := &collector.CallFrame{
+		Type:        typ,
+		CallLayer:   uint64(tingrong.CALL_LAYER),
+		Caller:      caller,
+		Callee:      callee,
+		CodeAddress: codeAddr,
+		Input:       input,
+		Gas:         gas,
+	}
+	if value != nil {
+		frame.Value = collector.BigToWord(value)
+	}
+	tingrong.CALL_TREE.Enter(frame)
+	evm.StateDB.SetJournalFrame(frame.CallLayer)
+
+	// DELEGATECALL and CALLCODE run code on the storage of their caller, e.g.
+	// the implementation behind a proxy, whose transfer the call to the
+	// proxy already reported.
+	if typ == collector.MessageCall && evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTokenTransfer) {
+		if tr := collector.DecodeTokenCall(caller, callee, input); tr != nil {
+			tr.TxHash = common.HexToHash(tingrong.TxHash)
+			tingrong.CALL_TREE.Token(tr)
+		}
+	}
+}
+
+// exitFrame closes the innermost frame of the SODA call tree and hands the
+// state changes that follow back to its parent.
+func (evm *EVM) exitFrame(ret []byte, gasUsed uint64, err error) {
+	tingrong.CALL_TREE.Exit(ret, gasUsed, Failure(err))
+	var frame uint64
+	if parent := tingrong.CALL_TREE.Current(); parent != nil {
+		frame = parent.CallLayer
+	}
+	evm.StateDB.SetJournalFrame(frame)
+}
+
+// reportCreation sends CONTRACT_CREATED for a creation that returned. Salt
+// is nil unless the creation is a CREATE2, and a creation at depth 0 is the
+// transaction itself.
+func (evm *EVM) reportCreation(creator, addr common.Address, code []byte, value, salt *big.Int, layer uint64, err error) {
+	if !evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvContractCreated) {
+		return
+	}
+	creation := &collector.ContractCreation{
+		Type:         collector.CreatedByCreate,
+		TxHash:       common.HexToHash(tingrong.TxHash),
+		BlockNumber:  evm.BlockNumber.Uint64(),
+		Creator:      creator,
+		Deployer:     evm.Origin,
+		Address:      addr,
+		InitCodeHash: crypto.Keccak256Hash(code),
+		Value:        collector.BigToWord(value),
+		Depth:        uint64(evm.depth),
+		CallLayer:    layer,
+		Success:      err == nil,
+		Failure:      Failure(err),
+	}
+	switch {
+	case salt != nil:
+		creation.Type, creation.Salt = collector.CreatedByCreate2, collector.BigToWord(salt)
+	case evm.depth == 0:
+		creation.Type = collector.CreatedByTx
+	}
+	if err == nil {
+		creation.RuntimeCode = evm.StateDB.GetCode(addr)
+	}
+	evm.ChainConfig().TransferDataPlg.SendEvent(pluginManage.EvContractCreated, creation.SendContractCreatedEvent())
+}
+
+// runPrecompile runs a precompiled contract and reports the call as
+// PRECOMPILE.
+func (evm *EVM) runPrecompile(p PrecompiledContract, input []byte, contract *Contract) ([]byte, error) {
+	ret, err := RunPrecompiledContract(p, input, contract)
+
+	call := collector.NewPrecompileCall(*contract.CodeAddr, input, ret)
+	call.TxHash = common.HexToHash(tingrong.TxHash)
+	call.Caller = contract.Caller()
+	call.Gas = p.RequiredGas(input)
+	call.Failure = Failure(err)
+	if frame := tingrong.CALL_TREE.Current(); frame != nil {
+		call.CallLayer = frame.CallLayer
+	}
+	evm.ChainConfig().TransferDataPlg.SendEvent(pluginManage.EvPrecompile, call.SendPrecompileEvent())
+	return ret, err
+}
+
+// recordTransfer adds ether moved by the current frame to the SODA call tree,
+// which delivers it as BALANCE_TRANSFER once the transaction ended.
+func (evm *EVM) recordTransfer(reason string, from, to common.Address, amount *big.Int) {
+	if !evm.isTxStart || amount.Sign() == 0 || !evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvBalanceTransfer) {
+		return
+	}
+	tingrong.CALL_TREE.Transfer(&collector.BalanceTransfer{
+		Reason:      reason,
+		TxHash:      common.HexToHash(tingrong.TxHash),
+		BlockNumber: evm.BlockNumber.Uint64(),
+		From:        from,
+		To:          to,
+		Amount:      collector.BigToWord(amount),
+	})
+}
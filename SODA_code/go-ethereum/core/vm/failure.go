@@ -0,0 +1,49 @@
+package vm
+
+//add new file
+
+import (
+	"strings"
+
+	"github.com/ethereum/collector"
+)
+
+// Failure maps an execution error to its SODA failure cause, one of the
+// collector.Failure* constants, or to the empty cause for a nil error.
+func Failure(err error) string {
+	switch err {
+	case nil:
+		return ""
+	case ErrOutOfGas, ErrCodeStoreOutOfGas, errGasUintOverflow:
+		return collector.FailureOutOfGas
+	case errExecutionReverted:
+		return collector.FailureRevert
+	case errWriteProtection:
+		return collector.FailureWriteProtection
+	case errInvalidJump:
+		return collector.FailureInvalidJump
+	case ErrDepth:
+		return collector.FailureDepth
+	case ErrInsufficientBalance:
+		return collector.FailureInsufficientBalance
+	}
+	// The interpreter formats the errors of invalid opcodes and of stack
+	// violations.
+	switch msg := err.Error(); {
+	case strings.HasPrefix(msg, "invalid opcode"):
+		return collector.FailureInvalidOpcode
+	case strings.HasPrefix(msg, "stack underflow"), strings.HasPrefix(msg, "stack limit reached"):
+		return collector.FailureStack
+	}
+	return collector.FailureOther
+}
+
+// revertReason returns the Error(string) message of the data returned by a
+// reverted call or creation.
+func revertReason(err error, ret []byte) string {
+	if err != errExecutionReverted {
+		return ""
+	}
+	reason, _ := collector.RevertReason(ret)
+	return reason
+}
@@ -0,0 +1,61 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/collector"
+)
+
+func TestFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{ErrOutOfGas, collector.FailureOutOfGas},
+		{ErrCodeStoreOutOfGas, collector.FailureOutOfGas},
+		{errGasUintOverflow, collector.FailureOutOfGas},
+		{errExecutionReverted, collector.FailureRevert},
+		{errWriteProtection, collector.FailureWriteProtection},
+		{errInvalidJump, collector.FailureInvalidJump},
+		{ErrDepth, collector.FailureDepth},
+		{ErrInsufficientBalance, collector.FailureInsufficientBalance},
+		{fmt.Errorf("invalid opcode 0x%x", int(0xfe)), collector.FailureInvalidOpcode},
+		{fmt.Errorf("stack underflow (%d <=> %d)", 0, 1), collector.FailureStack},
+		{fmt.Errorf("stack limit reached %d (%d)", 1025, 1024), collector.FailureStack},
+		{ErrContractAddressCollision, collector.FailureOther},
+		{errors.New("unknown"), collector.FailureOther},
+	}
+	for i, tt := range tests {
+		if have := Failure(tt.err); have != tt.want {
+			t.Errorf("test %d (%v): have %q, want %q", i, tt.err, have, tt.want)
+		}
+	}
+}
+
+func TestRevertReason(t *testing.T) {
+	// Error("no") as encoded by solidity.
+	reason := make([]byte, 100)
+	copy(reason, []byte{0x08, 0xc3, 0x79, 0xa0})
+	reason[35], reason[67] = 0x20, 2
+	copy(reason[68:], "no")
+
+	tests := []struct {
+		err  error
+		ret  []byte
+		want string
+	}{
+		{errExecutionReverted, reason, "no"},
+		{errExecutionReverted, nil, ""},
+		{errExecutionReverted, reason[:40], ""},
+		{ErrOutOfGas, reason, ""},
+		{nil, reason, ""},
+	}
+	for i, tt := range tests {
+		if have := revertReason(tt.err, tt.ret); have != tt.want {
+			t.Errorf("test %d: have %q, want %q", i, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package vm
+
+//add new file
+
+import (
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// forwardGas accounts the gas a call or create hands to its callee. The
+// dynamic cost of a call includes the forwarded gas, charged, which the
+// instruction doesn't consume itself; forwarded adds the stipend of a call
+// sending value.
+func forwardGas(e *collector.InsEvent, forwarded, charged uint64) {
+	if charged <= e.DynamicGas {
+		e.DynamicGas -= charged
+	}
+	e.GasForwarded = forwarded
+	e.AllocatedGas = forwarded
+	e.RealGasUsed = e.StaticGas + e.DynamicGas
+}
+
+// endGasEvent starts the event reported when a call or create returns. It
+// keeps the costs and the frame of the instruction, and RealGasUsed becomes
+// the gas the callee consumed.
+func endGasEvent(start *collector.InsEvent, returned uint64) *collector.InsEvent {
+	e := collector.NewInsEvent()
+	e.GasBefore = start.GasBefore
+	e.StaticGas = start.StaticGas
+	e.DynamicGas = start.DynamicGas
+	e.GasForwarded = start.GasForwarded
+	e.GasReturned = returned
+	e.RefundBefore = start.RefundBefore
+	e.FrameType, e.CodeAddress, e.StorageAddress = start.FrameType, start.CodeAddress, start.StorageAddress
+	e.Sender, e.CallValue = start.Sender, start.CallValue
+	if returned <= start.GasForwarded {
+		e.RealGasUsed = start.GasForwarded - returned
+	}
+	return e
+}
+
+// countRefund adds the change of the refund counter by an instruction to the
+// running frame.
+func countRefund(before, after uint64) {
+	frame := tingrong.CALL_TREE.Current()
+	if frame == nil {
+		return
+	}
+	if after > before {
+		frame.RefundAdded += after - before
+	} else {
+		frame.RefundRemoved += before - after
+	}
+}
@@ -0,0 +1,97 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+func TestForwardGas(t *testing.T) {
+	tests := []struct {
+		static, dynamic    uint64
+		forwarded, charged uint64
+		wantDynamic        uint64
+	}{
+		// A call forwarding 1000 gas without value.
+		{0, 1700, 1000, 1000, 700},
+		// A call sending value gets the stipend on top of what it was charged.
+		{0, 10700 + 1000, 1000 + 2300, 1000, 10700},
+		// A create is charged nothing for the gas it forwards.
+		{32000, 0, 5000, 0, 0},
+		// A charge above the dynamic cost leaves it untouched.
+		{0, 500, 1000, 1000, 500},
+	}
+	for i, tt := range tests {
+		e := &collector.InsEvent{StaticGas: tt.static, DynamicGas: tt.dynamic}
+		forwardGas(e, tt.forwarded, tt.charged)
+		if e.DynamicGas != tt.wantDynamic || e.GasForwarded != tt.forwarded || e.AllocatedGas != tt.forwarded ||
+			e.RealGasUsed != tt.static+tt.wantDynamic {
+			t.Errorf("test %d: unexpected event %+v", i, e)
+		}
+	}
+}
+
+func TestEndGasEvent(t *testing.T) {
+	start := &collector.InsEvent{
+		GasBefore:      10000,
+		DynamicGas:     700,
+		GasForwarded:   3000,
+		RefundBefore:   15000,
+		FrameType:      collector.MessageDelegateCall,
+		CodeAddress:    common.Address{1},
+		StorageAddress: common.Address{2},
+		Sender:         common.Address{3},
+		CallValue:      collector.Uint64ToWord(5),
+	}
+	tests := []struct {
+		returned uint64
+		used     uint64
+	}{
+		{1000, 2000},
+		{3000, 0},
+		{0, 3000},
+		// More returned than forwarded can't be told apart.
+		{4000, 0},
+	}
+	for i, tt := range tests {
+		e := endGasEvent(start, tt.returned)
+		if e.RealGasUsed != tt.used || e.GasReturned != tt.returned {
+			t.Errorf("test %d: have used %d returned %d, want %d %d", i, e.RealGasUsed, e.GasReturned, tt.used, tt.returned)
+		}
+		if e.GasBefore != start.GasBefore || e.DynamicGas != start.DynamicGas || e.GasForwarded != start.GasForwarded ||
+			e.RefundBefore != start.RefundBefore {
+			t.Errorf("test %d: costs not kept: %+v", i, e)
+		}
+		if e.FrameType != start.FrameType || e.CodeAddress != start.CodeAddress || e.StorageAddress != start.StorageAddress ||
+			e.Sender != start.Sender || e.CallValue != start.CallValue {
+			t.Errorf("test %d: frame not kept: %+v", i, e)
+		}
+	}
+}
+
+func TestCountRefund(t *testing.T) {
+	defer tingrong.CALL_TREE.Reset()
+
+	tingrong.CALL_TREE.Reset()
+	// Outside of execution there is no frame to count for.
+	countRefund(0, 100)
+
+	frame := new(collector.CallFrame)
+	tingrong.CALL_TREE.Enter(frame)
+	tests := []struct {
+		before, after uint64
+	}{
+		{0, 15000},
+		{15000, 30000},
+		{30000, 15000},
+		{15000, 15000},
+	}
+	for _, tt := range tests {
+		countRefund(tt.before, tt.after)
+	}
+	if frame.RefundAdded != 30000 || frame.RefundRemoved != 15000 {
+		t.Errorf("have added %d removed %d, want 30000 15000", frame.RefundAdded, frame.RefundRemoved)
+	}
+}
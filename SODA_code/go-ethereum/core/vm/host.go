@@ -0,0 +1,119 @@
+package vm
+
+//add new file
+
+import (
+	"math/big"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HeaderChain is the part of core.ChainContext the plugin host reads
+// headers from.
+type HeaderChain interface {
+	GetHeader(common.Hash, uint64) *types.Header
+}
+
+// host implements collector.Host over the state and chain a transaction
+// runs on. It keeps them unexported so handlers can't reach the writers.
+type host struct {
+	db     StateDB
+	chain  HeaderChain   // nil if only the current header is known
+	header *types.Header // block being processed
+}
+
+// NewHost returns the plugin host for a transaction executed on db in the
+// block of header. Chain may be nil, leaving only header to read.
+func NewHost(db StateDB, chain HeaderChain, header *types.Header) collector.Host {
+	return &host{db: db, chain: chain, header: header}
+}
+
+func (h *host) GetBalance(addr common.Address) *big.Int {
+	return new(big.Int).Set(h.db.GetBalance(addr))
+}
+
+func (h *host) GetCode(addr common.Address) []byte {
+	return common.CopyBytes(h.db.GetCode(addr))
+}
+
+func (h *host) GetCodeHash(addr common.Address) common.Hash {
+	return h.db.GetCodeHash(addr)
+}
+
+func (h *host) GetState(addr common.Address, key common.Hash) common.Hash {
+	return h.db.GetState(addr, key)
+}
+
+func (h *host) GetNonce(addr common.Address) uint64 {
+	return h.db.GetNonce(addr)
+}
+
+func (h *host) Exist(addr common.Address) bool {
+	return h.db.Exist(addr)
+}
+
+func (h *host) GetHeader(hash common.Hash, number uint64) *collector.BlockEvent {
+	if h.header != nil && h.header.Number.Uint64() == number && h.header.Hash() == hash {
+		return NewBlockEvent(h.header)
+	}
+	if h.chain == nil {
+		return nil
+	}
+	if header := h.chain.GetHeader(hash, number); header != nil {
+		return NewBlockEvent(header)
+	}
+	return nil
+}
+
+// GetHeaderByNumber follows the parents of the current header, as the block
+// being processed isn't part of the canonical chain yet.
+func (h *host) GetHeaderByNumber(number uint64) *collector.BlockEvent {
+	header := h.header
+	if header == nil || header.Number.Uint64() < number {
+		return nil
+	}
+	if chain, ok := h.chain.(interface {
+		GetHeaderByNumber(uint64) *types.Header
+	}); ok && header.Number.Uint64() > number {
+		// Canonical lookups are only right below the current block if
+		// it extends the canonical chain.
+		if parent := chain.GetHeaderByNumber(header.Number.Uint64() - 1); parent != nil && parent.Hash() == header.ParentHash {
+			if header = chain.GetHeaderByNumber(number); header == nil {
+				return nil
+			}
+			return NewBlockEvent(header)
+		}
+	}
+	for header.Number.Uint64() > number {
+		if h.chain == nil {
+			return nil
+		}
+		if header = h.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1); header == nil {
+			return nil
+		}
+	}
+	return NewBlockEvent(header)
+}
+
+// NewBlockEvent describes the header for the plugins.
+func NewBlockEvent(header *types.Header) *collector.BlockEvent {
+	return &collector.BlockEvent{
+		Number:      header.Number.Uint64(),
+		ParentHash:  header.ParentHash,
+		UncleHash:   header.UncleHash,
+		Coinbase:    header.Coinbase,
+		StateRoot:   header.Root,
+		TxHashRoot:  header.TxHash,
+		ReceiptHash: header.ReceiptHash,
+		Bloom:       header.Bloom.Bytes(),
+		Difficulty:  collector.BigToWord(header.Difficulty),
+		GasLimit:    header.GasLimit,
+		GasUsed:     header.GasUsed,
+		Time:        header.Time,
+		Extra:       common.CopyBytes(header.Extra),
+		MixDigest:   header.MixDigest,
+		Nonce:       header.Nonce.Uint64(),
+	}
+}
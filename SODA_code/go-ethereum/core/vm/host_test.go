@@ -0,0 +1,55 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// testHeaderChain serves the headers it holds by hash and number.
+type testHeaderChain map[common.Hash]*types.Header
+
+func (c testHeaderChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c[hash]; header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+func TestHostHeaders(t *testing.T) {
+	chain := make(testHeaderChain)
+	var parent common.Hash
+	for i := int64(0); i < 3; i++ {
+		header := &types.Header{Number: big.NewInt(i), ParentHash: parent, Difficulty: big.NewInt(1), Extra: []byte{byte(i)}}
+		chain[header.Hash()] = header
+		parent = header.Hash()
+	}
+	current := &types.Header{Number: big.NewInt(3), ParentHash: parent, Difficulty: big.NewInt(1)}
+	host := NewHost(nil, chain, current)
+
+	for i := uint64(0); i <= 3; i++ {
+		if header := host.GetHeaderByNumber(i); header == nil || header.Number != i {
+			t.Errorf("header %d: have %+v", i, header)
+		}
+	}
+	if header := host.GetHeaderByNumber(4); header != nil {
+		t.Errorf("have future header %+v", header)
+	}
+	if header := host.GetHeader(current.Hash(), 3); header == nil || header.ParentHash != parent {
+		t.Errorf("unexpected current header %+v", header)
+	}
+	if header := host.GetHeader(parent, 2); header == nil || header.Extra[0] != 2 {
+		t.Errorf("unexpected parent header %+v", header)
+	}
+	if header := host.GetHeader(parent, 1); header != nil {
+		t.Errorf("have header %+v for a mismatching number", header)
+	}
+
+	// Without a chain only the current header is known.
+	host = NewHost(nil, nil, current)
+	if host.GetHeaderByNumber(3) == nil || host.GetHeaderByNumber(2) != nil {
+		t.Error("unexpected headers without a chain")
+	}
+}
@@ -18,7 +18,6 @@ package vm
 
 import (
 	"errors"
-	"fmt"
 	"github.com/ethereum/collector"
 	"math/big"
 	"strings"
@@ -30,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"golang.org/x/crypto/sha3"
 	"github.com/ethereum/go-ethereum/tingrong"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
 )
 
 var (
@@ -45,13 +45,13 @@ var (
 func opAdd(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	math.U256(y.Add(x, y))
 
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -59,13 +59,13 @@ func opAdd(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opSub(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	math.U256(y.Sub(x, y))
 
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -73,14 +73,14 @@ func opSub(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opMul(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.pop()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 
 	stack.push(math.U256(x.Mul(x, y)))
 
 	interpreter.intPool.put(y)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = x.String()
+		stack.collector.SetResult(x)
 	}
 	return nil, nil
 }
@@ -88,7 +88,7 @@ func opMul(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opDiv(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	if y.Sign() != 0 {
 		math.U256(y.Div(x, y))
@@ -97,7 +97,7 @@ func opDiv(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	}
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -119,8 +119,8 @@ func opSdiv(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 	}
 	interpreter.intPool.put(x, y)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.AddArgs(x, y)
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -128,7 +128,7 @@ func opSdiv(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 func opMod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.pop()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	if y.Sign() == 0 {
 		stack.push(x.SetUint64(0))
@@ -137,7 +137,7 @@ func opMod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	}
 	interpreter.intPool.put(y)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = x.String()
+		stack.collector.SetResult(x)
 	}
 	return nil, nil
 }
@@ -159,8 +159,8 @@ func opSmod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 	}
 	interpreter.intPool.put(x, y)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.AddArgs(x, y)
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -168,7 +168,7 @@ func opSmod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 func opExp(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	base, exponent := stack.pop(), stack.pop()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, base.String(), exponent.String())
+		stack.collector.AddArgs(base, exponent)
 	}
 	// some shortcuts
 	cmpToOne := exponent.Cmp(big1)
@@ -189,7 +189,7 @@ func opExp(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	stack.push(res)
 	interpreter.intPool.put(exponent)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -197,14 +197,14 @@ func opExp(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opSignExtend(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	back := stack.pop()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, back.String())
+		stack.collector.AddArgs(back)
 	}
 
 	if back.Cmp(big.NewInt(31)) < 0 {
 		bit := uint(back.Uint64()*8 + 7)
 		num := stack.pop()
 		if stack.flag {
-			stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, num.String())
+			stack.collector.AddArgs(num)
 		}
 		mask := back.Lsh(common.Big1, bit)
 		mask.Sub(mask, common.Big1)
@@ -216,7 +216,7 @@ func opSignExtend(pc *uint64, interpreter *EVMInterpreter, contract *Contract, m
 		value := math.U256(num)
 		stack.push(value)
 		if stack.flag {
-			stack.collector.OpInOut.OpResult = value.String()
+			stack.collector.SetResult(value)
 		}
 	}
 	interpreter.intPool.put(back)
@@ -226,12 +226,12 @@ func opSignExtend(pc *uint64, interpreter *EVMInterpreter, contract *Contract, m
 func opNot(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x := stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String())
+		stack.collector.AddArgs(x)
 	}
 
 	math.U256(x.Not(x))
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = x.String()
+		stack.collector.SetResult(x)
 	}
 	return nil, nil
 }
@@ -239,7 +239,7 @@ func opNot(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opLt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	if x.Cmp(y) < 0 {
 		y.SetUint64(1)
@@ -248,7 +248,7 @@ func opLt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *M
 	}
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -256,7 +256,7 @@ func opLt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *M
 func opGt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	if x.Cmp(y) > 0 {
 		y.SetUint64(1)
@@ -265,7 +265,7 @@ func opGt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *M
 	}
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -273,7 +273,7 @@ func opGt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *M
 func opSlt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	xSign := x.Cmp(tt255)
 	ySign := y.Cmp(tt255)
@@ -294,7 +294,7 @@ func opSlt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	}
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -302,7 +302,7 @@ func opSlt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opSgt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	xSign := x.Cmp(tt255)
 	ySign := y.Cmp(tt255)
@@ -324,7 +324,7 @@ func opSgt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	interpreter.intPool.put(x)
 
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -332,7 +332,7 @@ func opSgt(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opEq(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	if x.Cmp(y) == 0 {
 		y.SetUint64(1)
@@ -341,7 +341,7 @@ func opEq(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *M
 	}
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -349,7 +349,7 @@ func opEq(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *M
 func opIszero(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x := stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String())
+		stack.collector.AddArgs(x)
 	}
 	if x.Sign() > 0 {
 		x.SetUint64(0)
@@ -357,7 +357,7 @@ func opIszero(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 		x.SetUint64(1)
 	}
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = x.String()
+		stack.collector.SetResult(x)
 	}
 	return nil, nil
 }
@@ -365,13 +365,13 @@ func opIszero(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 func opAnd(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.pop()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	stack.push(x.And(x, y))
 
 	interpreter.intPool.put(y)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = x.String()
+		stack.collector.SetResult(x)
 	}
 	return nil, nil
 }
@@ -379,13 +379,13 @@ func opAnd(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opOr(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	y.Or(x, y)
 
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -393,13 +393,13 @@ func opOr(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *M
 func opXor(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String())
+		stack.collector.AddArgs(x, y)
 	}
 	y.Xor(x, y)
 
 	interpreter.intPool.put(x)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = y.String()
+		stack.collector.SetResult(y)
 	}
 	return nil, nil
 }
@@ -407,7 +407,7 @@ func opXor(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 func opByte(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	th, val := stack.pop(), stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, th.String(), val.String())
+		stack.collector.AddArgs(th, val)
 	}
 	if th.Cmp(common.Big32) < 0 {
 		b := math.Byte(val, 32, int(th.Int64()))
@@ -417,7 +417,7 @@ func opByte(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 	}
 	interpreter.intPool.put(th)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = val.String()
+		stack.collector.SetResult(val)
 	}
 	return nil, nil
 }
@@ -425,7 +425,7 @@ func opByte(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 func opAddmod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y, z := stack.pop(), stack.pop(), stack.pop()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String(), z.String())
+		stack.collector.AddArgs(x, y, z)
 	}
 	if z.Cmp(bigZero) > 0 {
 		x.Add(x, y)
@@ -436,7 +436,7 @@ func opAddmod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	}
 	interpreter.intPool.put(y, z)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = x.String()
+		stack.collector.SetResult(x)
 	}
 	return nil, nil
 }
@@ -444,7 +444,7 @@ func opAddmod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 func opMulmod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	x, y, z := stack.pop(), stack.pop(), stack.pop()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, x.String(), y.String(), z.String())
+		stack.collector.AddArgs(x, y, z)
 	}
 	if z.Cmp(bigZero) > 0 {
 		x.Mul(x, y)
@@ -455,7 +455,7 @@ func opMulmod(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	}
 	interpreter.intPool.put(y, z)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = x.String()
+		stack.collector.SetResult(x)
 	}
 	return nil, nil
 }
@@ -467,7 +467,7 @@ func opSHL(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	// Note, second operand is left in the stack; accumulate result into it, and no need to push it afterwards
 	shift, value := math.U256(stack.pop()), math.U256(stack.peek())
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, shift.String(), value.String())
+		stack.collector.AddArgs(shift, value)
 	}
 	defer interpreter.intPool.put(shift) // First operand back into the pool
 
@@ -478,7 +478,7 @@ func opSHL(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	n := uint(shift.Uint64())
 	math.U256(value.Lsh(value, n))
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = value.String()
+		stack.collector.SetResult(value)
 	}
 	return nil, nil
 }
@@ -490,7 +490,7 @@ func opSHR(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	// Note, second operand is left in the stack; accumulate result into it, and no need to push it afterwards
 	shift, value := math.U256(stack.pop()), math.U256(stack.peek())
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, shift.String(), value.String())
+		stack.collector.AddArgs(shift, value)
 	}
 	defer interpreter.intPool.put(shift) // First operand back into the pool
 
@@ -501,7 +501,7 @@ func opSHR(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	n := uint(shift.Uint64())
 	math.U256(value.Rsh(value, n))
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = value.String()
+		stack.collector.SetResult(value)
 	}
 	return nil, nil
 }
@@ -513,7 +513,7 @@ func opSAR(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	// Note, S256 returns (potentially) a new bigint, so we're popping, not peeking this one
 	shift, value := math.U256(stack.pop()), math.S256(stack.pop())
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, shift.String(), value.String())
+		stack.collector.AddArgs(shift, value)
 	}
 	defer interpreter.intPool.put(shift) // First operand back into the pool
 
@@ -525,7 +525,7 @@ func opSAR(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 		}
 		stack.push(math.U256(value))
 		if stack.flag {
-			stack.collector.OpInOut.OpResult = value.String()
+			stack.collector.SetResult(value)
 		}
 		return nil, nil
 	}
@@ -533,7 +533,7 @@ func opSAR(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	value.Rsh(value, n)
 	stack.push(math.U256(value))
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = value.String()
+		stack.collector.SetResult(value)
 	}
 	return nil, nil
 }
@@ -554,14 +554,19 @@ func opSha3(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 	if evm.vmConfig.EnablePreimageRecording {
 		evm.StateDB.AddPreimage(interpreter.hasherBuf, data)
 	}
+	//add new 
+	if evm.tracksSlots() {
+		tingrong.PREIMAGES.Add(interpreter.hasherBuf, data)
+	}
+	//add new 
 	res := interpreter.intPool.get().SetBytes(interpreter.hasherBuf[:])
 	stack.push(res)
 
 	interpreter.intPool.put(offset, size)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, offset.String(), size.String())
-		stack.collector.OpInOut.InputData = data
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.AddArgs(offset, size)
+		stack.collector.InputData = data
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -570,7 +575,7 @@ func opAddress(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memo
 	res := interpreter.intPool.get().SetBytes(contract.Address().Bytes())
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -578,11 +583,11 @@ func opAddress(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memo
 func opBalance(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	slot := stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, slot.String())
+		stack.collector.AddArgs(slot)
 	}
 	slot.Set(interpreter.evm.StateDB.GetBalance(common.BigToAddress(slot)))
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = slot.String()
+		stack.collector.SetResult(slot)
 	}
 	return nil, nil
 }
@@ -591,7 +596,7 @@ func opOrigin(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	val := interpreter.intPool.get().SetBytes(interpreter.evm.Origin.Bytes())
 	stack.push(val)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = val.String()
+		stack.collector.SetResult(val)
 	}
 	return nil, nil
 }
@@ -600,7 +605,7 @@ func opCaller(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	val := interpreter.intPool.get().SetBytes(contract.Caller().Bytes())
 	stack.push(val)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = val.String()
+		stack.collector.SetResult(val)
 	}
 	return nil, nil
 }
@@ -609,7 +614,7 @@ func opCallValue(pc *uint64, interpreter *EVMInterpreter, contract *Contract, me
 	val := interpreter.intPool.get().Set(contract.value)
 	stack.push(val)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = val.String()
+		stack.collector.SetResult(val)
 	}
 	return nil, nil
 }
@@ -620,9 +625,9 @@ func opCallDataLoad(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 	res := interpreter.intPool.get().SetBytes(by)
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, p.String())
-		stack.collector.OpInOut.InputData = by
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.AddArgs(p)
+		stack.collector.InputData = by
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -631,7 +636,7 @@ func opCallDataSize(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 	res := interpreter.intPool.get().SetInt64(int64(len(contract.Input)))
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -648,9 +653,9 @@ func opCallDataCopy(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 
 	interpreter.intPool.put(memOffset, dataOffset, length)
 	if stack.flag {
-		stack.collector.OpInOut.MemoryData = pre
-		stack.collector.OpInOut.RetArgs = res
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, memOffset.String(), dataOffset.String(), length.String())
+		stack.collector.MemoryData = pre
+		stack.collector.RetArgs = res
+		stack.collector.AddArgs(memOffset, dataOffset, length)
 	}
 	return nil, nil
 }
@@ -659,7 +664,7 @@ func opReturnDataSize(pc *uint64, interpreter *EVMInterpreter, contract *Contrac
 	res := interpreter.intPool.get().SetUint64(uint64(len(interpreter.returnData)))
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -678,13 +683,13 @@ func opReturnDataCopy(pc *uint64, interpreter *EVMInterpreter, contract *Contrac
 		return nil, errReturnDataOutOfBounds
 	}
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, memOffset.String(), dataOffset.String(), length.String())
-		stack.collector.OpInOut.MemoryData = memory.Get(memOffset.Int64(), length.Int64())
+		stack.collector.AddArgs(memOffset, dataOffset, length)
+		stack.collector.MemoryData = memory.Get(memOffset.Int64(), length.Int64())
 	}
 	res := interpreter.returnData[dataOffset.Uint64():end.Uint64()]
 	memory.Set(memOffset.Uint64(), length.Uint64(), res)
 	if stack.flag {
-		stack.collector.OpInOut.RetArgs = res
+		stack.collector.RetArgs = res
 	}
 	return nil, nil
 }
@@ -692,11 +697,11 @@ func opReturnDataCopy(pc *uint64, interpreter *EVMInterpreter, contract *Contrac
 func opExtCodeSize(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	slot := stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, slot.String())
+		stack.collector.AddArgs(slot)
 	}
 	slot.SetUint64(uint64(interpreter.evm.StateDB.GetCodeSize(common.BigToAddress(slot))))
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = slot.String()
+		stack.collector.SetResult(slot)
 	}
 	return nil, nil
 }
@@ -705,7 +710,7 @@ func opCodeSize(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	l := interpreter.intPool.get().SetInt64(int64(len(contract.Code)))
 	stack.push(l)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = l.String()
+		stack.collector.SetResult(l)
 	}
 	return nil, nil
 }
@@ -717,15 +722,15 @@ func opCodeCopy(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 		length     = stack.pop()
 	)
 	if stack.flag {
-		stack.collector.OpInOut.MemoryData = memory.Get(memOffset.Int64(), length.Int64())
+		stack.collector.MemoryData = memory.Get(memOffset.Int64(), length.Int64())
 	}
 	codeCopy := getDataBig(contract.Code, codeOffset, length)
 	memory.Set(memOffset.Uint64(), length.Uint64(), codeCopy)
 
 	interpreter.intPool.put(memOffset, codeOffset, length)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, memOffset.String(), codeOffset.String(), length.String())
-		stack.collector.OpInOut.RetArgs = codeCopy
+		stack.collector.AddArgs(memOffset, codeOffset, length)
+		stack.collector.RetArgs = codeCopy
 	}
 	return nil, nil
 }
@@ -738,15 +743,16 @@ func opExtCodeCopy(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 		length     = stack.pop()
 	)
 	if stack.flag {
-		stack.collector.OpInOut.MemoryData = memory.Get(memOffset.Int64(), length.Int64())
+		stack.collector.MemoryData = memory.Get(memOffset.Int64(), length.Int64())
 	}
 	codeCopy := getDataBig(interpreter.evm.StateDB.GetCode(addr), codeOffset, length)
 	memory.Set(memOffset.Uint64(), length.Uint64(), codeCopy)
 
 	interpreter.intPool.put(memOffset, codeOffset, length)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, addr.String(), memOffset.String(), codeOffset.String(), length.String())
-		stack.collector.OpInOut.RetArgs = codeCopy
+		stack.collector.AddWords(collector.AddressToWord(addr))
+		stack.collector.AddArgs(memOffset, codeOffset, length)
+		stack.collector.RetArgs = codeCopy
 	}
 	return nil, nil
 }
@@ -781,7 +787,7 @@ func opExtCodeHash(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 	slot := stack.peek()
 	address := common.BigToAddress(slot)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, slot.String())
+		stack.collector.AddArgs(slot)
 	}
 	if interpreter.evm.StateDB.Empty(address) {
 		slot.SetUint64(0)
@@ -789,7 +795,7 @@ func opExtCodeHash(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 		slot.SetBytes(interpreter.evm.StateDB.GetCodeHash(address).Bytes())
 	}
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = slot.String()
+		stack.collector.SetResult(slot)
 	}
 	return nil, nil
 }
@@ -798,7 +804,7 @@ func opGasprice(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	res := interpreter.intPool.get().Set(interpreter.evm.GasPrice)
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -808,7 +814,7 @@ func opBlockhash(pc *uint64, interpreter *EVMInterpreter, contract *Contract, me
 
 	n := interpreter.intPool.get().Sub(interpreter.evm.BlockNumber, common.Big257)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, num.String())
+		stack.collector.AddArgs(num)
 	}
 	var p *big.Int
 	if num.Cmp(n) > 0 && num.Cmp(interpreter.evm.BlockNumber) < 0 {
@@ -819,7 +825,7 @@ func opBlockhash(pc *uint64, interpreter *EVMInterpreter, contract *Contract, me
 	stack.push(p)
 	interpreter.intPool.put(num, n)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = p.String()
+		stack.collector.SetResult(p)
 	}
 	return nil, nil
 }
@@ -828,7 +834,7 @@ func opCoinbase(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	res := interpreter.intPool.get().SetBytes(interpreter.evm.Coinbase.Bytes())
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -837,7 +843,7 @@ func opTimestamp(pc *uint64, interpreter *EVMInterpreter, contract *Contract, me
 	res := math.U256(interpreter.intPool.get().Set(interpreter.evm.Time))
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -846,7 +852,7 @@ func opNumber(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	res := math.U256(interpreter.intPool.get().Set(interpreter.evm.BlockNumber))
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -855,7 +861,7 @@ func opDifficulty(pc *uint64, interpreter *EVMInterpreter, contract *Contract, m
 	res := math.U256(interpreter.intPool.get().Set(interpreter.evm.Difficulty))
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -864,7 +870,7 @@ func opGasLimit(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	res := math.U256(interpreter.intPool.get().SetUint64(interpreter.evm.GasLimit))
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -873,7 +879,7 @@ func opPop(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	res := stack.pop()
 	interpreter.intPool.put(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, res.String())
+		stack.collector.AddArgs(res)
 	}
 	return nil, nil
 }
@@ -885,8 +891,8 @@ func opMload(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 
 	interpreter.intPool.put(offset)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, offset.String())
-		stack.collector.OpInOut.OpResult = val.String()
+		stack.collector.AddArgs(offset)
+		stack.collector.SetResult(val)
 	}
 	return nil, nil
 }
@@ -896,14 +902,14 @@ func opMstore(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	mStart, val := stack.pop(), stack.pop()
 
 	if stack.flag {
-		stack.collector.OpInOut.MemoryData = memory.Get(mStart.Int64(), 32)
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, mStart.String(), val.String())
+		stack.collector.MemoryData = memory.Get(mStart.Int64(), 32)
+		stack.collector.AddArgs(mStart, val)
 	}
 	memory.Set32(mStart.Uint64(), val)
 
 	interpreter.intPool.put(mStart, val)
 	if stack.flag {
-		stack.collector.OpInOut.RetArgs = memory.Get(mStart.Int64(), 32)
+		stack.collector.RetArgs = memory.Get(mStart.Int64(), 32)
 	}
 	return nil, nil
 }
@@ -911,13 +917,13 @@ func opMstore(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 func opMstore8(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	off, val := stack.pop().Int64(), stack.pop().Int64()
 	if stack.flag {
-		stack.collector.OpInOut.MemoryData = []byte{memory.store[off]}
+		stack.collector.MemoryData = []byte{memory.store[off]}
 	}
 	res := byte(val & 0xff)
 	memory.store[off] = res
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, (big.NewInt(off)).String(), (big.NewInt(val)).String())
-		stack.collector.OpInOut.RetArgs = []byte{res}
+		stack.collector.AddArgs(big.NewInt(off), big.NewInt(val))
+		stack.collector.RetArgs = []byte{res}
 	}
 	return nil, nil
 }
@@ -925,12 +931,15 @@ func opMstore8(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memo
 func opSload(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	loc := stack.peek()
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, loc.String())
+		stack.collector.AddArgs(loc)
+	}
+	if stack.flag {
+		stack.collector.Slot = explainSlot(contract, common.BigToHash(loc), false)
 	}
 	val := interpreter.evm.StateDB.GetState(contract.Address(), common.BigToHash(loc))
 	loc.SetBytes(val.Bytes())
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = loc.String()
+		stack.collector.SetResult(loc)
 	}
 
 	return nil, nil
@@ -940,12 +949,14 @@ func opSstore(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	loc := common.BigToHash(stack.pop())
 	val := stack.pop()
 	if stack.flag {
-		stack.collector.StoreValue.PreValue = interpreter.evm.StateDB.GetState(contract.Address(), loc).Big().String()
+		stack.collector.PreValue = interpreter.evm.StateDB.GetState(contract.Address(), loc)
 	}
 	interpreter.evm.StateDB.SetState(contract.Address(), loc, common.BigToHash(val))
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, loc.String(), val.String())
-		stack.collector.StoreValue.CurrentValue = val.String()
+		stack.collector.AddWords(collector.Word(loc))
+		stack.collector.AddArgs(val)
+		stack.collector.CurrentValue = common.BigToHash(val)
+		stack.collector.Slot = explainSlot(contract, loc, true)
 	}
 	interpreter.intPool.put(val)
 	return nil, nil
@@ -960,7 +971,7 @@ func opJump(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 
 	interpreter.intPool.put(pos)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, pos.String())
+		stack.collector.AddArgs(pos)
 	}
 	return nil, nil
 }
@@ -978,7 +989,7 @@ func opJumpi(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 
 	interpreter.intPool.put(pos, cond)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, pos.String(), cond.String())
+		stack.collector.AddArgs(pos, cond)
 	}
 	return nil, nil
 }
@@ -991,7 +1002,7 @@ func opPc(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *M
 	res := interpreter.intPool.get().SetUint64(*pc)
 	stack.push(res)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = res.String()
+		stack.collector.SetResult(res)
 	}
 	return nil, nil
 }
@@ -1000,7 +1011,7 @@ func opMsize(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 	val := interpreter.intPool.get().SetInt64(int64(memory.Len()))
 	stack.push(val)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = val.String()
+		stack.collector.SetResult(val)
 	}
 	return nil, nil
 }
@@ -1009,7 +1020,7 @@ func opGas(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *
 	val := interpreter.intPool.get().SetUint64(contract.Gas)
 	stack.push(val)
 	if stack.flag {
-		stack.collector.OpInOut.OpResult = val.String()
+		stack.collector.SetResult(val)
 	}
 	return nil, nil
 }
@@ -1030,14 +1041,14 @@ func opCreate(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	if stack.flag {
 		stack.collector.OpName = "CREATESTART"
 		stack.collector.CallLayer = tingrong.CALL_LAYER + 1
-		stack.collector.AccountValue.CallContract = ""
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, value.String(), offset.String(), size.String())
-		stack.collector.OpInOut.InputData = input
-		stack.collector.AccountValue.Value = value.String()
-		stack.collector.Gas.AllocatedGas = fmt.Sprintf("%v", stack.collector.Gas.RealGasUsed)
-		stack.collector.AccountValue.FromAddr = contract.Address().String()
-		data := stack.collector.SendInsInfo()
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(stack.collector.OpName, data)
+		stack.collector.CallContract = common.Address{}
+		stack.collector.AddArgs(value, offset, size)
+		stack.collector.InputData = input
+		stack.collector.Value = collector.BigToWord(value)
+		forwardGas(stack.collector, gas, 0)
+		stack.collector.From = contract.Address()
+		data := stack.collector.SendInsEvent()
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvCreateStart, data)
 	}
 	//add new 
 
@@ -1045,9 +1056,7 @@ func opCreate(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 
 	//add new 
 	if stack.flag{
-		temp_gas := stack.collector.Gas.RealGasUsed
-		stack.collector = collector.NewCollector()
-		stack.collector.Gas.RealGasUsed = temp_gas - returnGas
+		stack.collector = endGasEvent(stack.collector, returnGas)
 	}
 	//add new 
 
@@ -1068,36 +1077,31 @@ func opCreate(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 	interpreter.intPool.put(value, offset, size)
 	
 	//add new 
-	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.GetOpcodeRegister("TRANS_CREATE") {
-		invokeinfo := collector.NewTransCollector()
-		invokeinfo.Op = "TRANS_CREATE"
+	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTransCreate) {
+		invokeinfo := &collector.MessageEvent{Type: collector.MessageCreate}
 		invokeinfo.Pc = *pc
-		invokeinfo.From = contract.Address().String()
-		invokeinfo.To = addr.String()
-		invokeinfo.Value = value.String()
-		invokeinfo.CallType = "CREATE"
-		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
-		temp_arr := strings.Split(temp_str,"#")
-		invokeinfo.CallLayer,_ = strconv.Atoi(temp_arr[1])
-		
-		createcollector := collector.NewCreateCollector()
-		createcollector.ContractAddr = addr.String()
-		createcollector.ContractDeployCode = input
-		createcollector.ContractRuntimeCode = res
-		invokeinfo.CreateInfo = *createcollector
-		
-		invokeinfo.IsSuccess = (suberr != nil)
-		interpreter.evm.ChainConfig().TransferDataPlg.SendDataToPlugin(invokeinfo.Op, invokeinfo.SendTransInfo(invokeinfo.Op))
+		invokeinfo.From = contract.Address()
+		invokeinfo.To = addr
+		invokeinfo.Value = collector.BigToWord(value)
+		invokeinfo.CallLayer = currentCallLayer()
+		invokeinfo.Input = input
+		invokeinfo.Code = res
+		invokeinfo.Success = (suberr == nil)
+		invokeinfo.Failure = Failure(suberr)
+		invokeinfo.RevertReason = revertReason(suberr, res)
+		interpreter.evm.ChainConfig().TransferDataPlg.SendEvent(pluginManage.EvTransCreate, invokeinfo.SendMessageEvent())
 	}
 	if stack.flag {
 		stack.collector.OpName = "CREATEEND"
 		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
 		temp_arr := strings.Split(temp_str,"#")
 		stack.collector.CallLayer,_ = strconv.Atoi(temp_arr[1])
-		stack.collector.AccountValue.CallContract = addr.String()
-		stack.collector.OpInOut.OpResult = p.String()
-		stack.collector.OpInOut.RetArgs = res
-		stack.collector.AccountValue.ToAddr = addr.String()
+		stack.collector.CallContract = addr
+		stack.collector.SetResult(p)
+		stack.collector.RetArgs = res
+		stack.collector.To = addr
+		stack.collector.Failure = Failure(suberr)
+		stack.collector.RevertReason = revertReason(suberr, res)
 	}
 	
 	if interpreter.evm.isTxStart{
@@ -1128,14 +1132,14 @@ func opCreate2(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memo
 	if stack.flag {
 		stack.collector.OpName = "CREATE2START"
 		stack.collector.CallLayer = tingrong.CALL_LAYER + 1
-		stack.collector.AccountValue.CallContract = ""
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, endowment.String(), offset.String(), size.String(), salt.String())
-		stack.collector.OpInOut.InputData = input
-		stack.collector.Gas.AllocatedGas = fmt.Sprintf("%v", stack.collector.Gas.RealGasUsed)
-		stack.collector.AccountValue.Value = endowment.String()
-		stack.collector.AccountValue.FromAddr = contract.Address().String()
-		data := stack.collector.SendInsInfo()
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(stack.collector.OpName, data)
+		stack.collector.CallContract = common.Address{}
+		stack.collector.AddArgs(endowment, offset, size, salt)
+		stack.collector.InputData = input
+		forwardGas(stack.collector, gas, 0)
+		stack.collector.Value = collector.BigToWord(endowment)
+		stack.collector.From = contract.Address()
+		data := stack.collector.SendInsEvent()
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvCreate2Start, data)
 	}
 	//add new 
 
@@ -1143,9 +1147,7 @@ func opCreate2(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memo
 
 	//add new 
 	if stack.flag{
-		temp_gas := stack.collector.Gas.RealGasUsed
-		stack.collector = collector.NewCollector()
-		stack.collector.Gas.RealGasUsed = temp_gas - returnGas
+		stack.collector = endGasEvent(stack.collector, returnGas)
 	}
 	//add new 
 
@@ -1162,34 +1164,32 @@ func opCreate2(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memo
 
 	//add new 
 	//add new 
-	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.GetOpcodeRegister("TRANS_CREATE2") {
-		invokeinfo := collector.NewTransCollector()
-		invokeinfo.Op = "TRANS_CREATE2"
+	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTransCreate2) {
+		invokeinfo := &collector.MessageEvent{Type: collector.MessageCreate2}
 		invokeinfo.Pc = *pc
-		invokeinfo.From = contract.Address().String()
-		invokeinfo.To = addr.String()
-		invokeinfo.Value = endowment.String()
-		invokeinfo.CallType = "CREATE"
-		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
-		temp_arr := strings.Split(temp_str,"#")
-		invokeinfo.CallLayer,_ = strconv.Atoi(temp_arr[1])		
-		createcollector := collector.NewCreateCollector()
-		createcollector.ContractAddr = addr.String()
-		createcollector.ContractDeployCode = input
-		createcollector.ContractRuntimeCode = res
-		invokeinfo.CreateInfo = *createcollector	
-		invokeinfo.IsSuccess = (suberr != nil)
-		interpreter.evm.ChainConfig().TransferDataPlg.SendDataToPlugin(invokeinfo.Op, invokeinfo.SendTransInfo(invokeinfo.Op))
+		invokeinfo.From = contract.Address()
+		invokeinfo.To = addr
+		invokeinfo.Value = collector.BigToWord(endowment)
+		invokeinfo.CallLayer = currentCallLayer()
+		invokeinfo.Input = input
+		invokeinfo.Code = res
+		invokeinfo.Success = (suberr == nil)
+		invokeinfo.Failure = Failure(suberr)
+		invokeinfo.RevertReason = revertReason(suberr, res)
+		invokeinfo.Salt = collector.BigToWord(salt)
+		interpreter.evm.ChainConfig().TransferDataPlg.SendEvent(pluginManage.EvTransCreate2, invokeinfo.SendMessageEvent())
 	}
 	if stack.flag {
 		stack.collector.OpName = "CREATE2END"
 		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
 		temp_arr := strings.Split(temp_str,"#")
 		stack.collector.CallLayer,_ = strconv.Atoi(temp_arr[1])
-		stack.collector.AccountValue.CallContract = addr.String()
-		stack.collector.OpInOut.OpResult = p.String()
-		stack.collector.OpInOut.RetArgs = res
-		stack.collector.AccountValue.ToAddr = addr.String()
+		stack.collector.CallContract = addr
+		stack.collector.SetResult(p)
+		stack.collector.RetArgs = res
+		stack.collector.To = addr
+		stack.collector.Failure = Failure(suberr)
+		stack.collector.RevertReason = revertReason(suberr, res)
 	}
 	
 	if interpreter.evm.isTxStart{
@@ -1230,16 +1230,16 @@ func opCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 	if stack.flag {
 		stack.collector.OpName = "CALLSTART"
 		stack.collector.CallLayer = tingrong.CALL_LAYER
-		stack.collector.AccountValue.CallContract = toAddr.String()
-		stack.collector.AccountValue.FromAddr = contract.Address().String()
-		stack.collector.AccountValue.ToAddr = toAddr.String()
-		stack.collector.AccountValue.Value = value.String()
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, pop.String(), inOffset.String(), inSize.String(), retOffset.String(), retSize.String())
-		stack.collector.OpInOut.InputData = args
-		stack.collector.Gas.AllocatedGas = fmt.Sprintf("%v", stack.collector.Gas.RealGasUsed)
-		stack.collector.OpInOut.ByteCode = interpreter.evm.StateDB.GetCode(toAddr)
-		data := stack.collector.SendInsInfo()
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(stack.collector.OpName, data)
+		stack.collector.CallContract = toAddr
+		stack.collector.From = contract.Address()
+		stack.collector.To = toAddr
+		stack.collector.Value = collector.BigToWord(value)
+		stack.collector.AddArgs(pop, inOffset, inSize, retOffset, retSize)
+		stack.collector.InputData = args
+		forwardGas(stack.collector, gas, interpreter.evm.callGasTemp)
+		stack.collector.ByteCode = interpreter.evm.StateDB.GetCode(toAddr)
+		data := stack.collector.SendInsEvent()
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvCallStart, data)
 	}
 	
 	//add new 
@@ -1248,9 +1248,7 @@ func opCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 
 	//add new 
 	if stack.flag{
-		temp_gas := stack.collector.Gas.RealGasUsed
-		stack.collector = collector.NewCollector()
-		stack.collector.Gas.RealGasUsed = temp_gas - returnGas
+		stack.collector = endGasEvent(stack.collector, returnGas)
 	}
 	//add new 
 
@@ -1259,15 +1257,17 @@ func opCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 		p = interpreter.intPool.getZero()
 		//add new 
 		if stack.flag {
-			stack.collector.CheckErr.InternalErr = err.Error()
-			stack.collector.CheckErr.IsInternalSucceeded = false
+			stack.collector.InternalErr = err.Error()
+			stack.collector.Failure = Failure(err)
+			stack.collector.RevertReason = revertReason(err, ret)
+			stack.collector.IsInternalSucceeded = false
 		}
 		//add new 
 	} else {
 		p = interpreter.intPool.get().SetUint64(1)
 		//add new 
 		if stack.flag {
-			stack.collector.CheckErr.IsInternalSucceeded = true
+			stack.collector.IsInternalSucceeded = true
 		}
 		//add new 
 	}
@@ -1275,13 +1275,13 @@ func opCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 	if err == nil || err == errExecutionReverted {
 		//add new 
 		if stack.flag {
-			stack.collector.OpInOut.MemoryData = memory.Get(retOffset.Int64(), retSize.Int64())
+			stack.collector.MemoryData = memory.Get(retOffset.Int64(), retSize.Int64())
 		}
 		//add new 
 		memory.Set(retOffset.Uint64(), retSize.Uint64(), ret)
 		//add new 
 		if stack.flag {
-			stack.collector.OpInOut.RetArgs = ret
+			stack.collector.RetArgs = ret
 		}
 		//add new 
 	}
@@ -1291,28 +1291,23 @@ func opCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 	
 	//add new 
 
-	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.GetOpcodeRegister("TRANS_CALL") {
-		invokeinfo := collector.NewTransCollector()
-		invokeinfo.Op = "TRANS_CALL"
+	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTransCall) {
+		invokeinfo := &collector.MessageEvent{Type: collector.MessageCall}
 		invokeinfo.Pc = *pc
-		invokeinfo.From = contract.Address().String()
-		invokeinfo.To = toAddr.String()
-		invokeinfo.Value = value.String()
-		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
-		temp_arr := strings.Split(temp_str,"#")
-		invokeinfo.CallLayer,_ = strconv.Atoi(temp_arr[1])
-
-		invokeinfo.CallType = "CALL"
-		callcollector := collector.NewCallCollector()
-		callcollector.ContractCode = interpreter.evm.StateDB.GetCode(toAddr)	
-		callcollector.InputData = args		
-		invokeinfo.CallInfo = *callcollector
+		invokeinfo.From = contract.Address()
+		invokeinfo.To = toAddr
+		invokeinfo.Value = collector.BigToWord(value)
+		invokeinfo.CallLayer = currentCallLayer()
+		invokeinfo.Input = args
+		invokeinfo.Code = interpreter.evm.StateDB.GetCode(toAddr)
 		if err==nil || err == ErrInsufficientBalance || err == ErrDepth {
-			invokeinfo.IsSuccess = true 
+			invokeinfo.Success = true 
 		}else{
-			invokeinfo.IsSuccess = false 
+			invokeinfo.Success = false 
 		}
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(invokeinfo.Op, invokeinfo.SendTransInfo(invokeinfo.Op))
+		invokeinfo.Failure = Failure(err)
+		invokeinfo.RevertReason = revertReason(err, ret)
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvTransCall, invokeinfo.SendMessageEvent())
 	}
 
 	if stack.flag {
@@ -1320,10 +1315,10 @@ func opCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
 		temp_arr := strings.Split(temp_str,"#")
 		stack.collector.CallLayer,_ = strconv.Atoi(temp_arr[1])
-		stack.collector.AccountValue.CallContract = toAddr.String()
-		stack.collector.OpInOut.OpResult = p.String()
-		//stack.collector.CheckErr.IsInternalSucceeded = tingrong.CALLVALID_MAP[tingrong.CALL_LAYER] && stack.collector.CheckErr.IsInternalSucceeded
-		stack.collector.CheckErr.IsCallValid = tingrong.CALLVALID_MAP[stack.collector.CallLayer]
+		stack.collector.CallContract = toAddr
+		stack.collector.SetResult(p)
+		//stack.collector.IsInternalSucceeded = tingrong.CALLVALID_MAP[tingrong.CALL_LAYER] && stack.collector.IsInternalSucceeded
+		stack.collector.IsCallValid = tingrong.CALLVALID_MAP[stack.collector.CallLayer]
 	}
 	
 	if interpreter.evm.isTxStart {
@@ -1360,16 +1355,16 @@ func opCallCode(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	if stack.flag {
 		stack.collector.OpName = "CALLCODESTART"
 		stack.collector.CallLayer = tingrong.CALL_LAYER
-		stack.collector.AccountValue.CallContract = toAddr.String()
-		stack.collector.AccountValue.FromAddr = contract.Address().String()
-		stack.collector.AccountValue.ToAddr = toAddr.String()
-		stack.collector.AccountValue.Value = value.String()
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, pop.String(), inOffset.String(), inSize.String(), retOffset.String(), retSize.String())
-		stack.collector.OpInOut.InputData = args
-		stack.collector.Gas.AllocatedGas = fmt.Sprintf("%v", stack.collector.Gas.RealGasUsed)
-		stack.collector.OpInOut.ByteCode = interpreter.evm.StateDB.GetCode(toAddr)
-		data := stack.collector.SendInsInfo()
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(stack.collector.OpName, data)
+		stack.collector.CallContract = toAddr
+		stack.collector.From = contract.Address()
+		stack.collector.To = toAddr
+		stack.collector.Value = collector.BigToWord(value)
+		stack.collector.AddArgs(pop, inOffset, inSize, retOffset, retSize)
+		stack.collector.InputData = args
+		forwardGas(stack.collector, gas, interpreter.evm.callGasTemp)
+		stack.collector.ByteCode = interpreter.evm.StateDB.GetCode(toAddr)
+		data := stack.collector.SendInsEvent()
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvCallCodeStart, data)
 	}
 	//add new 
 
@@ -1377,9 +1372,7 @@ func opCallCode(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 
 	//add new 
 	if stack.flag{
-		temp_gas := stack.collector.Gas.RealGasUsed
-		stack.collector = collector.NewCollector()
-		stack.collector.Gas.RealGasUsed = temp_gas - returnGas
+		stack.collector = endGasEvent(stack.collector, returnGas)
 	}
 	//add new 
 
@@ -1388,15 +1381,17 @@ func opCallCode(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 		p = interpreter.intPool.getZero()
 		//add new 
 		if stack.flag {
-			stack.collector.CheckErr.InternalErr = err.Error()
-			stack.collector.CheckErr.IsInternalSucceeded = false
+			stack.collector.InternalErr = err.Error()
+			stack.collector.Failure = Failure(err)
+			stack.collector.RevertReason = revertReason(err, ret)
+			stack.collector.IsInternalSucceeded = false
 		}
 		//add new 
 	} else {
 		p = interpreter.intPool.get().SetUint64(1)
 		//add new 
 		if stack.flag {
-			stack.collector.CheckErr.IsInternalSucceeded = true
+			stack.collector.IsInternalSucceeded = true
 		}
 		//add new 
 	}
@@ -1404,13 +1399,13 @@ func opCallCode(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	if err == nil || err == errExecutionReverted {
 		//add new 
 		if stack.flag {
-			stack.collector.OpInOut.MemoryData = memory.Get(retOffset.Int64(), retSize.Int64())
+			stack.collector.MemoryData = memory.Get(retOffset.Int64(), retSize.Int64())
 		}
 		//add new 
 		memory.Set(retOffset.Uint64(), retSize.Uint64(), ret)
 		//add new 
 		if stack.flag {
-			stack.collector.OpInOut.RetArgs = ret
+			stack.collector.RetArgs = ret
 		}
 		//add new 
 	}
@@ -1419,28 +1414,23 @@ func opCallCode(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	interpreter.intPool.put(addr, value, inOffset, inSize, retOffset, retSize)
 	//add new 
 
-	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.GetOpcodeRegister("TRANS_CALLCODE") {
-		invokeinfo := collector.NewTransCollector()
-		invokeinfo.Op = "TRANS_CALLCODE"
+	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTransCallCode) {
+		invokeinfo := &collector.MessageEvent{Type: collector.MessageCallCode}
 		invokeinfo.Pc = *pc
-		invokeinfo.From = contract.Address().String()
-		invokeinfo.To = toAddr.String()
-		invokeinfo.Value = value.String()
-		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
-		temp_arr := strings.Split(temp_str,"#")
-		invokeinfo.CallLayer,_ = strconv.Atoi(temp_arr[1])
-
-		invokeinfo.CallType = "CALL"
-		callcollector := collector.NewCallCollector()
-		callcollector.ContractCode = interpreter.evm.StateDB.GetCode(toAddr)	
-		callcollector.InputData = args		
-		invokeinfo.CallInfo = *callcollector
+		invokeinfo.From = contract.Address()
+		invokeinfo.To = toAddr
+		invokeinfo.Value = collector.BigToWord(value)
+		invokeinfo.CallLayer = currentCallLayer()
+		invokeinfo.Input = args
+		invokeinfo.Code = interpreter.evm.StateDB.GetCode(toAddr)
 		if err==nil || err == ErrInsufficientBalance || err == ErrDepth {
-			invokeinfo.IsSuccess = true 
+			invokeinfo.Success = true 
 		}else{
-			invokeinfo.IsSuccess = false 
+			invokeinfo.Success = false 
 		}
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(invokeinfo.Op, invokeinfo.SendTransInfo(invokeinfo.Op))
+		invokeinfo.Failure = Failure(err)
+		invokeinfo.RevertReason = revertReason(err, ret)
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvTransCallCode, invokeinfo.SendMessageEvent())
 	}
 
 	if stack.flag {
@@ -1448,8 +1438,8 @@ func opCallCode(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
 		temp_arr := strings.Split(temp_str,"#")
 		stack.collector.CallLayer,_ = strconv.Atoi(temp_arr[1])
-		stack.collector.AccountValue.CallContract = toAddr.String()
-		stack.collector.OpInOut.OpResult = p.String()	
+		stack.collector.CallContract = toAddr
+		stack.collector.SetResult(p)	
 	}
 
 	if interpreter.evm.isTxStart{
@@ -1480,15 +1470,17 @@ func opDelegateCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 	if stack.flag {
 		stack.collector.OpName = "DELEGATECALLSTART"
 		stack.collector.CallLayer = tingrong.CALL_LAYER
-		stack.collector.AccountValue.CallContract = toAddr.String()
-		stack.collector.AccountValue.FromAddr = contract.Address().String()
-		stack.collector.AccountValue.ToAddr = toAddr.String()
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, pop.String(), toAddr.String(), inOffset.String(), inSize.String(), retOffset.String(), retSize.String())
-		stack.collector.OpInOut.InputData = args
-		stack.collector.Gas.AllocatedGas = fmt.Sprintf("%v", stack.collector.Gas.RealGasUsed)
-		stack.collector.OpInOut.ByteCode = interpreter.evm.StateDB.GetCode(toAddr)
-		data := stack.collector.SendInsInfo()
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(stack.collector.OpName, data)
+		stack.collector.CallContract = toAddr
+		stack.collector.From = contract.Address()
+		stack.collector.To = toAddr
+		stack.collector.AddArgs(pop)
+		stack.collector.AddWords(collector.AddressToWord(toAddr))
+		stack.collector.AddArgs(inOffset, inSize, retOffset, retSize)
+		stack.collector.InputData = args
+		forwardGas(stack.collector, gas, interpreter.evm.callGasTemp)
+		stack.collector.ByteCode = interpreter.evm.StateDB.GetCode(toAddr)
+		data := stack.collector.SendInsEvent()
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvDelegateCallStart, data)
 	}
 	//add new 
 
@@ -1496,9 +1488,7 @@ func opDelegateCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 	
 	//add new 
 	if stack.flag{
-		temp_gas := stack.collector.Gas.RealGasUsed
-		stack.collector = collector.NewCollector()
-		stack.collector.Gas.RealGasUsed = temp_gas - returnGas
+		stack.collector = endGasEvent(stack.collector, returnGas)
 	}
 	//add new 
 	
@@ -1507,15 +1497,17 @@ func opDelegateCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 		p = interpreter.intPool.getZero()
 		//add new 
 		if stack.flag {
-			stack.collector.CheckErr.InternalErr = err.Error()
-			stack.collector.CheckErr.IsInternalSucceeded = false
+			stack.collector.InternalErr = err.Error()
+			stack.collector.Failure = Failure(err)
+			stack.collector.RevertReason = revertReason(err, ret)
+			stack.collector.IsInternalSucceeded = false
 		}
 		//add new 
 	} else {
 		p = interpreter.intPool.get().SetUint64(1)
 		//add new 
 		if stack.flag {
-			stack.collector.CheckErr.IsInternalSucceeded = true
+			stack.collector.IsInternalSucceeded = true
 		}
 		//add new 
 	}
@@ -1523,13 +1515,13 @@ func opDelegateCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 	if err == nil || err == errExecutionReverted {
 		//add new 
 		if stack.flag {
-			stack.collector.OpInOut.MemoryData = memory.Get(retOffset.Int64(), retSize.Int64())
+			stack.collector.MemoryData = memory.Get(retOffset.Int64(), retSize.Int64())
 		}
 		//add new 
 		memory.Set(retOffset.Uint64(), retSize.Uint64(), ret)
 		//add new 
 		if stack.flag {
-			stack.collector.OpInOut.RetArgs = ret
+			stack.collector.RetArgs = ret
 		}
 		//add new 
 	}
@@ -1537,36 +1529,31 @@ func opDelegateCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 
 	interpreter.intPool.put(addr, inOffset, inSize, retOffset, retSize)
 	//add new 
-	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.GetOpcodeRegister("TRANS_DELEGATECALL") {
-		invokeinfo := collector.NewTransCollector()
-		invokeinfo.Op = "TRANS_DELEGATECALL"
+	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTransDelegateCall) {
+		invokeinfo := &collector.MessageEvent{Type: collector.MessageDelegateCall}
 		invokeinfo.Pc = *pc
-		invokeinfo.From = contract.Address().String()
-		invokeinfo.To = toAddr.String()
-		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
-		temp_arr := strings.Split(temp_str,"#")
-		invokeinfo.CallLayer,_ = strconv.Atoi(temp_arr[1])
-
-		invokeinfo.CallType = "CALL"
-		callcollector := collector.NewCallCollector()
-		callcollector.ContractCode = interpreter.evm.StateDB.GetCode(toAddr)	
-		callcollector.InputData = args		
-		invokeinfo.CallInfo = *callcollector
+		invokeinfo.From = contract.Address()
+		invokeinfo.To = toAddr
+		invokeinfo.CallLayer = currentCallLayer()
+		invokeinfo.Input = args
+		invokeinfo.Code = interpreter.evm.StateDB.GetCode(toAddr)
 		if err==nil || err == ErrDepth {
-			invokeinfo.IsSuccess = true 
+			invokeinfo.Success = true 
 		}else{
-			invokeinfo.IsSuccess = false 
+			invokeinfo.Success = false 
 		}
-		invokeinfo.IsSuccess = (err==nil)
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(invokeinfo.Op, invokeinfo.SendTransInfo(invokeinfo.Op))
+		invokeinfo.Success = (err==nil)
+		invokeinfo.Failure = Failure(err)
+		invokeinfo.RevertReason = revertReason(err, ret)
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvTransDelegateCall, invokeinfo.SendMessageEvent())
 	}
 	if stack.flag {
 		stack.collector.OpName = "DELEGATECALLEND"
 		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
 		temp_arr := strings.Split(temp_str,"#")
 		stack.collector.CallLayer,_ = strconv.Atoi(temp_arr[1])
-		stack.collector.AccountValue.CallContract = toAddr.String()
-		stack.collector.OpInOut.OpResult = p.String()	
+		stack.collector.CallContract = toAddr
+		stack.collector.SetResult(p)	
 	}
 
 	if interpreter.evm.isTxStart {
@@ -1597,24 +1584,24 @@ func opStaticCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, m
 	if stack.flag {
 		stack.collector.OpName = "STATICCALLSTART"
 		stack.collector.CallLayer = tingrong.CALL_LAYER
-		stack.collector.AccountValue.CallContract = toAddr.String()
-		stack.collector.AccountValue.FromAddr = contract.Address().String()
-		stack.collector.AccountValue.ToAddr = toAddr.String()
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, pop.String(), toAddr.String(), inOffset.String(), inSize.String(), retOffset.String(), retSize.String())
-		stack.collector.OpInOut.InputData = args
-		stack.collector.Gas.AllocatedGas = fmt.Sprintf("%v", stack.collector.Gas.RealGasUsed)
-		stack.collector.OpInOut.ByteCode = interpreter.evm.StateDB.GetCode(toAddr)
-		data := stack.collector.SendInsInfo()
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(stack.collector.OpName, data)
+		stack.collector.CallContract = toAddr
+		stack.collector.From = contract.Address()
+		stack.collector.To = toAddr
+		stack.collector.AddArgs(pop)
+		stack.collector.AddWords(collector.AddressToWord(toAddr))
+		stack.collector.AddArgs(inOffset, inSize, retOffset, retSize)
+		stack.collector.InputData = args
+		forwardGas(stack.collector, gas, interpreter.evm.callGasTemp)
+		stack.collector.ByteCode = interpreter.evm.StateDB.GetCode(toAddr)
+		data := stack.collector.SendInsEvent()
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvStaticCallStart, data)
 	}
 	//add new 
 
 	ret, returnGas, err := interpreter.evm.StaticCall(contract, toAddr, args, gas)
 	//add new 
 	if stack.flag{
-		temp_gas := stack.collector.Gas.RealGasUsed
-		stack.collector = collector.NewCollector()
-		stack.collector.Gas.RealGasUsed = temp_gas - returnGas
+		stack.collector = endGasEvent(stack.collector, returnGas)
 	}
 	//add new 
 	var p *big.Int
@@ -1622,15 +1609,17 @@ func opStaticCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, m
 		p = interpreter.intPool.getZero()
 		//add new 
 		if stack.flag {
-			stack.collector.CheckErr.InternalErr = err.Error()
-			stack.collector.CheckErr.IsInternalSucceeded = false
+			stack.collector.InternalErr = err.Error()
+			stack.collector.Failure = Failure(err)
+			stack.collector.RevertReason = revertReason(err, ret)
+			stack.collector.IsInternalSucceeded = false
 		}
 		//add new 
 	} else {
 		p = interpreter.intPool.get().SetUint64(1)
 		//add new 
 		if stack.flag {
-			stack.collector.CheckErr.IsInternalSucceeded = true
+			stack.collector.IsInternalSucceeded = true
 		}
 		//add new 
 	}
@@ -1638,13 +1627,13 @@ func opStaticCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, m
 	if err == nil || err == errExecutionReverted {
 		//add new 
 		if stack.flag {
-			stack.collector.OpInOut.MemoryData = memory.Get(retOffset.Int64(), retSize.Int64())
+			stack.collector.MemoryData = memory.Get(retOffset.Int64(), retSize.Int64())
 		}
 		//add new 
 		memory.Set(retOffset.Uint64(), retSize.Uint64(), ret)
 		//add new 
 		if stack.flag {
-			stack.collector.OpInOut.RetArgs = ret
+			stack.collector.RetArgs = ret
 		}
 		//add new 
 	}
@@ -1652,35 +1641,30 @@ func opStaticCall(pc *uint64, interpreter *EVMInterpreter, contract *Contract, m
 
 	interpreter.intPool.put(addr, inOffset, inSize, retOffset, retSize)
 	//add new 
-	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.GetOpcodeRegister("TRANS_STATICCALL") {
-		invokeinfo := collector.NewTransCollector()
-		invokeinfo.Op = "TRANS_STATICCALL"
+	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTransStaticCall) {
+		invokeinfo := &collector.MessageEvent{Type: collector.MessageStaticCall}
 		invokeinfo.Pc = *pc
-		invokeinfo.From = contract.Address().String()
-		invokeinfo.To = toAddr.String()
-		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
-		temp_arr := strings.Split(temp_str,"#")
-		invokeinfo.CallLayer,_ = strconv.Atoi(temp_arr[1])
-
-		invokeinfo.CallType = "CALL"
-		callcollector := collector.NewCallCollector()
-		callcollector.ContractCode = interpreter.evm.StateDB.GetCode(toAddr)	
-		callcollector.InputData = args		
-		invokeinfo.CallInfo = *callcollector
+		invokeinfo.From = contract.Address()
+		invokeinfo.To = toAddr
+		invokeinfo.CallLayer = currentCallLayer()
+		invokeinfo.Input = args
+		invokeinfo.Code = interpreter.evm.StateDB.GetCode(toAddr)
 		if err==nil || err == ErrDepth {
-			invokeinfo.IsSuccess = true 
+			invokeinfo.Success = true 
 		}else{
-			invokeinfo.IsSuccess = false 
+			invokeinfo.Success = false 
 		}
-		interpreter.evm.chainConfig.TransferDataPlg.SendDataToPlugin(invokeinfo.Op, invokeinfo.SendTransInfo(invokeinfo.Op))
+		invokeinfo.Failure = Failure(err)
+		invokeinfo.RevertReason = revertReason(err, ret)
+		interpreter.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvTransStaticCall, invokeinfo.SendMessageEvent())
 	}
 	if stack.flag {
 		stack.collector.OpName = "STATICCALLEND"
 		temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
 		temp_arr := strings.Split(temp_str,"#")
 		stack.collector.CallLayer,_ = strconv.Atoi(temp_arr[1])
-		stack.collector.AccountValue.CallContract = toAddr.String()
-		stack.collector.OpInOut.OpResult = p.String()	
+		stack.collector.CallContract = toAddr
+		stack.collector.SetResult(p)	
 	}
 
 	if interpreter.evm.isTxStart{
@@ -1697,8 +1681,8 @@ func opReturn(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 
 	interpreter.intPool.put(offset, size)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, offset.String(), size.String())
-		stack.collector.OpInOut.RetArgs = ret
+		stack.collector.AddArgs(offset, size)
+		stack.collector.RetArgs = ret
 	}
 	return ret, nil
 }
@@ -1709,8 +1693,8 @@ func opRevert(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memor
 
 	interpreter.intPool.put(offset, size)
 	if stack.flag {
-		stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, offset.String(), size.String())
-		stack.collector.OpInOut.RetArgs = ret
+		stack.collector.AddArgs(offset, size)
+		stack.collector.RetArgs = ret
 	}
 	return ret, nil
 }
@@ -1725,18 +1709,21 @@ func opSuicide(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memo
 	interpreter.evm.StateDB.AddBalance(toAddr, balance)
 
 	interpreter.evm.StateDB.Suicide(contract.Address())
+	interpreter.evm.recordTransfer(collector.TransferSuicide, contract.Address(), toAddr, balance) //add new
 	if stack.flag {
-		stack.collector.AccountValue.Value = balance.String()
-		stack.collector.AccountValue.FromAddr = contract.Address().String()
-		stack.collector.AccountValue.ToAddr = toAddr.String()
+		stack.collector.Value = collector.BigToWord(balance)
+		stack.collector.From = contract.Address()
+		stack.collector.To = toAddr
 	}
-	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.GetOpcodeRegister("TRANS_SUICIDE"){
-		invokeinfo := collector.NewTransCollector()
-		invokeinfo.Op = "TRANS_SUICIDE"
-		invokeinfo.From = contract.Address().String()
-		invokeinfo.To = toAddr.String()
-		invokeinfo.Value = balance.String()
-		interpreter.evm.ChainConfig().TransferDataPlg.SendDataToPlugin(invokeinfo.Op, invokeinfo.SendTransInfo(invokeinfo.Op))
+	if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTransSuicide){
+		invokeinfo := &collector.MessageEvent{Type: collector.MessageSuicide}
+		invokeinfo.Pc = *pc
+		invokeinfo.From = contract.Address()
+		invokeinfo.To = toAddr
+		invokeinfo.Value = collector.BigToWord(balance)
+		invokeinfo.CallLayer = currentCallLayer()
+		invokeinfo.Success = true
+		interpreter.evm.ChainConfig().TransferDataPlg.SendEvent(pluginManage.EvTransSuicide, invokeinfo.SendMessageEvent())
 	}
 	return nil, nil
 }
@@ -1763,13 +1750,20 @@ func makeLog(size int) executionFunc {
 		})
 
 		interpreter.intPool.put(mStart, mSize)
+		//add new
+		if interpreter.evm.isTxStart && interpreter.evm.ChainConfig().TransferDataPlg.HasEvent(pluginManage.EvTokenTransfer) {
+			if tr := collector.DecodeTokenLog(contract.Address(), topics, d); tr != nil {
+				tr.TxHash = common.HexToHash(tingrong.TxHash)
+				tingrong.CALL_TREE.Token(tr)
+			}
+		}
 		if stack.flag {
-			stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, mStart.String(), mSize.String())
+			stack.collector.AddArgs(mStart, mSize)
 			for i := 0; i < size; i++ {
-				stack.collector.OpInOut.OpArgs = append(stack.collector.OpInOut.OpArgs, topics[i].String())
+				stack.collector.AddWords(collector.Word(topics[i]))
 			}
 
-			stack.collector.OpInOut.RetArgs = d
+			stack.collector.RetArgs = d
 		}
 		return nil, nil
 	}
@@ -1814,9 +1808,9 @@ func makePush(size uint64, pushByteSize int) executionFunc {
 
 		*pc += size
 		if stack.flag {
-			stack.collector.OpInOut.OpResult = p.String()
-			stack.collector.OpInOut.RetArgs = value
-			stack.collector.PcNext = fmt.Sprintf("%v", *pc)
+			stack.collector.SetResult(p)
+			stack.collector.RetArgs = value
+			stack.collector.PcNext = *pc
 		}
 		return nil, nil
 	}
@@ -1839,3 +1833,15 @@ func makeSwap(size int64) executionFunc {
 		return nil, nil
 	}
 }
+
+// currentCallLayer returns the call layer of the frame on top of the SODA
+// call stack, 0 if the stack is empty.
+func currentCallLayer() uint64 {
+	if len(tingrong.CALL_STACK) == 0 {
+		return 0
+	}
+	temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
+	temp_arr := strings.Split(temp_str, "#")
+	layer, _ := strconv.ParseUint(temp_arr[1], 10, 64)
+	return layer
+}
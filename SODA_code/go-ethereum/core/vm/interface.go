@@ -64,6 +64,9 @@ type StateDB interface {
 	AddPreimage(common.Hash, []byte)
 
 	ForEachStorage(common.Address, func(common.Hash, common.Hash) bool) error
+
+	//add new
+	SetJournalFrame(uint64)
 }
 
 // CallContext provides a basic interface for the EVM calling conventions. The EVM
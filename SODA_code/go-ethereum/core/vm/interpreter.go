@@ -29,6 +29,7 @@ import (
 
 	//add new 
 	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
 	"github.com/ethereum/go-ethereum/tingrong"
 )
 
@@ -167,6 +168,29 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	)
 	contract.Input = input
 
+	//add new 
+	// Resolve the detection hooks once per frame. Without subscribers the
+	// per-instruction cost is branching on hooks, all SODA bookkeeping of the
+	// loop sits behind it.
+	plg := in.evm.chainConfig.TransferDataPlg
+	hooks := in.evm.isTxStart && plg.Active()
+	var taint *frameTaint
+	if hooks && plg.Taints() {
+		taint = newFrameTaint()
+	}
+	branches := hooks && plg.HasEvent(pluginManage.EvBranch)
+	blocks := hooks && plg.HasEvent(pluginManage.EvBasicBlock)
+	memory := hooks && plg.HasEvent(pluginManage.EvMemory)
+	blockStart := true // whether pc starts a basic block without a JUMPDEST
+	var (
+		jumpPc     uint64
+		dest, cond collector.Word
+		refund     uint64 // refund counter before the instruction
+		written    memoryRegion
+		effect     taintEffect
+	)
+	//add new 
+
 	// Reclaim the stack as an int pool when the execution stops
 	defer func() { in.intPool.put(stack.data...) }()
 
@@ -200,27 +224,22 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		}
 
 		//add new 
-		if in.evm.isTxStart {
-			switch op.String(){
-			case "CALL":
-				stack.flag = in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("CALLSTART") || in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("CALLEND")
-			case "CALLCODE":
-				stack.flag = in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("CALLCODESTART") || in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("CALLCODEEND")
-			case "DELEGATECALL":
-				stack.flag = in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("DELEGATECALLSTART") || in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("DELEGATECALLEND")
-			case "STATICCALL":
-				stack.flag = in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("STATICCALLSTART") || in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("STATICCALLSTART")
-			case "CREATE":
-				stack.flag = in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("CREATESTART") || in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("CREATEEND")
-			case "CREATE2":
-				stack.flag = in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("CREATE2START") || in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister("CREATE2END")
-			default:
-				stack.flag = in.evm.chainConfig.TransferDataPlg.GetOpcodeRegister(op.String())
-			}
-			//stack.flag = true
+		if hooks {
+			stack.flag = plg.Collects(byte(op))
 			if stack.flag{
-				stack.collector = collector.NewCollector()
+				stack.collector = collector.NewInsEvent()
+				stack.collector.GasBefore = contract.Gas
 			}
+			if stack.flag || op == SSTORE || op == SELFDESTRUCT {
+				refund = in.evm.StateDB.GetRefund()
+				if stack.flag {
+					stack.collector.RefundBefore = refund
+				}
+			}
+			if blocks && (blockStart || op == JUMPDEST) {
+				in.sendBasicBlock(contract, pc)
+			}
+			blockStart = false
 		}
 		//add new 
 
@@ -281,18 +300,37 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		}
 
 		//add new 
-		if stack.flag {
-			stack.collector.OpName = contract.GetOp(pc).String()
-			stack.collector.Pc = pc
-			stack.collector.Gas.RealGasUsed += operation.constantGas
-			if operation.dynamicGas != nil {
-				stack.collector.Gas.RealGasUsed += cost
+		if hooks {
+			if stack.flag {
+				stack.collector.OpName = contract.GetOp(pc).String()
+				stack.collector.Pc = pc
+				stack.collector.StaticGas = operation.constantGas
+				if operation.dynamicGas != nil {
+					stack.collector.DynamicGas = cost
+				}
+				stack.collector.RealGasUsed = stack.collector.StaticGas + stack.collector.DynamicGas
+				temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
+				temp_arr := strings.Split(temp_str,"#")
+				stack.collector.CallContract = common.HexToAddress(temp_arr[0])
+				temp_int,_ := strconv.Atoi(temp_arr[1])
+				stack.collector.CallLayer = temp_int
+				setFrame(stack.collector, contract)
+			}
+			if taint != nil {
+				effect = in.taintBefore(taint, op, operation, contract, stack)
+			}
+			if branches && (op == JUMP || op == JUMPI) {
+				jumpPc, dest, cond = pc, collector.BigToWord(stack.Back(0)), collector.Word{}
+				if op == JUMPI {
+					cond = collector.BigToWord(stack.Back(1))
+				}
+			}
+			if memory {
+				var read memoryRegion
+				if read, written = memoryAccesses(op, stack); read.length > 0 {
+					in.sendMemory(contract, mem, op, pc, collector.MemoryRead, read)
+				}
 			}
-			temp_str := tingrong.CALL_STACK[len(tingrong.CALL_STACK)-1]
-			temp_arr := strings.Split(temp_str,"#")
-			stack.collector.AccountValue.CallContract = temp_arr[0]
-			temp_int,_ := strconv.Atoi(temp_arr[1])
-			stack.collector.CallLayer = temp_int
 		}
 		//add new 
 
@@ -300,14 +338,39 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		res, err = operation.execute(&pc, in, contract, mem, stack)
 
 		//add new 
-		if stack.flag {
-			if !operation.jumps {
-				stack.collector.PcNext = fmt.Sprintf("%v", pc+1)
-			} else {
-				stack.collector.PcNext = fmt.Sprintf("%v", pc)
+		if hooks {
+			if taint != nil && err == nil {
+				in.taintAfter(taint, &effect, contract)
+			}
+			// SSTORE and SELFDESTRUCT change the refund counter in their gas
+			// functions, calls leave it to their callee's frame.
+			if (op == SSTORE || op == SELFDESTRUCT) && err == nil {
+				countRefund(refund, in.evm.StateDB.GetRefund())
+			}
+			if memory && err == nil {
+				// A call writes no more than the callee returned.
+				if operation.returns && written.length > uint64(len(res)) {
+					written.length = uint64(len(res))
+				}
+				if written.length > 0 {
+					in.sendMemory(contract, mem, op, pc, collector.MemoryWrite, written)
+				}
+			}
+			if op == JUMP || op == JUMPI {
+				blockStart = true
+				if branches && err == nil {
+					in.sendBranch(contract, op, jumpPc, pc, dest, cond)
+				}
+			}
+			if stack.flag {
+				if !operation.jumps {
+					stack.collector.PcNext = pc + 1
+				} else {
+					stack.collector.PcNext = pc
+				}
+				stack.collector.RefundAfter = in.evm.StateDB.GetRefund()
+				plg.SendOpcode(byte(op), stack.collector.SendInsEvent())
 			}
-			data := stack.collector.SendInsInfo()
-			in.evm.chainConfig.TransferDataPlg.SendDataToPlugin(stack.collector.OpName, data)
 		}
 		//add new 
 
@@ -341,3 +404,18 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 func (in *EVMInterpreter) CanRun(code []byte) bool {
 	return true
 }
+
+// setFrame records which frame runs an instruction. Under DELEGATECALL and
+// CALLCODE the contract runs the code of its CodeAddr on its own storage,
+// and a DELEGATECALL keeps the sender and value of its caller.
+func setFrame(e *collector.InsEvent, contract *Contract) {
+	e.StorageAddress = contract.Address()
+	e.CodeAddress = codeAddress(contract)
+	e.Sender = contract.Caller()
+	if value := contract.Value(); value != nil {
+		e.CallValue = collector.BigToWord(value)
+	}
+	if frame := tingrong.CALL_TREE.Current(); frame != nil {
+		e.FrameType = frame.Type
+	}
+}
@@ -0,0 +1,70 @@
+package vm
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// hookIdents are the locals of Run holding SODA state.
+var hookIdents = map[string]bool{
+	"plg": true, "hooks": true, "taint": true, "branches": true, "blocks": true, "memory": true,
+	"blockStart": true, "jumpPc": true, "dest": true, "cond": true, "refund": true, "written": true, "effect": true,
+}
+
+// usesHooks reports whether n touches the SODA state of Run or of the stack.
+func usesHooks(n ast.Node) (uses bool) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			uses = uses || hookIdents[n.Name]
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok && x.Name == "stack" && (n.Sel.Name == "flag" || n.Sel.Name == "collector") {
+				uses = true
+			}
+		}
+		return !uses
+	})
+	return uses
+}
+
+// Without plugins the interpreter loop has to stay the one of upstream plus
+// tests of the hooks flag: every statement of the loop doing SODA bookkeeping
+// must be an if on hooks alone.
+func TestInterpreterHooksGuarded(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "interpreter.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loop *ast.ForStmt
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "Run" && fn.Recv != nil {
+			for _, stmt := range fn.Body.List {
+				if l, ok := stmt.(*ast.ForStmt); ok {
+					loop = l
+				}
+			}
+		}
+	}
+	if loop == nil {
+		t.Fatal("interpreter loop not found")
+	}
+	guards := 0
+	for _, stmt := range loop.Body.List {
+		if !usesHooks(stmt) {
+			continue
+		}
+		if s, ok := stmt.(*ast.IfStmt); ok && s.Init == nil && s.Else == nil {
+			if cond, ok := s.Cond.(*ast.Ident); ok && cond.Name == "hooks" {
+				guards++
+				continue
+			}
+		}
+		t.Errorf("%v: SODA bookkeeping runs without plugins", fset.Position(stmt.Pos()))
+	}
+	if guards == 0 {
+		t.Error("no hooks in the interpreter loop")
+	}
+}
@@ -0,0 +1,65 @@
+package vm
+
+//add new file
+
+import (
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// memoryRegion is a region of memory accessed by an instruction, empty if
+// the instruction doesn't access memory that way.
+type memoryRegion struct {
+	offset, length uint64
+}
+
+// memoryAccesses returns the regions op reads and writes. It reads the
+// offsets and sizes off the validated stack before op pops them, and the
+// memory has already been expanded to hold the regions.
+func memoryAccesses(op OpCode, stack *Stack) (read, write memoryRegion) {
+	region := func(offset, size int) memoryRegion {
+		if stack.Back(size).Sign() == 0 {
+			return memoryRegion{}
+		}
+		return memoryRegion{stack.Back(offset).Uint64(), stack.Back(size).Uint64()}
+	}
+	switch op {
+	case MLOAD:
+		read = memoryRegion{stack.Back(0).Uint64(), 32}
+	case MSTORE:
+		write = memoryRegion{stack.Back(0).Uint64(), 32}
+	case MSTORE8:
+		write = memoryRegion{stack.Back(0).Uint64(), 1}
+	case SHA3, RETURN, REVERT, LOG0, LOG1, LOG2, LOG3, LOG4:
+		read = region(0, 1)
+	case CALLDATACOPY, CODECOPY, RETURNDATACOPY:
+		write = region(0, 2)
+	case EXTCODECOPY:
+		write = region(1, 3)
+	case CREATE, CREATE2:
+		read = region(1, 2)
+	case CALL, CALLCODE:
+		read, write = region(3, 4), region(5, 6)
+	case DELEGATECALL, STATICCALL:
+		read, write = region(2, 3), region(4, 5)
+	}
+	return read, write
+}
+
+// sendMemory reports the access of op at pc to the region.
+func (in *EVMInterpreter) sendMemory(contract *Contract, mem *Memory, op OpCode, pc uint64, direction string, region memoryRegion) {
+	access := &collector.MemoryAccess{
+		Op:          op.String(),
+		Direction:   direction,
+		TxHash:      common.HexToHash(tingrong.TxHash),
+		CallLayer:   currentCallLayer(),
+		CodeAddress: codeAddress(contract),
+		Pc:          pc,
+		Offset:      region.offset,
+		Length:      region.length,
+		Data:        mem.Get(int64(region.offset), int64(region.length)),
+	}
+	in.evm.chainConfig.TransferDataPlg.SendEvent(pluginManage.EvMemory, access.SendMemoryEvent())
+}
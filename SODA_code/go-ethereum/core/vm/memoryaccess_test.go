@@ -0,0 +1,40 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMemoryAccesses(t *testing.T) {
+	tests := []struct {
+		op          OpCode
+		stack       []int64 // top first
+		read, write memoryRegion
+	}{
+		{MLOAD, []int64{64}, memoryRegion{64, 32}, memoryRegion{}},
+		{MSTORE, []int64{32, 1}, memoryRegion{}, memoryRegion{32, 32}},
+		{MSTORE8, []int64{5, 1}, memoryRegion{}, memoryRegion{5, 1}},
+		{SHA3, []int64{4, 64}, memoryRegion{4, 64}, memoryRegion{}},
+		{REVERT, []int64{0, 100}, memoryRegion{0, 100}, memoryRegion{}},
+		{LOG3, []int64{32, 32, 1, 2, 3}, memoryRegion{32, 32}, memoryRegion{}},
+		{CALLDATACOPY, []int64{8, 0, 16}, memoryRegion{}, memoryRegion{8, 16}},
+		{EXTCODECOPY, []int64{1, 8, 0, 16}, memoryRegion{}, memoryRegion{8, 16}},
+		{CREATE2, []int64{0, 10, 20, 7}, memoryRegion{10, 20}, memoryRegion{}},
+		{CALL, []int64{100, 1, 0, 0, 32, 32, 32}, memoryRegion{0, 32}, memoryRegion{32, 32}},
+		{STATICCALL, []int64{100, 1, 0, 4, 64, 32}, memoryRegion{0, 4}, memoryRegion{64, 32}},
+		// An empty region doesn't access memory, whatever its offset.
+		{SHA3, []int64{1000, 0}, memoryRegion{}, memoryRegion{}},
+		{CALL, []int64{100, 1, 0, 50, 0, 60, 0}, memoryRegion{}, memoryRegion{}},
+		{ADD, []int64{1, 2}, memoryRegion{}, memoryRegion{}},
+	}
+	for i, tt := range tests {
+		stack := newstack()
+		for j := len(tt.stack) - 1; j >= 0; j-- {
+			stack.push(big.NewInt(tt.stack[j]))
+		}
+		read, write := memoryAccesses(tt.op, stack)
+		if read != tt.read || write != tt.write {
+			t.Errorf("test %d (%v): have read %+v write %+v, want read %+v write %+v", i, tt.op, read, write, tt.read, tt.write)
+		}
+	}
+}
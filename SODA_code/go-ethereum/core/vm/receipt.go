@@ -0,0 +1,17 @@
+package vm
+
+//add new file
+
+import (
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReceiptLogs converts the logs of a transaction for its end event.
+func ReceiptLogs(logs []*types.Log) []*collector.Log {
+	out := make([]*collector.Log, len(logs))
+	for i, log := range logs {
+		out[i] = &collector.Log{Address: log.Address, Topics: log.Topics, Data: log.Data, Index: uint64(log.Index)}
+	}
+	return out
+}
@@ -0,0 +1,1142 @@
+package runtime_test
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/detectortest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Accounts of the SODA event test. Every scenario runs on a fresh state
+// holding all contracts of sodaContracts.
+var (
+	origin = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+
+	treeA, treeB, treeC     = sodaAddr(0x0a), sodaAddr(0x0b), sodaAddr(0x0c)
+	diffA, diffB, diffC     = sodaAddr(0x1a), sodaAddr(0x1b), sodaAddr(0x1c)
+	payer, suicider, refuse = sodaAddr(0x2a), sodaAddr(0x2b), sodaAddr(0x2c)
+	failer, reasoner        = sodaAddr(0x3a), sodaAddr(0x3b)
+	proxy, library          = sodaAddr(0x4a), sodaAddr(0x4b)
+	gasA, gasB              = sodaAddr(0x5a), sodaAddr(0x5b)
+	gasRevertA, gasRevertB  = sodaAddr(0x5c), sodaAddr(0x5d)
+	memA, memB              = sodaAddr(0x6a), sodaAddr(0x6b)
+	jumper                  = sodaAddr(0x70)
+	hostA                   = sodaAddr(0x80)
+	recoverer               = sodaAddr(0x90)
+	logger                  = sodaAddr(0xa0)
+	balances                = sodaAddr(0xb0)
+	tainted                 = sodaAddr(0xc0)
+	token, tokenProxy       = sodaAddr(0xd0), sodaAddr(0xd1)
+)
+
+func sodaAddr(b byte) common.Address {
+	return common.BytesToAddress([]byte{b})
+}
+
+// gasStoreSource sets a slot and clears it again for a refund.
+const gasStoreSource = `
+	push 1
+	push 0
+	sstore
+	push 0
+	push 0
+	sstore
+`
+
+// gasUsedByB is what gasStoreSource spends: four pushes, setting a slot and
+// resetting it.
+const gasUsedByB = 4*3 + params.SstoreSetGas + params.SstoreResetGas
+
+// sodaContracts are the contracts the scenarios of TestSODAEvents call.
+var sodaContracts = map[common.Address]string{
+	// a calls b with 7 wei and then delegates to c, which calls b as well
+	// but reverts.
+	treeA: `
+		push 0
+		push 0
+		push 0
+		push 0
+		push 7
+		push 0x000000000000000000000000000000000000000b
+		gas
+		call
+		pop
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000000c
+		gas
+		delegatecall
+		pop
+		stop
+	`,
+	treeB: `
+		push 1
+		push 0
+		mstore
+		push 32
+		push 0
+		return
+	`,
+	treeC: `
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000000b
+		gas
+		call
+		pop
+		push 0
+		push 0
+		revert
+	`,
+
+	// a overwrites a slot, writes another one back to its original value,
+	// pays b, which stores a value, and calls c, which stores a value too but
+	// reverts.
+	diffA: `
+		push 5
+		push 1
+		sstore
+		push 1
+		push 3
+		sstore
+		push 0
+		push 3
+		sstore
+		push 0
+		push 0
+		push 0
+		push 0
+		push 3
+		push 0x000000000000000000000000000000000000001b
+		gas
+		call
+		pop
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000001c
+		gas
+		call
+		pop
+		stop
+	`,
+	diffB: `
+		push 9
+		push 2
+		sstore
+		stop
+	`,
+	diffC: `
+		push 1
+		push 1
+		sstore
+		push 0
+		push 0
+		revert
+	`,
+
+	// The payer pays the suicider, which selfdestructs in favour of the
+	// origin, and then pays the refuser, which reverts.
+	payer: `
+		push 0
+		push 0
+		push 0
+		push 0
+		push 3
+		push 0x000000000000000000000000000000000000002b
+		gas
+		call
+		pop
+		push 0
+		push 0
+		push 0
+		push 0
+		push 2
+		push 0x000000000000000000000000000000000000002c
+		gas
+		call
+		pop
+		stop
+	`,
+	suicider: `
+		origin
+		selfdestruct
+	`,
+	refuse: `
+		push 0
+		push 0
+		revert
+	`,
+
+	// The failer calls the reasoner, which reverts with Error("no"), and
+	// then pops an empty stack.
+	failer: `
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000003b
+		gas
+		call
+		pop
+		pop
+	`,
+	reasoner: `
+		push 0x08c379a000000000000000000000000000000000000000000000000000000000
+		push 0
+		mstore
+		push 32
+		push 4
+		mstore
+		push 2
+		push 36
+		mstore
+		push 0x6e6f000000000000000000000000000000000000000000000000000000000000
+		push 68
+		mstore
+		push 100
+		push 0
+		revert
+	`,
+
+	// The proxy delegatecalls the library, which writes slot 0, and then
+	// writes slot 1 itself.
+	proxy: `
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000004b
+		gas
+		delegatecall
+		pop
+		push 2
+		push 1
+		sstore
+	`,
+	library: `
+		push 1
+		push 0
+		sstore
+	`,
+
+	// a calls b with all its gas, b earns a refund. The second pair does the
+	// same, but b reverts.
+	gasA: `
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000005b
+		gas
+		call
+		pop
+	`,
+	gasB: gasStoreSource,
+	gasRevertA: `
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000005d
+		gas
+		call
+		pop
+	`,
+	gasRevertB: gasStoreSource + `
+		push 0
+		push 0
+		revert
+	`,
+
+	// a stores 42, loads and hashes it and passes it to b, which copies its
+	// call data to memory and returns it into a's second word.
+	memA: `
+		push 42
+		push 0
+		mstore
+		push 0
+		mload
+		pop
+		push 32
+		push 0
+		sha3
+		pop
+		push 32
+		push 32
+		push 32
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000006b
+		gas
+		call
+		pop
+	`,
+	memB: `
+		push 32
+		push 0
+		push 0
+		calldatacopy
+		push 32
+		push 0
+		return
+	`,
+
+	// The jumper falls through a JUMPI at pc 7, takes the JUMPI at pc 15 to
+	// the JUMPDEST at 17 and jumps from pc 23 to the JUMPDEST at 25.
+	jumper: `
+		push 0
+		jumpi @skip
+		push 1
+		jumpi @skip
+		stop
+	skip:
+		jump @end
+		stop
+	end:
+		stop
+	`,
+
+	// hostA stores 9 and sends 3 wei to b.
+	hostA: `
+		push 9
+		push 0
+		sstore
+		push 0
+		push 0
+		push 0
+		push 0
+		push 3
+		push 0x000000000000000000000000000000000000000b
+		gas
+		call
+		pop
+	`,
+
+	// The recoverer forwards its call data to ecrecover.
+	recoverer: `
+		calldatasize
+		push 0
+		push 0
+		calldatacopy
+		push 32
+		push 0
+		calldatasize
+		push 0
+		push 1
+		gas
+		staticcall
+		stop
+	`,
+
+	// The logger logs Transfer(caller, b, 5).
+	logger: `
+		push 5
+		push 0
+		mstore
+		push 0x000000000000000000000000000000000000000b
+		caller
+		push 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+		push 32
+		push 0
+		log3
+	`,
+
+	// balances sets the entry of the sender in the mapping at slot 1 to 5 and
+	// reads the fixed slot 0.
+	balances: `
+		origin
+		push 0
+		mstore
+		push 1
+		push 32
+		mstore
+		push 64
+		push 0
+		sha3
+		push 5
+		swap1
+		sstore
+		push 0
+		sload
+		pop
+	`,
+
+	// tainted moves a timestamp through memory and storage before comparing
+	// it with the origin, compares two constants and finally stores data
+	// returned by a call.
+	tainted: `
+		timestamp
+		push 7
+		mod
+		push 0
+		mstore
+		push 0
+		mload
+		push 1
+		sstore
+
+		push 5
+		push 5
+		eq
+		pop
+
+		origin
+		push 1
+		sload
+		eq
+		jumpi @next
+
+	next:
+		push 32
+		push 0
+		push 0
+		push 0
+		push 0
+		push 0x000000000000000000000000000000000000000b
+		gas
+		call
+		pop
+		push 0
+		mload
+		push 2
+		sstore
+	`,
+
+	// Every call of the token stores the amount of a
+	// transfer(address,uint256) under the recipient and logs the transfer.
+	token: `
+		push 36
+		calldataload
+		dup1
+		push 4
+		calldataload
+		sstore
+		push 0
+		mstore
+		push 4
+		calldataload
+		caller
+		push 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+		push 32
+		push 0
+		log3
+		stop
+	`,
+	// The token proxy delegates its call data to the token.
+	tokenProxy: `
+		calldatasize
+		push 0
+		push 0
+		calldatacopy
+		push 0
+		push 0
+		calldatasize
+		push 0
+		push 0x00000000000000000000000000000000000000d0
+		gas
+		delegatecall
+		pop
+		stop
+	`,
+}
+
+// creationSource is init code deploying a contract returning a single STOP
+// with CREATE2 and salt 7, then trying a CREATE whose init code reverts.
+const creationSource = `
+	push 0x60016000f3000000000000000000000000000000000000000000000000000000
+	push 0
+	mstore
+	push 7
+	push 5
+	push 0
+	push 0
+	create2
+	pop
+	push 0x60006000fd000000000000000000000000000000000000000000000000000000
+	push 0
+	mstore
+	push 5
+	push 0
+	push 0
+	create
+	pop
+	stop
+`
+
+// unfundedCreationSource is init code of a contract without balance creating
+// an empty contract with a value of one wei.
+const unfundedCreationSource = `
+	push 0
+	push 0
+	push 1
+	create
+	pop
+	stop
+`
+
+// sodaRecorded are the events the recorder subscribes to. IAL_MEMORY brings
+// the MEMORY events, the memory instructions and the call events.
+var sodaRecorded = []string{
+	"IAL_MEMORY", "SSTORE", "SLOAD", "EQ", "JUMPI", "LOG3",
+	"TRANS_CALL", "TRANS_CREATE", "TRANS_CREATE2", "EXTERNALINFOEND",
+	"CALLTREE", "TXSTATEDIFF", "BALANCE_TRANSFER", "TOKEN_TRANSFER",
+	"PRECOMPILE", "CONTRACT_CREATED", "BRANCH", "BASICBLOCK",
+}
+
+// sodaRecorder keeps every event dispatched to it in order.
+type sodaRecorder struct {
+	events []*collector.Event
+	host   func(*collector.Event, collector.Host) // called with the host of every event, if set
+}
+
+// of returns the recorded events named option.
+func (r *sodaRecorder) of(option string) []*collector.Event {
+	var events []*collector.Event
+	for _, ev := range r.events {
+		if ev.Option == option {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// last returns the last recorded event named option.
+func (r *sodaRecorder) last(t *testing.T, option string) *collector.Event {
+	t.Helper()
+	events := r.of(option)
+	if len(events) == 0 {
+		t.Fatalf("no %s event", option)
+	}
+	return events[len(events)-1]
+}
+
+// sodaScenarios run transactions against the contracts and check the events
+// they reported.
+var sodaScenarios = []struct {
+	name  string
+	taint bool
+	run   func(t *testing.T, h *detectortest.Harness, rec *sodaRecorder)
+}{
+	{"calltree", false, testCallTree},
+	{"statediff", false, testStateDiff},
+	{"transfers", false, testBalanceTransfers},
+	{"failures", false, testFailures},
+	{"frames", false, testFrameIdentity},
+	{"gas", false, testGasAccounting},
+	{"gas-refund-reverted", false, testGasRefundReverted},
+	{"memory", false, testMemoryAccess},
+	{"controlflow", false, testControlFlow},
+	{"host", false, testHostState},
+	{"precompile", false, testPrecompile},
+	{"receipt", false, testTxEndReceipt},
+	{"slots", false, testSlotPaths},
+	{"taint", true, testTaint},
+	{"taint-opt-in", false, testTaintOptIn},
+	{"token", false, func(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) { testTokenTransfer(t, h, rec, token) }},
+	// The implementation behind a proxy runs the same call data, but the
+	// transfer is reported once, for the proxy.
+	{"token-proxy", false, func(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+		testTokenTransfer(t, h, rec, tokenProxy)
+	}},
+	{"creation", false, testContractCreated},
+	{"creation-early-failure", false, testContractCreatedEarlyFailure},
+}
+
+// TestSODAEvents runs every scenario as whole transactions on an in-memory
+// chain and checks the events the plugins receive.
+func TestSODAEvents(t *testing.T) {
+	for _, scenario := range sodaScenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			var (
+				h        = detectortest.New(t)
+				rec      = new(sodaRecorder)
+				handlers = make(map[string]interface{})
+			)
+			for _, event := range sodaRecorded {
+				handlers[event] = func(ev *collector.Event, host collector.Host) (byte, string) {
+					rec.events = append(rec.events, ev)
+					if rec.host != nil {
+						rec.host(ev, host)
+					}
+					return 0, ""
+				}
+			}
+			if scenario.taint {
+				h.SubscribeTaint("events", handlers)
+			} else {
+				h.Subscribe("events", handlers)
+			}
+			for addr, source := range sodaContracts {
+				h.Deploy(addr, detectortest.Assemble(t, source))
+			}
+			h.Fund(origin, big.NewInt(100))
+			scenario.run(t, h, rec)
+		})
+	}
+}
+
+func testCallTree(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, treeA, []byte{0xab}, big.NewInt(10)); err != nil {
+		t.Fatal(err)
+	}
+	tree := rec.last(t, "CALLTREE").CallTree
+	if tree.Type != collector.MessageCall || tree.Caller != origin || tree.Callee != treeA ||
+		tree.Value != collector.Uint64ToWord(10) || !bytes.Equal(tree.Input, []byte{0xab}) || !tree.Committed() {
+		t.Errorf("unexpected root frame: %+v", tree)
+	}
+	if tree.GasUsed == 0 || tree.GasUsed > tree.Gas {
+		t.Errorf("root gas used %d out of range, gas %d", tree.GasUsed, tree.Gas)
+	}
+	if len(tree.Children) != 2 {
+		t.Fatalf("root has %d children, want 2", len(tree.Children))
+	}
+	call, delegate := tree.Children[0], tree.Children[1]
+	if call.Type != collector.MessageCall || call.Caller != treeA || call.Callee != treeB || call.Depth != 1 ||
+		call.Value != collector.Uint64ToWord(7) || len(call.Output) != 32 || call.Output[31] != 1 || !call.Committed() {
+		t.Errorf("unexpected call frame: %+v", call)
+	}
+	if delegate.Type != collector.MessageDelegateCall || delegate.Caller != origin || delegate.Callee != treeA ||
+		delegate.CodeAddress != treeC || delegate.Success || delegate.Reverted {
+		t.Errorf("unexpected delegate call frame: %+v", delegate)
+	}
+	if len(delegate.Children) != 1 {
+		t.Fatalf("delegate call has %d children, want 1", len(delegate.Children))
+	}
+	if inner := delegate.Children[0]; inner.Caller != treeA || inner.Callee != treeB || inner.Depth != 2 ||
+		!inner.Success || !inner.Reverted {
+		t.Errorf("unexpected frame under the reverted delegate call: %+v", inner)
+	}
+	if layers := []uint64{tree.CallLayer, call.CallLayer, delegate.CallLayer, delegate.Children[0].CallLayer}; layers[0] != 1 ||
+		layers[1] <= layers[0] || layers[2] <= layers[1] || layers[3] <= layers[2] {
+		t.Errorf("frame ids not increasing in execution order: %v", layers)
+	}
+	if have := h.State().GetBalance(treeB); have.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("balance of b mismatch: have %v, want 7", have)
+	}
+}
+
+func testStateDiff(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	h.SetStorage(diffA, common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(4)))
+	if _, err := h.Call(origin, diffA, nil, big.NewInt(10)); err != nil {
+		t.Fatal(err)
+	}
+	diff, tree := rec.last(t, "TXSTATEDIFF").StateDiff, rec.last(t, "CALLTREE").CallTree
+	if len(tree.Children) != 2 {
+		t.Fatalf("root has %d children, want 2", len(tree.Children))
+	}
+	frameA, frameB := tree.CallLayer, tree.Children[0].CallLayer
+
+	if len(diff.Accounts) != 3 {
+		t.Errorf("diff has %d accounts, want 3", len(diff.Accounts))
+	}
+	if diff.Account(diffC) != nil {
+		t.Errorf("reverted account c in diff: %+v", diff.Account(diffC))
+	}
+	sender := diff.Account(origin)
+	if sender == nil || !sender.BalanceChanged || sender.PreBalance != collector.Uint64ToWord(100) ||
+		sender.PostBalance != collector.Uint64ToWord(90) || sender.BalanceFrame != frameA || sender.NonceChanged {
+		t.Errorf("unexpected origin diff: %+v", sender)
+	}
+
+	a := diff.Account(diffA)
+	if a == nil || a.Created || !a.BalanceChanged || a.PostBalance != collector.Uint64ToWord(7) || a.BalanceFrame != frameB {
+		t.Fatalf("unexpected diff of a: %+v", a)
+	}
+	if len(a.Storage) != 1 {
+		t.Fatalf("a has %d changed slots, want 1", len(a.Storage))
+	}
+	if slot := a.Storage[0]; slot.Key != common.BigToHash(big.NewInt(1)) || slot.Pre != common.BigToHash(big.NewInt(4)) ||
+		slot.Post != common.BigToHash(big.NewInt(5)) || slot.Frame != frameA {
+		t.Errorf("unexpected slot diff of a: %+v", slot)
+	}
+
+	b := diff.Account(diffB)
+	if b == nil || b.PreBalance != (collector.Word{}) || b.PostBalance != collector.Uint64ToWord(3) || b.BalanceFrame != frameB {
+		t.Fatalf("unexpected diff of b: %+v", b)
+	}
+	if len(b.Storage) != 1 || b.Storage[0].Post != common.BigToHash(big.NewInt(9)) || b.Storage[0].Frame != frameB {
+		t.Errorf("unexpected storage diff of b: %+v", b.Storage)
+	}
+}
+
+func testBalanceTransfers(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, payer, nil, big.NewInt(10)); err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		reason   string
+		from, to common.Address
+		amount   uint64
+		reverted bool
+	}{
+		{collector.TransferCall, origin, payer, 10, false},
+		{collector.TransferCall, payer, suicider, 3, false},
+		{collector.TransferSuicide, suicider, origin, 3, false},
+		{collector.TransferCall, payer, refuse, 2, true},
+	}
+	events := rec.of("BALANCE_TRANSFER")
+	if len(events) != len(want) {
+		t.Fatalf("have %d transfers, want %d", len(events), len(want))
+	}
+	for i, w := range want {
+		tr := events[i].Transfer
+		if tr.Reason != w.reason || tr.From != w.from || tr.To != w.to || tr.Amount != collector.Uint64ToWord(w.amount) || tr.Reverted != w.reverted {
+			t.Errorf("transfer %d mismatch: have %+v, want %+v", i, tr, w)
+		}
+	}
+	if l := []uint64{events[0].Transfer.CallLayer, events[1].Transfer.CallLayer, events[2].Transfer.CallLayer, events[3].Transfer.CallLayer}; l[0] != 1 || l[1] != l[2] || l[3] <= l[2] {
+		t.Errorf("unexpected frames: %v", l)
+	}
+}
+
+func testFailures(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, failer, nil, nil); err == nil {
+		t.Fatal("transaction succeeded")
+	}
+	if messages := rec.of("TRANS_CALL"); len(messages) != 1 || messages[0].Message.Failure != collector.FailureRevert ||
+		messages[0].Message.RevertReason != "no" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+	tree := rec.last(t, "CALLTREE").CallTree
+	if tree.Failure != collector.FailureStack || len(tree.Children) != 1 ||
+		tree.Children[0].Failure != collector.FailureRevert || tree.Children[0].RevertReason != "no" {
+		t.Errorf("unexpected call tree: %+v", tree)
+	}
+	if end := rec.last(t, "EXTERNALINFOEND").TxEnd; end.Success || end.Failure != collector.FailureStack || end.RevertReason != "" {
+		t.Errorf("unexpected transaction end: %+v", end)
+	}
+}
+
+func testFrameIdentity(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, proxy, nil, big.NewInt(5)); err != nil {
+		t.Fatal(err)
+	}
+	stores := rec.of("SSTORE")
+	if len(stores) != 2 {
+		t.Fatalf("have %d SSTORE events, want 2", len(stores))
+	}
+	// The library writes the storage of the proxy, as its caller's caller.
+	if lib := stores[0].Ins; lib.FrameType != collector.MessageDelegateCall || lib.CodeAddress != library || lib.StorageAddress != proxy ||
+		lib.Sender != origin || lib.CallValue != collector.Uint64ToWord(5) || lib.CallContract != library {
+		t.Errorf("unexpected library SSTORE: %+v", lib)
+	}
+	if p := stores[1].Ins; p.FrameType != collector.MessageCall || p.CodeAddress != proxy || p.StorageAddress != proxy ||
+		p.Sender != origin || p.CallValue != collector.Uint64ToWord(5) {
+		t.Errorf("unexpected proxy SSTORE: %+v", p)
+	}
+	// The end of the delegate call runs in the frame of the proxy again.
+	if end := rec.last(t, "DELEGATECALLEND").Ins; end.FrameType != collector.MessageCall || end.CodeAddress != proxy ||
+		end.StorageAddress != proxy || end.Sender != origin {
+		t.Errorf("unexpected DELEGATECALLEND frame: %+v", end)
+	}
+	if slot := h.State().GetState(proxy, common.Hash{}); slot != common.BigToHash(big.NewInt(1)) {
+		t.Errorf("library wrote %x to the proxy, want 1", slot)
+	}
+}
+
+func testGasAccounting(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, gasA, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	stores := rec.of("SSTORE")
+	if len(stores) != 2 {
+		t.Fatalf("have %d SSTORE events, want 2", len(stores))
+	}
+	set, reset := stores[0].Ins, stores[1].Ins
+	if set.StaticGas != 0 || set.DynamicGas != params.SstoreSetGas || set.RealGasUsed != params.SstoreSetGas ||
+		set.RefundBefore != 0 || set.RefundAfter != 0 {
+		t.Errorf("unexpected gas of setting the slot: %+v", set)
+	}
+	if reset.DynamicGas != params.SstoreResetGas || reset.GasBefore != set.GasBefore-set.DynamicGas-2*3 ||
+		reset.RefundBefore != 0 || reset.RefundAfter != params.SstoreRefundGas {
+		t.Errorf("unexpected gas of clearing the slot: %+v", reset)
+	}
+
+	// The call costs its own 700 gas, what it forwards is spent by b. The
+	// gas table prices calls dynamically.
+	start, end := rec.last(t, "CALLSTART").Ins, rec.last(t, "CALLEND").Ins
+	callGas := params.GasTableConstantinople.Calls
+	if start.StaticGas != 0 || start.DynamicGas != callGas || start.RealGasUsed != callGas ||
+		start.GasForwarded == 0 || start.AllocatedGas != start.GasForwarded ||
+		start.GasForwarded > start.GasBefore-callGas {
+		t.Errorf("unexpected gas of the call start: %+v", start)
+	}
+	if end.GasBefore != start.GasBefore || end.GasForwarded != start.GasForwarded || end.RealGasUsed != gasUsedByB ||
+		end.GasReturned != start.GasForwarded-gasUsedByB || end.RefundAfter != params.SstoreRefundGas {
+		t.Errorf("unexpected gas of the call end: %+v", end)
+	}
+
+	tree := rec.last(t, "CALLTREE").CallTree
+	if len(tree.Children) != 1 {
+		t.Fatalf("unexpected call tree: %+v", tree)
+	}
+	if child := tree.Children[0]; child.GasUsed != gasUsedByB || child.GasSelf != gasUsedByB ||
+		child.RefundAdded != params.SstoreRefundGas || child.RefundRemoved != 0 {
+		t.Errorf("unexpected gas of b's frame: %+v", child)
+	}
+	if tree.GasSelf != tree.GasUsed-gasUsedByB || tree.RefundAdded != 0 {
+		t.Errorf("unexpected gas of a's frame: used %d, self %d, refund %d", tree.GasUsed, tree.GasSelf, tree.RefundAdded)
+	}
+}
+
+func testGasRefundReverted(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, gasRevertA, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if end := rec.last(t, "CALLEND").Ins; end.RefundAfter != 0 {
+		t.Errorf("unexpected call end: %+v", end)
+	}
+	tree := rec.last(t, "CALLTREE").CallTree
+	if len(tree.Children) != 1 {
+		t.Fatalf("unexpected call tree: %+v", tree)
+	}
+	if child := tree.Children[0]; child.Success || child.RefundAdded != 0 || child.RefundRemoved != 0 {
+		t.Errorf("refunds of the reverted frame kept: %+v", child)
+	}
+}
+
+func testMemoryAccess(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, memA, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if mloads := len(rec.of("MLOAD")); mloads != 1 {
+		t.Errorf("have %d MLOAD events, want 1", mloads)
+	}
+	want := []struct {
+		op, direction  string
+		code           common.Address
+		offset, length uint64
+	}{
+		{"MSTORE", collector.MemoryWrite, memA, 0, 32},
+		{"MLOAD", collector.MemoryRead, memA, 0, 32},
+		{"SHA3", collector.MemoryRead, memA, 0, 32},
+		{"CALL", collector.MemoryRead, memA, 0, 32},
+		{"CALLDATACOPY", collector.MemoryWrite, memB, 0, 32},
+		{"RETURN", collector.MemoryRead, memB, 0, 32},
+		{"CALL", collector.MemoryWrite, memA, 32, 32},
+	}
+	events := rec.of("MEMORY")
+	if len(events) != len(want) {
+		t.Fatalf("have %d memory accesses, want %d", len(events), len(want))
+	}
+	word := common.BigToHash(big.NewInt(42))
+	for i, w := range want {
+		a := events[i].Memory
+		if a.Op != w.op || a.Direction != w.direction || a.CodeAddress != w.code || a.Offset != w.offset ||
+			a.Length != w.length || common.BytesToHash(a.Data) != word {
+			t.Errorf("access %d: have %s %s of %x at %d+%d: %x, want %s %s of %x at %d+%d", i,
+				a.Op, a.Direction, a.CodeAddress, a.Offset, a.Length, a.Data, w.op, w.direction, w.code, w.offset, w.length)
+		}
+	}
+	if call, callee, ret := events[3].Memory, events[4].Memory, events[6].Memory; ret.CallLayer != call.CallLayer || callee.CallLayer <= call.CallLayer {
+		t.Errorf("unexpected call layers: call %d, callee %d, return %d", call.CallLayer, callee.CallLayer, ret.CallLayer)
+	}
+}
+
+func testControlFlow(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, jumper, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	hash := h.State().GetCodeHash(jumper)
+	want := []collector.Branch{
+		{Op: "JUMPI", Pc: 7, Dest: collector.Uint64ToWord(17), Next: 8},
+		{Op: "JUMPI", Pc: 15, Dest: collector.Uint64ToWord(17), Condition: collector.Uint64ToWord(1), Taken: true, Next: 17},
+		{Op: "JUMP", Pc: 23, Dest: collector.Uint64ToWord(25), Taken: true, Next: 25},
+	}
+	branches := rec.of("BRANCH")
+	if len(branches) != len(want) {
+		t.Fatalf("have %d branches, want %d", len(branches), len(want))
+	}
+	for i, w := range want {
+		w.CallLayer, w.CodeAddress, w.CodeHash = 1, jumper, hash
+		if *branches[i].Branch != w {
+			t.Errorf("branch %d: have %+v, want %+v", i, branches[i].Branch, w)
+		}
+	}
+	wantBlocks := [][2]uint64{{0, 7}, {8, 15}, {17, 23}, {25, 26}}
+	blocks := rec.of("BASICBLOCK")
+	if len(blocks) != len(wantBlocks) {
+		t.Fatalf("have %d basic blocks, want %d", len(blocks), len(wantBlocks))
+	}
+	for i, w := range wantBlocks {
+		if b := blocks[i].BasicBlock; b.Start != w[0] || b.End != w[1] || b.CallLayer != 1 || b.CodeAddress != jumper || b.CodeHash != hash {
+			t.Errorf("block %d: have %+v, want %d-%d", i, b, w[0], w[1])
+		}
+	}
+}
+
+func testHostState(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	var (
+		calls int
+		code  = h.State().GetCode(hostA)
+	)
+	h.Fund(hostA, big.NewInt(10))
+	rec.host = func(ev *collector.Event, host collector.Host) {
+		if ev.Option != "TRANS_CALL" {
+			return
+		}
+		calls++
+		// The state is the one of the running transaction.
+		if have := host.GetState(hostA, common.Hash{}); have != common.BigToHash(big.NewInt(9)) {
+			t.Errorf("slot mismatch: have %x, want 9", have)
+		}
+		if have := host.GetBalance(treeB); have.Cmp(big.NewInt(3)) != 0 {
+			t.Errorf("balance mismatch: have %v, want 3", have)
+		}
+		if have := host.GetCode(hostA); !bytes.Equal(have, code) || host.GetCodeHash(hostA) != crypto.Keccak256Hash(code) {
+			t.Errorf("code mismatch: have %x", have)
+		}
+		if !host.Exist(hostA) || host.Exist(common.HexToAddress("0xdead")) || host.GetNonce(hostA) != 0 {
+			t.Errorf("unexpected accounts")
+		}
+		if header := host.GetHeaderByNumber(1); header == nil || header.Number != 1 {
+			t.Errorf("unexpected current header %+v", header)
+		}
+		// Values handed out don't alias the state.
+		host.GetBalance(treeB).SetInt64(1000)
+		host.GetCode(hostA)[0] = 0xff
+	}
+	if _, err := h.Call(origin, hostA, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("have %d calls, want 1", calls)
+	}
+	if have := h.State().GetBalance(treeB); have.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("handler changed the balance of b to %v", have)
+	}
+	if have := h.State().GetCode(hostA); !bytes.Equal(have, code) {
+		t.Errorf("handler changed the code of a to %x", have)
+	}
+}
+
+func testPrecompile(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	key, _ := crypto.GenerateKey()
+	hash := crypto.Keccak256([]byte("soda"))
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := append(hash, common.LeftPadBytes([]byte{sig[64] + 27}, 32)...)
+	input = append(input, sig[:64]...)
+	if _, err := h.Call(origin, recoverer, input, nil); err != nil {
+		t.Fatal(err)
+	}
+	calls := rec.of("PRECOMPILE")
+	if len(calls) != 1 {
+		t.Fatalf("have %d precompile calls, want 1", len(calls))
+	}
+	call := calls[0].Precompile
+	if call.Name != collector.PrecompileEcrecover || call.Caller != recoverer || call.CallLayer != 2 || call.Gas != 3000 ||
+		call.Failure != "" || call.Signer != crypto.PubkeyToAddress(key.PublicKey) || call.HighS {
+		t.Errorf("unexpected precompile call: %+v", call)
+	}
+	if call.Decoded == nil || len(call.Decoded.Args) != 4 || call.Decoded.Args[1].Value != strconv.Itoa(int(sig[64])+27) {
+		t.Errorf("unexpected decoded input: %v", call.Decoded)
+	}
+}
+
+func testTxEndReceipt(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	// Every transaction reports its own logs only.
+	for i := 0; i < 2; i++ {
+		if _, err := h.Call(origin, logger, []byte{0x01}, big.NewInt(7)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ends := rec.of("EXTERNALINFOEND"); len(ends) != 2 {
+		t.Fatalf("have %d transaction ends, want 2", len(ends))
+	}
+	end := rec.last(t, "EXTERNALINFOEND").TxEnd
+	if end.From != origin || end.To != logger || end.Value != collector.Uint64ToWord(7) || len(end.Input) != 1 ||
+		end.Status != types.ReceiptStatusSuccessful || end.CumulativeGasUsed != end.GasUsed {
+		t.Errorf("unexpected transaction end: %+v", end)
+	}
+	if len(end.Logs) != 1 {
+		t.Fatalf("have %d logs, want 1", len(end.Logs))
+	}
+	log := end.Logs[0]
+	if log.Address != logger || len(log.Topics) != 3 || log.Topics[1] != origin.Hash() || log.Index != 1 {
+		t.Errorf("unexpected log: %+v", log)
+	}
+	if log.Decoded == nil || log.Decoded.String() != "Transfer(address: "+origin.Hex()+", address: "+treeB.Hex()+", uint256: 5)" {
+		t.Errorf("unexpected decoded log: %v", log.Decoded)
+	}
+	if bloom := types.BytesToBloom(end.Bloom); !types.BloomLookup(bloom, logger) || types.BloomLookup(bloom, common.HexToAddress("0xdead")) {
+		t.Errorf("unexpected bloom %x", end.Bloom)
+	}
+}
+
+func testSlotPaths(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, balances, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	stores, loads := rec.of("SSTORE"), rec.of("SLOAD")
+	if len(stores) != 1 || len(loads) != 1 {
+		t.Fatalf("have %d SSTORE and %d SLOAD events, want 1 each", len(stores), len(loads))
+	}
+	store, load := stores[0].Ins.Slot, loads[0].Ins.Slot
+	if store == nil || store.Kind != collector.SlotMapping || common.BytesToAddress(store.Key) != origin ||
+		store.Base.Kind != collector.SlotFixed || store.Base.Slot != collector.Uint64ToWord(1) {
+		t.Errorf("unexpected SSTORE slot: %+v", store)
+	}
+	if load == nil || load.Kind != collector.SlotFixed || load.Slot != (collector.Word{}) {
+		t.Errorf("unexpected SLOAD slot: %+v", load)
+	}
+
+	hash := h.State().GetCodeHash(balances)
+	layout := pluginManage.Layouts.Layout(hash)
+	if len(layout) != 2 {
+		t.Fatalf("have %d storage variables, want 2", len(layout))
+	}
+	if v := layout[0]; v.Slot != (collector.Word{}) || v.Type != "value" || v.Reads != 1 || v.Writes != 0 {
+		t.Errorf("unexpected variable at slot 0: %+v", v)
+	}
+	if v := layout[1]; v.Slot != collector.Uint64ToWord(1) || v.Type != "mapping" || v.Fields != 1 || v.Writes != 1 {
+		t.Errorf("unexpected variable at slot 1: %+v", v)
+	}
+	if v := pluginManage.Layouts.Variable(hash, store); v == nil || *v != *layout[1] {
+		t.Errorf("SSTORE slot resolved to %+v", v)
+	}
+}
+
+func testTaint(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, tainted, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	type sink struct {
+		op    string
+		taint []collector.Taint
+	}
+	var sinks []sink
+	for _, ev := range rec.events {
+		if ev.Option == "EQ" || ev.Option == "JUMPI" || ev.Option == "SSTORE" {
+			sinks = append(sinks, sink{ev.Ins.OpName, ev.Ins.ArgTaint})
+		}
+	}
+	stored := collector.TaintStorage | collector.TaintTimestamp
+	want := []sink{
+		{"SSTORE", []collector.Taint{0, collector.TaintTimestamp}},
+		{"EQ", []collector.Taint{0, 0}},
+		{"EQ", []collector.Taint{stored, collector.TaintOrigin}},
+		{"JUMPI", []collector.Taint{0, stored | collector.TaintOrigin}},
+		{"SSTORE", []collector.Taint{0, collector.TaintReturnData}},
+	}
+	if !reflect.DeepEqual(sinks, want) {
+		t.Errorf("sink taint mismatch:\nhave %v\nwant %v", sinks, want)
+	}
+}
+
+func testTaintOptIn(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	if _, err := h.Call(origin, tainted, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, ev := range rec.of("SSTORE") {
+		if ev.Ins.ArgTaint != nil {
+			t.Error("taint tracked without a plugin asking for it")
+		}
+	}
+	if h.Manager.Taints() {
+		t.Error("manager reports taint tracking")
+	}
+}
+
+func testTokenTransfer(t *testing.T, h *detectortest.Harness, rec *sodaRecorder, to common.Address) {
+	amount := common.BigToHash(big.NewInt(5))
+	input := append(common.FromHex("a9059cbb"), treeB.Hash().Bytes()...)
+	input = append(input, amount[:]...)
+	if _, err := h.Call(origin, to, input, nil); err != nil {
+		t.Fatal(err)
+	}
+	transfers := rec.of("TOKEN_TRANSFER")
+	if len(transfers) != 1 {
+		t.Fatalf("have %d token transfers, want 1", len(transfers))
+	}
+	tr := transfers[0].TokenTransfer
+	if tr.Action != collector.TokenTransferred || tr.Standard != collector.TokenERC20 || tr.Token != to ||
+		tr.Method != "transfer" || tr.From != origin || tr.To != treeB || tr.Amount != collector.Word(amount) ||
+		!tr.FromCall || !tr.FromLog || !tr.Consistent || tr.Reverted || tr.CallLayer != 1 {
+		t.Errorf("unexpected token transfer: %+v", tr)
+	}
+	if logs := rec.of("LOG3"); len(logs) != 1 || logs[0].Ins.Decoded.String() != "Transfer(address: "+origin.Hex()+", address: "+treeB.Hex()+", uint256: 5)" {
+		t.Errorf("unexpected decoded logs: %v", logs)
+	}
+	if len(tr.Slots) != 1 || tr.Slots[0].Key != treeB.Hash() || tr.Slots[0].Post != amount {
+		t.Errorf("unexpected balance slots: %+v", tr.Slots)
+	}
+}
+
+func testContractCreated(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	code := detectortest.Assemble(t, creationSource)
+	factory, err := h.Create(origin, code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creations := rec.of("CONTRACT_CREATED")
+	if len(creations) != 3 {
+		t.Fatalf("have %d creations, want 3", len(creations))
+	}
+	// Creations are reported when they return, innermost first.
+	child := crypto.CreateAddress2(factory, common.BigToHash(big.NewInt(7)), crypto.Keccak256(common.FromHex("0x60016000f3")))
+	if c := creations[0].Creation; c.Type != collector.CreatedByCreate2 || c.Creator != factory || c.Address != child ||
+		c.Deployer != origin || c.Salt != collector.Uint64ToWord(7) || c.Depth != 1 || c.CallLayer != 2 ||
+		!c.Success || c.Failure != "" || len(c.RuntimeCode) != 1 || c.RuntimeCode[0] != 0x00 {
+		t.Errorf("unexpected CREATE2: %+v", c)
+	}
+	if c := creations[1].Creation; c.Type != collector.CreatedByCreate || c.Creator != factory || c.Depth != 1 || c.CallLayer != 3 ||
+		c.Success || c.Failure != collector.FailureRevert || len(c.RuntimeCode) != 0 || c.Salt != (collector.Word{}) ||
+		c.InitCodeHash != crypto.Keccak256Hash(common.FromHex("0x60006000fd")) {
+		t.Errorf("unexpected CREATE: %+v", c)
+	}
+	if c := creations[2].Creation; c.Type != collector.CreatedByTx || c.Creator != origin || c.Deployer != origin ||
+		c.Address != factory || c.Depth != 0 || c.CallLayer != 1 || !c.Success || c.InitCodeHash != crypto.Keccak256Hash(code) {
+		t.Errorf("unexpected transaction creation: %+v", c)
+	}
+
+	create2, create := rec.of("TRANS_CREATE2"), rec.of("TRANS_CREATE")
+	if len(create2) != 1 || len(create) != 1 {
+		t.Fatalf("have %d TRANS_CREATE2 and %d TRANS_CREATE messages, want 1 each", len(create2), len(create))
+	}
+	if m := create2[0].Message; m.Type != collector.MessageCreate2 || !m.Success || m.To != child || m.Salt != collector.Uint64ToWord(7) {
+		t.Errorf("unexpected TRANS_CREATE2: %+v", m)
+	}
+	if m := create[0].Message; m.Type != collector.MessageCreate || m.Success || m.Failure != collector.FailureRevert {
+		t.Errorf("unexpected TRANS_CREATE: %+v", m)
+	}
+}
+
+// A creation failing before it runs, here for lack of balance, still reports
+// the address it would have deployed to.
+func testContractCreatedEarlyFailure(t *testing.T, h *detectortest.Harness, rec *sodaRecorder) {
+	factory, err := h.Create(origin, detectortest.Assemble(t, unfundedCreationSource), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creations := rec.of("CONTRACT_CREATED")
+	if len(creations) != 2 {
+		t.Fatalf("have %d creations, want 2", len(creations))
+	}
+	if c := creations[0].Creation; c.Type != collector.CreatedByCreate || c.Creator != factory || c.Address != crypto.CreateAddress(factory, 1) ||
+		c.Success || c.Failure != collector.FailureInsufficientBalance {
+		t.Errorf("unexpected CREATE: %+v", c)
+	}
+}
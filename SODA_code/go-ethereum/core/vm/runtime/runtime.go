@@ -46,6 +46,10 @@ type Config struct {
 
 	State     *state.StateDB
 	GetHashFn func(n uint64) common.Hash
+
+	// TxStart runs the code as a whole transaction, reporting it and its
+	// instructions to the SODA plugins on ChainConfig.TransferDataPlg.
+	TxStart bool
 }
 
 // sets defaults on the config
@@ -109,15 +113,21 @@ func Execute(code, input []byte, cfg *Config) ([]byte, *state.StateDB, error) {
 	cfg.State.CreateAccount(address)
 	// set the receiver's (the executing contract) code for execution.
 	cfg.State.SetCode(address, code)
+	var tx *sodaTx
+	if cfg.TxStart {
+		tx = startTx(vmenv, cfg, &address, input)
+	}
 	// Call the code with the given configuration.
-	ret, _, err := vmenv.Call(
+	ret, leftOverGas, err := vmenv.Call(
 		sender,
 		common.BytesToAddress([]byte("contract")),
 		input,
 		cfg.GasLimit,
 		cfg.Value,
 	)
-
+	if cfg.TxStart {
+		endTx(vmenv, cfg, tx, common.Address{}, cfg.GasLimit-leftOverGas, err)
+	}
 	return ret, cfg.State, err
 }
 
@@ -135,7 +145,10 @@ func Create(input []byte, cfg *Config) ([]byte, common.Address, uint64, error) {
 		vmenv  = NewEnv(cfg)
 		sender = vm.AccountRef(cfg.Origin)
 	)
-
+	var tx *sodaTx
+	if cfg.TxStart {
+		tx = startTx(vmenv, cfg, nil, input)
+	}
 	// Call the code with the given configuration.
 	code, address, leftOverGas, err := vmenv.Create(
 		sender,
@@ -143,6 +156,9 @@ func Create(input []byte, cfg *Config) ([]byte, common.Address, uint64, error) {
 		cfg.GasLimit,
 		cfg.Value,
 	)
+	if cfg.TxStart {
+		endTx(vmenv, cfg, tx, address, cfg.GasLimit-leftOverGas, err)
+	}
 	return code, address, leftOverGas, err
 }
 
@@ -157,6 +173,10 @@ func Call(address common.Address, input []byte, cfg *Config) ([]byte, uint64, er
 	vmenv := NewEnv(cfg)
 
 	sender := cfg.State.GetOrNewStateObject(cfg.Origin)
+	var tx *sodaTx
+	if cfg.TxStart {
+		tx = startTx(vmenv, cfg, &address, input)
+	}
 	// Call the code with the given configuration.
 	ret, leftOverGas, err := vmenv.Call(
 		sender,
@@ -165,6 +185,8 @@ func Call(address common.Address, input []byte, cfg *Config) ([]byte, uint64, er
 		cfg.GasLimit,
 		cfg.Value,
 	)
-
+	if cfg.TxStart {
+		endTx(vmenv, cfg, tx, common.Address{}, cfg.GasLimit-leftOverGas, err)
+	}
 	return ret, leftOverGas, err
 }
@@ -0,0 +1,60 @@
+package runtime
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// sodaTx is a transaction of the runtime as reported to the plugins. The
+// runtime runs all transactions under the zero hash, so firstLog is the
+// number of logs the state held when it started.
+type sodaTx struct {
+	tx       *vm.SODATx
+	firstLog int
+}
+
+// startTx announces the transaction about to run to the plugins, the way
+// core.ApplyTransaction does for a block transaction. A nil to starts a
+// contract creation.
+func startTx(vmenv *vm.EVM, cfg *Config, to *common.Address, input []byte) *sodaTx {
+	tx := &vm.SODATx{
+		From:     cfg.Origin,
+		To:       to,
+		Value:    cfg.Value,
+		GasPrice: cfg.GasPrice,
+		Gas:      cfg.GasLimit,
+		Nonce:    cfg.State.GetNonce(cfg.Origin),
+		Input:    input,
+	}
+	header := &types.Header{
+		Number:     cfg.BlockNumber,
+		Time:       cfg.Time.Uint64(),
+		Coinbase:   cfg.Coinbase,
+		Difficulty: cfg.Difficulty,
+		GasLimit:   cfg.GasLimit,
+	}
+	first := len(cfg.State.GetLogs(common.Hash{}))
+	vmenv.StartSODATx(cfg.State, nil, header, tx)
+	return &sodaTx{tx: tx, firstLog: first}
+}
+
+// endTx reports what the transaction did and its end along with the receipt
+// it would have had. Created is the address of the contract deployed by a
+// creation.
+func endTx(vmenv *vm.EVM, cfg *Config, stx *sodaTx, created common.Address, gasUsed uint64, err error) {
+	vmenv.ReportSODATx(cfg.State)
+
+	receipt := &types.Receipt{
+		CumulativeGasUsed: gasUsed,
+		Logs:              cfg.State.GetLogs(common.Hash{})[stx.firstLog:],
+		ContractAddress:   created,
+		GasUsed:           gasUsed,
+		BlockNumber:       cfg.BlockNumber,
+	}
+	if err == nil {
+		receipt.Status = types.ReceiptStatusSuccessful
+	}
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	vmenv.EndSODATx(cfg.State, stx.tx, gasUsed, receipt, "")
+}
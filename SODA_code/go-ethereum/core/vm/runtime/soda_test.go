@@ -0,0 +1,48 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/plugintest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+)
+
+// BenchmarkSODA measures the cost of a contract call with no plugins, with
+// every detector on its own and with all of them together. Compare runs with
+// benchstat to spot overhead regressions.
+func BenchmarkSODA(b *testing.B) {
+	for _, suite := range plugintest.Suites() {
+		// Register before starting the sub-benchmark, the plugins print
+		// their log paths while loading.
+		plg := plugintest.NewManager(b, suite.Plugins...)
+		b.Run(suite.Name, func(b *testing.B) {
+			benchmarkSODA(b, plg)
+		})
+	}
+}
+
+func benchmarkSODA(b *testing.B, plg *pluginManage.PluginManages) {
+	var (
+		address = common.BytesToAddress([]byte("workload"))
+		input   = plugintest.WorkloadInput(1)
+		cfg     = &Config{TxStart: true}
+	)
+	setDefaults(cfg)
+	cfg.State, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	cfg.State.SetCode(address, plugintest.Workload)
+	cfg.ChainConfig.TransferDataPlg = plg
+
+	b.ResetTimer()
+	start, begin := plg.Dispatched(), time.Now()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := Call(address, input, cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	plugintest.ReportCost(b, time.Since(begin), plg.Dispatched()-start, 0)
+}
@@ -0,0 +1,25 @@
+package vm
+
+//add new file
+
+import (
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// tracksSlots reports whether SLOAD or SSTORE events explain their slot, so
+// SHA3 has to record its preimages.
+func (evm *EVM) tracksSlots() bool {
+	plg := evm.chainConfig.TransferDataPlg
+	return evm.isTxStart && (plg.Collects(byte(SLOAD)) || plg.Collects(byte(SSTORE)))
+}
+
+// explainSlot explains a slot accessed by the contract and teaches the
+// storage layout of its code.
+func explainSlot(contract *Contract, slot common.Hash, write bool) *collector.SlotPath {
+	path := tingrong.PREIMAGES.Explain(slot)
+	pluginManage.Layouts.Learn(contract.CodeHash, path, write)
+	return path
+}
@@ -0,0 +1,80 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+func TestTracksSlots(t *testing.T) {
+	handler := func(*collector.Event) (byte, string) { return 0, "" }
+	tests := []struct {
+		events  []string
+		txStart bool
+		want    bool
+	}{
+		{[]string{"SLOAD"}, true, true},
+		{[]string{"SSTORE"}, true, true},
+		{[]string{"SHA3", "CALL"}, true, false},
+		{[]string{"SSTORE"}, false, false},
+	}
+	for i, tt := range tests {
+		handlers := make(map[string]interface{})
+		for _, ev := range tt.events {
+			handlers[ev] = handler
+		}
+		in := newTestInterpreter(t, handlers)
+		in.evm.SetTxStart(tt.txStart)
+		if have := in.evm.tracksSlots(); have != tt.want {
+			t.Errorf("test %d: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestExplainSlot(t *testing.T) {
+	defer tingrong.PREIMAGES.Reset()
+
+	var (
+		key      = common.Address{0xaa}.Hash()
+		base     = common.BigToHash(common.Big1)
+		preimage = append(key.Bytes(), base.Bytes()...)
+		entry    = crypto.Keccak256Hash(preimage)
+		contract = NewContract(AccountRef(common.Address{1}), AccountRef(common.Address{2}), common.Big0, 0)
+	)
+	contract.CodeHash = crypto.Keccak256Hash([]byte("explainSlot"))
+	tingrong.PREIMAGES.Reset()
+	tingrong.PREIMAGES.Add(entry, preimage)
+
+	tests := []struct {
+		slot  common.Hash
+		write bool
+		kind  string
+	}{
+		{entry, true, collector.SlotMapping},
+		{common.BigToHash(common.Big2), false, collector.SlotFixed},
+	}
+	for i, tt := range tests {
+		path := explainSlot(contract, tt.slot, tt.write)
+		if path == nil || path.Kind != tt.kind {
+			t.Fatalf("test %d: unexpected path %+v", i, path)
+		}
+	}
+	if path := explainSlot(contract, entry, false); path.Base == nil || path.Base.Slot != collector.Word(base) || common.BytesToHash(path.Key) != key {
+		t.Errorf("unexpected mapping path %+v", path)
+	}
+	// The layout of the code learnt the mapping and the plain slot.
+	layout := pluginManage.Layouts.Layout(contract.CodeHash)
+	if len(layout) != 2 {
+		t.Fatalf("have %d variables, want 2", len(layout))
+	}
+	if v := layout[0]; v.Slot != collector.Word(base) || v.Type != "mapping" || v.Writes != 1 || v.Reads != 1 {
+		t.Errorf("unexpected mapping variable %+v", v)
+	}
+	if v := layout[1]; v.Slot != collector.Uint64ToWord(2) || v.Type != "value" || v.Reads != 1 || v.Writes != 0 {
+		t.Errorf("unexpected value variable %+v", v)
+	}
+}
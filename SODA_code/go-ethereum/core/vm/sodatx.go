@@ -0,0 +1,217 @@
+package vm
+
+//add new file
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/fei"
+	"github.com/ethereum/go-ethereum/tingrong"
+)
+
+// TxState is the state a transaction is reported from, implemented by
+// state.StateDB.
+type TxState interface {
+	StateDB
+	BeginTxDiff()
+	TxDiff() *collector.StateDiff
+}
+
+// SODATx describes a transaction to the plugins. Block transactions and the
+// runtime both go through StartSODATx, ReportSODATx and EndSODATx, so plugins
+// see the same events for either.
+type SODATx struct {
+	Hash     common.Hash
+	From     common.Address
+	To       *common.Address // nil for a contract creation
+	Value    *big.Int
+	GasPrice *big.Int
+	Gas      uint64
+	Nonce    uint64
+	Input    []byte
+}
+
+// StartSODATx applies the pending plugin API requests, resets the state kept
+// per transaction and announces tx to the plugins. Chain may be nil, leaving
+// the host only header to read.
+func (evm *EVM) StartSODATx(db TxState, chain HeaderChain, header *types.Header, tx *SODATx) {
+	plg := evm.ChainConfig().TransferDataPlg
+
+	evm.SetTxStart(true)
+	plg.Start()
+	plg.SetHost(NewHost(db, chain, header))
+	applyPluginRequests(plg)
+
+	tingrong.CALL_LAYER = 0
+	tingrong.CALL_STACK = nil
+	tingrong.ALL_STACK = nil
+	tingrong.EXTERNAL_FLAG = true
+	tingrong.BLOCKING_FLAG = false
+	tingrong.PLUGIN_SNAPSHOT_ID = 0
+	tingrong.CALLVALID_MAP = make(map[int]bool)
+	tingrong.CALL_TREE.Reset()
+	tingrong.PREIMAGES.Reset()
+	db.BeginTxDiff()
+	tingrong.TxHash = tx.Hash.String()
+	tingrong.BlockNumber = header.Number.Uint64()
+
+	if tx.To != nil {
+		tingrong.CALL_LAYER += 1
+		tingrong.CALL_STACK = append(tingrong.CALL_STACK, tx.To.String()+"#"+strconv.Itoa(tingrong.CALL_LAYER))
+		tingrong.ALL_STACK = append(tingrong.ALL_STACK, tx.To.String())
+	}
+	if plg.HasEvent(pluginManage.EvTxStart) {
+		plg.SendEvent(pluginManage.EvTxStart, collector.FlagEvent("TXSTART"))
+	}
+	if plg.HasEvent(pluginManage.EvExternalInfoStart) {
+		ev := &collector.TxStartEvent{
+			TxHash:      tx.Hash,
+			BlockNumber: header.Number.Uint64(),
+			BlockTime:   header.Time,
+			From:        tx.From,
+			Value:       collector.BigToWord(tx.Value),
+			GasPrice:    collector.BigToWord(tx.GasPrice),
+			GasLimit:    tx.Gas,
+			Nonce:       tx.Nonce,
+			Input:       tx.Input,
+			Create:      tx.To == nil,
+		}
+		if tx.To != nil {
+			ev.To = *tx.To
+			if db.Exist(*tx.To) {
+				ev.Code = db.GetCode(*tx.To)
+			}
+		}
+		plg.SendEvent(pluginManage.EvExternalInfoStart, ev.SendTxStartEvent())
+	}
+}
+
+// applyPluginRequests carries out the plugin registrations and recordings
+// requested over the API since the last transaction.
+func applyPluginRequests(plg *pluginManage.PluginManages) {
+	if fei.IsReg {
+		//single plugin
+		pluginManage.RegisterPlugin(plg, fei.RegPath)
+		fei.RegPath = fei.Clear
+		fei.IsReg = false
+	}
+	if fei.IsUn {
+		plg.UnRegisterPlg()
+		fei.IsUn = false
+		fei.UnPlg = fei.Clear
+	}
+	if fei.IsRecord {
+		filter := &pluginManage.RecordFilter{From: fei.RecordFrom, To: fei.RecordTo}
+		for _, hash := range fei.RecordTxs {
+			filter.Txs = append(filter.Txs, common.HexToHash(hash))
+		}
+		if err := plg.StartRecording(fei.RecordPath, filter); err != nil {
+			fmt.Println("Recording failed:", err)
+		}
+		fei.IsRecord = false
+		fei.RecordPath, fei.RecordTxs, fei.RecordFrom, fei.RecordTo = fei.Clear, nil, 0, 0
+	}
+	if fei.IsStopRecord {
+		if err := plg.StopRecording(); err != nil {
+			fmt.Println("Recording failed:", err)
+		}
+		fei.IsStopRecord = false
+	}
+}
+
+// ReportSODATx reverts the executed transaction if a plugin blocked it and
+// reports its balance and token transfers, call tree and state diff. The diff
+// is taken before the revert, so it holds what a blocked transaction did. It
+// must run before the state is finalised and only for a transaction that ran.
+func (evm *EVM) ReportSODATx(db TxState) {
+	plg := evm.ChainConfig().TransferDataPlg
+
+	var diff *collector.StateDiff
+	if plg.HasEvent(pluginManage.EvTokenTransfer) || plg.HasEvent(pluginManage.EvTxStateDiff) {
+		diff = db.TxDiff()
+	}
+	if tingrong.BLOCKING_FLAG {
+		db.RevertToSnapshot(tingrong.PLUGIN_SNAPSHOT_ID)
+	}
+	if plg.HasEvent(pluginManage.EvBalanceTransfer) {
+		for _, tr := range tingrong.CALL_TREE.Transfers() {
+			tr.Reverted = tr.Reverted || tingrong.BLOCKING_FLAG
+			plg.SendEvent(pluginManage.EvBalanceTransfer, tr.SendBalanceTransferEvent())
+		}
+	}
+	if plg.HasEvent(pluginManage.EvTokenTransfer) {
+		for _, tr := range tingrong.CALL_TREE.TokenTransfers(diff) {
+			tr.Reverted = tr.Reverted || tingrong.BLOCKING_FLAG
+			plg.SendEvent(pluginManage.EvTokenTransfer, tr.SendTokenTransferEvent())
+		}
+	}
+	if root := tingrong.CALL_TREE.Root(); root != nil && plg.HasEvent(pluginManage.EvCallTree) {
+		plg.SendEvent(pluginManage.EvCallTree, root.SendCallTreeEvent())
+	}
+	if plg.HasEvent(pluginManage.EvTxStateDiff) {
+		plg.SendEvent(pluginManage.EvTxStateDiff, diff.SendStateDiffEvent())
+	}
+}
+
+// EndSODATx reports the end of tx along with its receipt and clears the state
+// kept per transaction. A transaction rejected before it ran has no receipt,
+// only its EXTERNALINFOEND is sent with the cause it was rejected for.
+func (evm *EVM) EndSODATx(db TxState, tx *SODATx, gasUsed uint64, receipt *types.Receipt, rejected string) {
+	plg := evm.ChainConfig().TransferDataPlg
+	defer plg.SetHost(nil)
+
+	if plg.HasEvent(pluginManage.EvExternalInfoEnd) {
+		ev := &collector.TxEndEvent{
+			TxHash:  tx.Hash,
+			GasUsed: gasUsed,
+			From:    tx.From,
+			Value:   collector.BigToWord(tx.Value),
+			Input:   tx.Input,
+		}
+		if tx.To != nil {
+			ev.To = *tx.To
+		}
+		if root := tingrong.CALL_TREE.Root(); root != nil {
+			ev.Failure, ev.RevertReason = root.Failure, root.RevertReason
+		}
+		if receipt == nil {
+			ev.Failure = rejected
+			plg.SendEvent(pluginManage.EvExternalInfoEnd, ev.SendTxEndEvent())
+			return
+		}
+		if tx.To == nil {
+			ev.Create = true
+			ev.Contract = receipt.ContractAddress
+			ev.DeployCode = tx.Input
+			if db.Exist(receipt.ContractAddress) {
+				ev.RuntimeCode = db.GetCode(receipt.ContractAddress)
+			}
+		}
+		ev.Success = receipt.Status == types.ReceiptStatusSuccessful
+		ev.BlockNumber = receipt.BlockNumber.Uint64()
+		ev.TxIndex = uint64(receipt.TransactionIndex)
+		ev.Status = receipt.Status
+		ev.PostState = receipt.PostState
+		ev.CumulativeGasUsed = receipt.CumulativeGasUsed
+		ev.Bloom = receipt.Bloom.Bytes()
+		ev.Logs = ReceiptLogs(receipt.Logs)
+		plg.SendEvent(pluginManage.EvExternalInfoEnd, ev.SendTxEndEvent())
+	}
+	if receipt == nil {
+		return
+	}
+	if len(tingrong.CALL_STACK) > 0 {
+		tingrong.CALL_STACK = tingrong.CALL_STACK[:len(tingrong.CALL_STACK)-1]
+	}
+	if plg.HasEvent(pluginManage.EvTxEnd) {
+		plg.SendEvent(pluginManage.EvTxEnd, collector.FlagEvent("TXEND"))
+		plg.Stop()
+	}
+	evm.SetTxStart(false)
+}
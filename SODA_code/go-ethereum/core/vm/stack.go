@@ -29,13 +29,13 @@ import (
 type Stack struct {
 	data []*big.Int
 	//add new 
-	collector *collector.InsCollector
+	collector *collector.InsEvent
 	flag      bool
 }
 
 func newstack() *Stack {
 	//add new 
-	return &Stack{data: make([]*big.Int, 0, 1024), collector: collector.NewCollector(), flag: false}
+	return &Stack{data: make([]*big.Int, 0, 1024), collector: collector.NewInsEvent(), flag: false}
 }
 
 // Data returns the underlying big.Int array.
@@ -66,7 +66,7 @@ func (st *Stack) len() int {
 func (st *Stack) swap(n int) {
 	//add new 
 	if st.flag {
-		st.collector.OpInOut.OpArgs = append(st.collector.OpInOut.OpArgs, st.data[st.len()-n].String(), st.data[st.len()-1].String())
+		st.collector.AddArgs(st.data[st.len()-n], st.data[st.len()-1])
 	}
 	st.data[st.len()-n], st.data[st.len()-1] = st.data[st.len()-1], st.data[st.len()-n]
 }
@@ -76,7 +76,7 @@ func (st *Stack) dup(pool *intPool, n int) {
 	p := pool.get().Set(st.data[st.len()-n])
 	st.push(p)
 	if st.flag {
-		st.collector.OpInOut.OpResult = p.String()
+		st.collector.SetResult(p)
 	}
 }
 
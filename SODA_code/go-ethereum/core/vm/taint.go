@@ -0,0 +1,181 @@
+package vm
+
+//add new file
+
+import (
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// The taint engine follows the values produced by the taint sources of the
+// collector package through the stack, memory and storage of a transaction,
+// so plugins can tell which sources the arguments of an instruction were
+// computed from. It only runs when a subscribed plugin asked for it.
+//
+// An instruction taints the values it pushes with the union of the taint of
+// the values it pops, except for the sources, which push their own taint, and
+// for the loads, which push the taint of the memory or storage they read.
+// Memory is tracked per 32-byte word: a write covering a whole word replaces
+// its taint, a partial one adds to it. Storage taint lasts for the whole
+// transaction, even if the frame that wrote it is reverted.
+
+// taintSlot identifies a storage slot.
+type taintSlot struct {
+	addr common.Address
+	key  common.Hash
+}
+
+// frameTaint holds the taint of the stack items and memory words of a frame.
+type frameTaint struct {
+	stack []collector.Taint // in step with the stack, bottom first
+	mem   []collector.Taint // per memory word
+}
+
+func newFrameTaint() *frameTaint {
+	return &frameTaint{stack: make([]collector.Taint, 0, 16)}
+}
+
+// taintEffect is what an instruction does to the tracked taint. It is worked
+// out before the instruction runs, while the stack items it depends on are
+// still there, and applied once it ran.
+type taintEffect struct {
+	pushes int             // number of items pushed
+	result collector.Taint // taint of the pushed items
+
+	memWrite bool
+	memOff   uint64
+	memSize  uint64
+	memTaint collector.Taint
+
+	slotWrite bool
+	slot      common.Hash
+	slotTaint collector.Taint
+}
+
+// taintBefore pops the taint of the items consumed by the instruction about to
+// run and returns its effect. The taint of the arguments is added to the
+// instruction event, if the instruction is collected.
+func (in *EVMInterpreter) taintBefore(ft *frameTaint, op OpCode, operation operation, contract *Contract, stack *Stack) (eff taintEffect) {
+	// DUP and SWAP move taint around instead of computing new values.
+	switch {
+	case op >= DUP1 && op <= DUP16:
+		ft.stack = append(ft.stack, ft.stack[len(ft.stack)-1-int(op-DUP1)])
+		return eff
+	case op >= SWAP1 && op <= SWAP16:
+		top, n := len(ft.stack)-1, int(op-SWAP1)+1
+		ft.stack[top], ft.stack[top-n] = ft.stack[top-n], ft.stack[top]
+		return eff
+	}
+	pops := operation.minStack
+	eff.pushes = int(params.StackLimit) + pops - operation.maxStack
+
+	args := make([]collector.Taint, pops)
+	for i := range args {
+		args[i] = ft.stack[len(ft.stack)-1-i]
+		eff.result |= args[i]
+	}
+	ft.stack = ft.stack[:len(ft.stack)-pops]
+	if stack.flag {
+		stack.collector.ArgTaint = args
+	}
+
+	switch op {
+	case ORIGIN:
+		eff.result = collector.TaintOrigin
+	case TIMESTAMP:
+		eff.result = collector.TaintTimestamp
+	case NUMBER:
+		eff.result = collector.TaintNumber
+	case BLOCKHASH:
+		eff.result = collector.TaintBlockhash
+	case BALANCE:
+		eff.result = collector.TaintBalance
+	case CALLDATALOAD:
+		eff.result = collector.TaintCallData
+	case SLOAD:
+		eff.result = collector.TaintStorage | in.evm.taintSlots[taintSlot{contract.Address(), common.BigToHash(stack.Back(0))}]
+	case MLOAD:
+		eff.result = ft.read(stack.Back(0).Uint64(), 32)
+	case SHA3:
+		eff.result = ft.read(stack.Back(0).Uint64(), stack.Back(1).Uint64())
+	case CREATE, CREATE2:
+		eff.result = 0
+
+	case MSTORE:
+		eff.setMem(stack.Back(0).Uint64(), 32, args[1])
+	case MSTORE8:
+		eff.setMem(stack.Back(0).Uint64(), 1, args[1])
+	case CALLDATACOPY:
+		eff.setMem(stack.Back(0).Uint64(), stack.Back(2).Uint64(), collector.TaintCallData)
+	case CODECOPY:
+		eff.setMem(stack.Back(0).Uint64(), stack.Back(2).Uint64(), 0)
+	case EXTCODECOPY:
+		eff.setMem(stack.Back(1).Uint64(), stack.Back(3).Uint64(), 0)
+	case RETURNDATACOPY:
+		eff.setMem(stack.Back(0).Uint64(), stack.Back(2).Uint64(), collector.TaintReturnData)
+	case CALL, CALLCODE:
+		eff.result = collector.TaintReturnData
+		eff.setMem(stack.Back(5).Uint64(), stack.Back(6).Uint64(), collector.TaintReturnData)
+	case DELEGATECALL, STATICCALL:
+		eff.result = collector.TaintReturnData
+		eff.setMem(stack.Back(4).Uint64(), stack.Back(5).Uint64(), collector.TaintReturnData)
+
+	case SSTORE:
+		eff.slotWrite, eff.slot, eff.slotTaint = true, common.BigToHash(stack.Back(0)), args[1]
+	}
+	return eff
+}
+
+// setMem records a write of size bytes at off. Memory has been expanded to
+// cover the range already, so the offsets are valid whenever size isn't zero.
+func (eff *taintEffect) setMem(off, size uint64, taint collector.Taint) {
+	eff.memWrite, eff.memOff, eff.memSize, eff.memTaint = size > 0, off, size, taint
+}
+
+// taintAfter applies the effect of an instruction that ran successfully.
+func (in *EVMInterpreter) taintAfter(ft *frameTaint, eff *taintEffect, contract *Contract) {
+	for i := 0; i < eff.pushes; i++ {
+		ft.stack = append(ft.stack, eff.result)
+	}
+	if eff.memWrite {
+		ft.write(eff.memOff, eff.memSize, eff.memTaint)
+	}
+	if eff.slotWrite {
+		slot := taintSlot{contract.Address(), eff.slot}
+		if eff.slotTaint == 0 {
+			delete(in.evm.taintSlots, slot)
+			return
+		}
+		if in.evm.taintSlots == nil {
+			in.evm.taintSlots = make(map[taintSlot]collector.Taint)
+		}
+		in.evm.taintSlots[slot] = eff.slotTaint
+	}
+}
+
+// read returns the taint of size bytes of memory at off.
+func (ft *frameTaint) read(off, size uint64) (taint collector.Taint) {
+	if size == 0 {
+		return 0
+	}
+	for w := off / 32; w <= (off+size-1)/32 && w < uint64(len(ft.mem)); w++ {
+		taint |= ft.mem[w]
+	}
+	return taint
+}
+
+// write taints size bytes of memory at off.
+func (ft *frameTaint) write(off, size uint64, taint collector.Taint) {
+	first, last := off/32, (off+size-1)/32
+	for uint64(len(ft.mem)) <= last {
+		ft.mem = append(ft.mem, 0)
+	}
+	for w := first; w <= last; w++ {
+		if off <= w*32 && (w+1)*32 <= off+size {
+			ft.mem[w] = taint
+		} else {
+			ft.mem[w] |= taint
+		}
+	}
+}
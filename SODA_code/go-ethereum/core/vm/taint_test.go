@@ -0,0 +1,113 @@
+package vm
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/collector"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	tTime = collector.TaintTimestamp
+	tOrig = collector.TaintOrigin
+	tData = collector.TaintCallData
+)
+
+func TestTaintInstructions(t *testing.T) {
+	tests := []struct {
+		op        OpCode
+		stack     []int64           // top first
+		taint     []collector.Taint // top first
+		want      []collector.Taint // taint of the stack afterwards, top first
+		wantArgs  []collector.Taint
+		wantSlots map[common.Hash]collector.Taint
+	}{
+		// Computations taint their result with their arguments.
+		{ADD, []int64{1, 2, 3}, []collector.Taint{tTime, tOrig, tData}, []collector.Taint{tTime | tOrig, tData}, []collector.Taint{tTime, tOrig}, nil},
+		{ISZERO, []int64{0}, []collector.Taint{tData}, []collector.Taint{tData}, []collector.Taint{tData}, nil},
+		// Sources push their own taint.
+		{TIMESTAMP, nil, nil, []collector.Taint{tTime}, []collector.Taint{}, nil},
+		{CALLDATALOAD, []int64{4}, []collector.Taint{tOrig}, []collector.Taint{tData}, []collector.Taint{tOrig}, nil},
+		// DUP and SWAP move taint around.
+		{DUP2, []int64{1, 2}, []collector.Taint{tTime, tOrig}, []collector.Taint{tOrig, tTime, tOrig}, nil, nil},
+		{SWAP2, []int64{1, 2, 3}, []collector.Taint{tTime, 0, tOrig}, []collector.Taint{tOrig, 0, tTime}, nil, nil},
+		{POP, []int64{1, 2}, []collector.Taint{tTime, tOrig}, []collector.Taint{tOrig}, []collector.Taint{tTime}, nil},
+		// Storage keeps the taint of the value written, clean writes clear it.
+		// Slot 7 starts out tainted by the origin.
+		{SSTORE, []int64{7, 1}, []collector.Taint{0, tTime}, []collector.Taint{}, []collector.Taint{0, tTime}, map[common.Hash]collector.Taint{common.BigToHash(big.NewInt(7)): tTime}},
+		{SSTORE, []int64{7, 1}, []collector.Taint{tTime, 0}, []collector.Taint{}, []collector.Taint{tTime, 0}, map[common.Hash]collector.Taint{}},
+		{SLOAD, []int64{7}, []collector.Taint{0}, []collector.Taint{collector.TaintStorage | tOrig}, []collector.Taint{0}, nil},
+		// Loads push the taint of what they read, not of the key.
+		{SLOAD, []int64{8}, []collector.Taint{tData}, []collector.Taint{collector.TaintStorage}, []collector.Taint{tData}, nil},
+	}
+	contract := NewContract(AccountRef(common.Address{1}), AccountRef(common.Address{2}), new(big.Int), 0)
+	for i, tt := range tests {
+		in := newTestInterpreter(t, nil)
+		in.evm.taintSlots = map[taintSlot]collector.Taint{{contract.Address(), common.BigToHash(big.NewInt(7))}: tOrig}
+		stack, ft := newstack(), newFrameTaint()
+		stack.flag, stack.collector = true, collector.NewInsEvent()
+		for j := len(tt.stack) - 1; j >= 0; j-- {
+			stack.push(big.NewInt(tt.stack[j]))
+			ft.stack = append(ft.stack, tt.taint[j])
+		}
+		eff := in.taintBefore(ft, tt.op, in.cfg.JumpTable[tt.op], contract, stack)
+		in.taintAfter(ft, &eff, contract)
+
+		have := make([]collector.Taint, len(ft.stack))
+		for j := range ft.stack {
+			have[j] = ft.stack[len(ft.stack)-1-j]
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d (%v): have stack taint %v, want %v", i, tt.op, have, tt.want)
+		}
+		if tt.wantArgs != nil && !reflect.DeepEqual(stack.collector.ArgTaint, tt.wantArgs) {
+			t.Errorf("test %d (%v): have argument taint %v, want %v", i, tt.op, stack.collector.ArgTaint, tt.wantArgs)
+		}
+		if tt.wantSlots != nil {
+			slots := make(map[common.Hash]collector.Taint)
+			for slot, taint := range in.evm.taintSlots {
+				slots[slot.key] = taint
+			}
+			if !reflect.DeepEqual(slots, tt.wantSlots) {
+				t.Errorf("test %d (%v): have slot taint %v, want %v", i, tt.op, slots, tt.wantSlots)
+			}
+		}
+	}
+}
+
+func TestTaintMemory(t *testing.T) {
+	type write struct {
+		off, size uint64
+		taint     collector.Taint
+	}
+	tests := []struct {
+		writes    []write
+		off, size uint64
+		want      collector.Taint
+	}{
+		// A whole word replaces the taint of the word.
+		{[]write{{0, 32, tTime}, {0, 32, tOrig}}, 0, 32, tOrig},
+		// A partial write adds to it.
+		{[]write{{0, 32, tTime}, {4, 1, tOrig}}, 0, 32, tTime | tOrig},
+		// Writes across words cover every word they touch.
+		{[]write{{16, 32, tData}}, 0, 1, tData},
+		{[]write{{16, 32, tData}}, 32, 1, tData},
+		{[]write{{16, 32, tData}}, 64, 32, 0},
+		{[]write{{0, 96, tTime}, {32, 32, 0}}, 32, 32, 0},
+		{[]write{{0, 96, tTime}, {32, 32, 0}}, 0, 96, tTime},
+		// Empty reads and reads past the written memory are clean.
+		{[]write{{0, 32, tTime}}, 0, 0, 0},
+		{nil, 1000, 32, 0},
+	}
+	for i, tt := range tests {
+		ft := newFrameTaint()
+		for _, w := range tt.writes {
+			ft.write(w.off, w.size, w.taint)
+		}
+		if have := ft.read(tt.off, tt.size); have != tt.want {
+			t.Errorf("test %d: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import Header from 'Header';
 import Body from 'Body';
 import {inserter as logInserter, SAME} from 'Logs';
 import {inserter as peerInserter} from 'Network';
+import {blockInserter} from 'SODA';
 import {MENU} from '../common';
 import type {Content} from '../types/content';
 
@@ -112,6 +113,11 @@ const defaultContent: () => Content = () => ({
 		topChanged:    SAME,
 		bottomChanged: SAME,
 	},
+	soda: {
+		alerts:  [],
+		plugins: [],
+		blocks:  [],
+	},
 });
 
 // updaters contains the state updater functions for each path of the state.
@@ -137,6 +143,11 @@ const updaters = {
 		diskWrite:      appender(200),
 	},
 	logs: logInserter(5),
+	soda: {
+		alerts:  appender(200),
+		plugins: replacer,
+		blocks:  blockInserter(200),
+	},
 };
 
 // styles contains the constant styles of the component.
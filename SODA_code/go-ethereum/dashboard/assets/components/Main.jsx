@@ -22,6 +22,7 @@ import withStyles from '@material-ui/core/styles/withStyles';
 
 import Network from 'Network';
 import Logs from 'Logs';
+import SODA from 'SODA';
 import Footer from 'Footer';
 import {MENU} from '../common';
 import type {Content} from '../types/content';
@@ -119,6 +120,12 @@ class Main extends Component<Props, State> {
 					shouldUpdate={shouldUpdate}
 				/>
 			);
+			break;
+		case MENU.get('soda').id:
+			children = <SODA
+				content={this.props.content.soda}
+				shouldUpdate={shouldUpdate}
+			/>;
 		}
 
 		return (
@@ -0,0 +1,215 @@
+// @flow
+
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+import React, {Component} from 'react';
+
+import Table from '@material-ui/core/Table';
+import TableHead from '@material-ui/core/TableHead';
+import TableBody from '@material-ui/core/TableBody';
+import TableRow from '@material-ui/core/TableRow';
+import TableCell from '@material-ui/core/TableCell';
+import Grid from '@material-ui/core/Grid/Grid';
+import Typography from '@material-ui/core/Typography';
+import {BarChart, Bar, Tooltip, XAxis, YAxis} from 'recharts';
+import {FontAwesomeIcon} from '@fortawesome/react-fontawesome';
+import {faCircle as fasCircle} from '@fortawesome/free-solid-svg-icons';
+import {faCircle as farCircle} from '@fortawesome/free-regular-svg-icons';
+
+import type {SODA as SODAType, BlockAlerts} from '../types/content';
+import {styles as commonStyles} from '../common';
+
+// Alert chart dimensions.
+const alertChartHeight = 200;
+const alertChartWidth  = 800;
+
+const warningColor = '#FFFF00';
+const seriousColor = '#FF0000';
+
+// blockInserter is a state updater function for the per block alert counters.
+// The counters of a block are replaced if the block is already present, the
+// other blocks are appended. limit defines the maximum number of blocks.
+export const blockInserter = (limit: number) => (update: Array<BlockAlerts>, prev: Array<BlockAlerts>) => {
+	const blocks = [...prev];
+	update.forEach((block) => {
+		const i = blocks.findIndex(b => b.number === block.number);
+		if (i < 0) {
+			blocks.push(block);
+		} else {
+			blocks[i] = block;
+		}
+	});
+	return blocks.slice(-limit);
+};
+
+// styles contains the constant styles of the component.
+const styles = {
+	tableHead: {
+		height: 'auto',
+	},
+	tableRow: {
+		height: 'auto',
+	},
+	tableCell: {
+		paddingTop:    0,
+		paddingRight:  5,
+		paddingBottom: 0,
+		paddingLeft:   5,
+		border:        'none',
+	},
+	section: {
+		marginBottom: 24,
+	},
+};
+
+export type Props = {
+	content:      SODAType,
+	shouldUpdate: Object,
+};
+
+type State = {};
+
+// SODA renders the alerts raised by the detection plugins and their health.
+class SODA extends Component<Props, State> {
+	shouldComponentUpdate(nextProps: Readonly<Props>, nextState: Readonly<State>, nextContext: any) {
+		return typeof nextProps.shouldUpdate.soda !== 'undefined';
+	}
+
+	formatTime = (t: string) => {
+		const time = new Date(t);
+		if (isNaN(time)) {
+			return '';
+		}
+		const hours = `0${time.getHours()}`.slice(-2);
+		const minutes = `0${time.getMinutes()}`.slice(-2);
+		const seconds = `0${time.getSeconds()}`.slice(-2);
+		return `${hours}:${minutes}:${seconds}`;
+	};
+
+	// formatLatency renders a latency given in nanoseconds.
+	formatLatency = (ns: number) => {
+		if (ns >= 1e6) {
+			return `${(ns / 1e6).toFixed(2)} ms`;
+		}
+		return `${(ns / 1e3).toFixed(2)} µs`;
+	};
+
+	pluginTable = () => (
+		<Table>
+			<TableHead style={styles.tableHead}>
+				<TableRow style={styles.tableRow}>
+					<TableCell style={styles.tableCell} />
+					<TableCell style={styles.tableCell}>Plugin</TableCell>
+					<TableCell style={styles.tableCell}>Events/s</TableCell>
+					<TableCell style={styles.tableCell}>Avg latency</TableCell>
+					<TableCell style={styles.tableCell}>Warnings</TableCell>
+					<TableCell style={styles.tableCell}>Serious</TableCell>
+					<TableCell style={styles.tableCell}>Faults</TableCell>
+				</TableRow>
+			</TableHead>
+			<TableBody>
+				{this.props.content.plugins.map(plugin => (
+					<TableRow key={plugin.name} style={styles.tableRow}>
+						<TableCell style={styles.tableCell}>
+							{plugin.enabled
+								? <FontAwesomeIcon icon={fasCircle} color='green' />
+								: <FontAwesomeIcon icon={farCircle} style={commonStyles.light} />
+							}
+						</TableCell>
+						<TableCell style={styles.tableCell}>{plugin.name}</TableCell>
+						<TableCell style={styles.tableCell}>{plugin.eventRate.toFixed(1)}</TableCell>
+						<TableCell style={styles.tableCell}>{this.formatLatency(plugin.latency)}</TableCell>
+						<TableCell style={styles.tableCell}>{plugin.warning}</TableCell>
+						<TableCell style={styles.tableCell}>{plugin.serious}</TableCell>
+						<TableCell style={styles.tableCell}>{plugin.faults}</TableCell>
+					</TableRow>
+				))}
+			</TableBody>
+		</Table>
+	);
+
+	alertChart = () => (
+		<BarChart
+			width={alertChartWidth}
+			height={alertChartHeight}
+			data={this.props.content.blocks}
+			margin={{top: 5, right: 5, bottom: 5, left: 5}}
+		>
+			<XAxis dataKey='number' />
+			<YAxis allowDecimals={false} />
+			<Tooltip cursor={false} />
+			<Bar isAnimationActive={false} dataKey='warning' stackId='alerts' fill={warningColor} />
+			<Bar isAnimationActive={false} dataKey='serious' stackId='alerts' fill={seriousColor} />
+		</BarChart>
+	);
+
+	alertTable = () => (
+		<Table>
+			<TableHead style={styles.tableHead}>
+				<TableRow style={styles.tableRow}>
+					<TableCell style={styles.tableCell}>Time</TableCell>
+					<TableCell style={styles.tableCell}>Severity</TableCell>
+					<TableCell style={styles.tableCell}>Plugin</TableCell>
+					<TableCell style={styles.tableCell}>Block</TableCell>
+					<TableCell style={styles.tableCell}>Transaction</TableCell>
+					<TableCell style={styles.tableCell}>Contract</TableCell>
+					<TableCell style={styles.tableCell}>Message</TableCell>
+				</TableRow>
+			</TableHead>
+			<TableBody>
+				{[...this.props.content.alerts].reverse().map((alert, i) => (
+					<TableRow key={`${alert.txhash}_${i}`} style={styles.tableRow}>
+						<TableCell style={styles.tableCell}>{this.formatTime(alert.time)}</TableCell>
+						<TableCell style={{...styles.tableCell, color: alert.severity === 'serious' ? seriousColor : warningColor}}>
+							{alert.severity}
+						</TableCell>
+						<TableCell style={styles.tableCell}>{alert.plugin}</TableCell>
+						<TableCell style={styles.tableCell}>{alert.block}</TableCell>
+						<TableCell style={{fontFamily: 'monospace', ...styles.tableCell, ...commonStyles.light}}>
+							{alert.txhash.substring(0, 10)}
+						</TableCell>
+						<TableCell style={{fontFamily: 'monospace', ...styles.tableCell, ...commonStyles.light}}>
+							{alert.contract.substring(0, 10)}
+						</TableCell>
+						<TableCell style={styles.tableCell}>{alert.message}</TableCell>
+					</TableRow>
+				))}
+			</TableBody>
+		</Table>
+	);
+
+	render() {
+		return (
+			<Grid container direction='column'>
+				<Grid item style={styles.section}>
+					<Typography variant='subtitle1'>Plugins</Typography>
+					{this.pluginTable()}
+				</Grid>
+				<Grid item style={styles.section}>
+					<Typography variant='subtitle1'>Alerts per block</Typography>
+					{this.alertChart()}
+				</Grid>
+				<Grid item style={styles.section}>
+					<Typography variant='subtitle1'>Alerts</Typography>
+					{this.alertTable()}
+				</Grid>
+			</Grid>
+		);
+	}
+}
+
+export default SODA;
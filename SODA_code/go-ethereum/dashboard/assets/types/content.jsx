@@ -24,6 +24,7 @@ export type Content = {
 	network: Network,
 	system:  System,
 	logs:    Logs,
+	soda:    SODA,
 };
 
 export type ChartEntries = Array<ChartEntry>;
@@ -136,3 +137,36 @@ export type LogFile = {
 	name: string,
 	last: string,
 };
+
+export type SODA = {
+	alerts:  Array<Alert>,
+	plugins: Array<PluginStatus>,
+	blocks:  Array<BlockAlerts>,
+};
+
+export type Alert = {
+	time:     string,
+	plugin:   string,
+	event:    string,
+	severity: string,
+	message:  string,
+	txhash:   string,
+	contract: string,
+	block:    number,
+};
+
+export type PluginStatus = {
+	name:      string,
+	enabled:   boolean,
+	eventRate: number,
+	latency:   number,
+	faults:    number,
+	warning:   number,
+	serious:   number,
+};
+
+export type BlockAlerts = {
+	number:  number,
+	warning: number,
+	serious: number,
+};
@@ -61,6 +61,7 @@ type Dashboard struct {
 	sysLock  sync.RWMutex // Lock protecting the stored system data
 	peerLock sync.RWMutex // Lock protecting the stored peer data
 	logLock  sync.RWMutex // Lock protecting the stored log data
+	sodaLock sync.RWMutex // Lock protecting the stored SODA data
 
 	geodb  *geoDB // geoip database instance for IP to geographical information conversions
 	logdir string // Directory containing the log files
@@ -102,6 +103,7 @@ func New(config *Config, commit string, logdir string) *Dashboard {
 				DiskRead:       emptyChartEntries(now, sampleLimit),
 				DiskWrite:      emptyChartEntries(now, sampleLimit),
 			},
+			SODA: &SODAMessage{},
 		},
 		logdir: logdir,
 	}
@@ -127,10 +129,11 @@ func (db *Dashboard) APIs() []rpc.API { return nil }
 func (db *Dashboard) Start(server *p2p.Server) error {
 	log.Info("Starting dashboard")
 
-	db.wg.Add(3)
+	db.wg.Add(4)
 	go db.collectSystemData()
 	go db.streamLogs()
 	go db.collectPeerData()
+	go db.collectSODAData()
 
 	http.HandleFunc("/", db.webHandler)
 	http.Handle("/api", websocket.Handler(db.apiHandler))
@@ -156,7 +159,7 @@ func (db *Dashboard) Stop() error {
 	}
 	// Close the collectors.
 	errc := make(chan error, 1)
-	for i := 0; i < 3; i++ {
+	for i := 0; i < 4; i++ {
 		db.quit <- errc
 		if err := <-errc; err != nil {
 			errs = append(errs, err)
@@ -233,12 +236,14 @@ func (db *Dashboard) apiHandler(conn *websocket.Conn) {
 	db.sysLock.RLock()
 	db.peerLock.RLock()
 	db.logLock.RLock()
+	db.sodaLock.RLock()
 
 	h := deepcopy.Copy(db.history).(*Message)
 
 	db.sysLock.RUnlock()
 	db.peerLock.RUnlock()
 	db.logLock.RUnlock()
+	db.sodaLock.RUnlock()
 
 	client.msg <- h
 
@@ -18,6 +18,8 @@ package dashboard
 
 import (
 	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
 )
 
 type Message struct {
@@ -28,6 +30,7 @@ type Message struct {
 	Network *NetworkMessage `json:"network,omitempty"`
 	System  *SystemMessage  `json:"system,omitempty"`
 	Logs    *LogsMessage    `json:"logs,omitempty"`
+	SODA    *SODAMessage    `json:"soda,omitempty"`
 }
 
 type ChartEntries []*ChartEntry
@@ -84,6 +87,31 @@ type LogFile struct {
 	Last bool   `json:"last"` // Denotes if the actual log file is the last one in the directory.
 }
 
+// SODAMessage contains the alerts raised by the SODA detection plugins and their health.
+type SODAMessage struct {
+	Alerts  []*pluginManage.Alert `json:"alerts,omitempty"`  // Alerts raised since the previous message.
+	Plugins []*PluginStatus       `json:"plugins,omitempty"` // Status of every plugin, replaces the previous table.
+	Blocks  []*BlockAlerts        `json:"blocks,omitempty"`  // Updated alert counters of the affected blocks.
+}
+
+// PluginStatus contains the health of a SODA plugin.
+type PluginStatus struct {
+	Name      string  `json:"name"`
+	Enabled   bool    `json:"enabled"`   // Whether the plugin is registered.
+	EventRate float64 `json:"eventRate"` // Handled events per second.
+	Latency   float64 `json:"latency"`   // Average handler latency in nanoseconds.
+	Faults    int64   `json:"faults"`    // Number of handler panics.
+	Warning   int64   `json:"warning"`   // Number of warnings raised.
+	Serious   int64   `json:"serious"`   // Number of serious alerts raised.
+}
+
+// BlockAlerts contains the number of alerts raised while processing a block.
+type BlockAlerts struct {
+	Number  uint64 `json:"number"`
+	Warning int    `json:"warning"`
+	Serious int    `json:"serious"`
+}
+
 // Request represents the client request.
 type Request struct {
 	Logs *LogsRequest `json:"logs,omitempty"`
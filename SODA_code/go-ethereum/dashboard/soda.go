@@ -0,0 +1,160 @@
+package dashboard
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// sodaMetricsPrefix is the prefix of the metrics reported by the SODA plugin manager.
+const sodaMetricsPrefix = "soda/"
+
+// pluginCounters contains the cumulative metrics of a plugin at a given moment.
+type pluginCounters struct {
+	enabled bool
+	calls   int64   // Handler invocations over all events
+	latency float64 // Sum of the mean handler latencies weighted by their invocations
+	faults  int64   // Handler panics
+	warning int64   // Alerts reported as warnings
+	serious int64   // Alerts reported as serious
+}
+
+// collectPluginCounters gathers the SODA plugin metrics from the default registry.
+func collectPluginCounters() map[string]*pluginCounters {
+	counters := make(map[string]*pluginCounters)
+	metrics.DefaultRegistry.Each(func(name string, i interface{}) {
+		if !strings.HasPrefix(name, sodaMetricsPrefix) {
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(name, sodaMetricsPrefix), "/")
+		if len(parts) < 2 {
+			return
+		}
+		c := counters[parts[0]]
+		if c == nil {
+			c = new(pluginCounters)
+			counters[parts[0]] = c
+		}
+		switch metric := i.(type) {
+		case metrics.Gauge:
+			if parts[1] == "enabled" {
+				c.enabled = metric.Value() > 0
+			}
+		case metrics.Timer:
+			if count := metric.Count(); count > 0 {
+				c.latency += metric.Mean() * float64(count)
+			}
+		case metrics.Meter:
+			switch strings.Join(parts[1:], "/") {
+			case "panics":
+				c.faults = metric.Count()
+			case "alerts/warning":
+				c.warning = metric.Count()
+			case "alerts/serious":
+				c.serious = metric.Count()
+			default:
+				if parts[len(parts)-1] == "calls" {
+					c.calls += metric.Count()
+				}
+			}
+		}
+	})
+	return counters
+}
+
+// collectSODAData gathers the alerts and the health of the SODA plugins and sends them to the clients.
+func (db *Dashboard) collectSODAData() {
+	defer db.wg.Done()
+
+	var (
+		seq       uint64
+		prev      = collectPluginCounters()
+		frequency = float64(db.config.Refresh / time.Second)
+	)
+	for {
+		select {
+		case errc := <-db.quit:
+			errc <- nil
+			return
+		case <-time.After(db.config.Refresh):
+			var alerts []*pluginManage.Alert
+			alerts, seq = pluginManage.AlertsSince(seq)
+
+			cur := collectPluginCounters()
+			plugins := make([]*PluginStatus, 0, len(cur))
+			for name, c := range cur {
+				status := &PluginStatus{
+					Name:    name,
+					Enabled: c.enabled,
+					Faults:  c.faults,
+					Warning: c.warning,
+					Serious: c.serious,
+				}
+				if c.calls > 0 {
+					status.Latency = c.latency / float64(c.calls)
+				}
+				calls := c.calls
+				if p, ok := prev[name]; ok {
+					calls -= p.calls
+				}
+				status.EventRate = float64(calls) / frequency
+				plugins = append(plugins, status)
+			}
+			sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+			prev = cur
+
+			db.sodaLock.Lock()
+			soda := db.history.SODA
+			soda.Alerts = append(soda.Alerts, alerts...)
+			if len(soda.Alerts) > sampleLimit {
+				soda.Alerts = soda.Alerts[len(soda.Alerts)-sampleLimit:]
+			}
+			soda.Plugins = plugins
+			blocks := countBlockAlerts(soda, alerts)
+			db.sodaLock.Unlock()
+
+			db.sendToAll(&Message{
+				SODA: &SODAMessage{
+					Alerts:  alerts,
+					Plugins: plugins,
+					Blocks:  blocks,
+				},
+			})
+		}
+	}
+}
+
+// countBlockAlerts adds the alerts to the per block counters of the history
+// and returns the updated counters.
+func countBlockAlerts(soda *SODAMessage, alerts []*pluginManage.Alert) []*BlockAlerts {
+	var updated []*BlockAlerts
+	for _, alert := range alerts {
+		var block *BlockAlerts
+		if n := len(soda.Blocks); n > 0 && soda.Blocks[n-1].Number == alert.Block {
+			block = soda.Blocks[n-1]
+		} else {
+			block = &BlockAlerts{Number: alert.Block}
+			soda.Blocks = append(soda.Blocks, block)
+		}
+		if alert.Severity == "serious" {
+			block.Serious++
+		} else {
+			block.Warning++
+		}
+		if n := len(updated); n == 0 || updated[n-1] != block {
+			updated = append(updated, block)
+		}
+	}
+	if len(soda.Blocks) > sampleLimit {
+		soda.Blocks = soda.Blocks[len(soda.Blocks)-sampleLimit:]
+	}
+	result := make([]*BlockAlerts, len(updated))
+	for i, block := range updated {
+		copied := *block
+		result[i] = &copied
+	}
+	return result
+}
@@ -298,6 +298,38 @@ func NewPrivateDebugAPI(eth *Ethereum) *PrivateDebugAPI {
 	return &PrivateDebugAPI{eth: eth}
 }
 
+//add new
+// RecordBlocks records the plugin events of the transactions in blocks from to
+// to, both inclusive, into the file at path. A zero to records until stopped.
+// The recording methods are private debug methods as they write to any path
+// they are given.
+func (api *PrivateDebugAPI) RecordBlocks(path string, from, to uint64) string {
+	fei.RecordPath = path
+	fei.RecordTxs = nil
+	fei.RecordFrom, fei.RecordTo = from, to
+	fei.IsRecord = true
+	return "Record Start"
+}
+
+// RecordTxs records the plugin events of the given transactions into the file
+// at path.
+func (api *PrivateDebugAPI) RecordTxs(path string, txs []common.Hash) string {
+	fei.RecordPath = path
+	fei.RecordTxs = nil
+	for _, hash := range txs {
+		fei.RecordTxs = append(fei.RecordTxs, hash.Hex())
+	}
+	fei.RecordFrom, fei.RecordTo = 0, 0
+	fei.IsRecord = true
+	return "Record Start"
+}
+
+// StopRecording finishes the running event recording.
+func (api *PrivateDebugAPI) StopRecording() string {
+	fei.IsStopRecord = true
+	return "Record Stop"
+}
+
 // Preimage is a debug API function that returns the preimage for a sha3 hash, if known.
 func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	if preimage := rawdb.ReadPreimage(api.eth.ChainDb(), hash); preimage != nil {
@@ -7,3 +7,10 @@ var IsUn bool = false //是否卸载一个插件
 var UnPlg string      //卸载的插件的名字
 
 var Clear string //清空变量
+
+var IsRecord bool = false     //whether to start recording the plugin events
+var RecordPath string         //file the events are recorded to
+var RecordTxs []string        //transactions to record
+var RecordFrom uint64         //first block to record
+var RecordTo uint64           //last block to record, 0 for no limit
+var IsStopRecord bool = false //whether to stop the running recording
@@ -445,6 +445,21 @@ web3._extend({
 			params: 2,
 			inputFormatter:[null, null],
 		}),
+		new web3._extend.Method({
+			name: 'recordBlocks',
+			call: 'debug_recordBlocks',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'recordTxs',
+			call: 'debug_recordTxs',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'stopRecording',
+			call: 'debug_stopRecording',
+			params: 0
+		}),
 	],
 	properties: []
 });
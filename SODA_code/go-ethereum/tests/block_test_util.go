@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/cmd/pluginManage"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -48,6 +49,57 @@ func (t *BlockTest) UnmarshalJSON(in []byte) error {
 	return json.Unmarshal(in, &t.json)
 }
 
+//add new
+
+// MarshalJSON implements json.Marshaler interface.
+func (t *BlockTest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.json)
+}
+
+// NewBlockTest returns a test importing blocks, generated on top of genesis
+// with the accounts of pre, on the given fork without checking the proof of
+// work. Post holds the accounts expected after the import.
+func NewBlockTest(network string, genesis *types.Block, pre, post core.GenesisAlloc, blocks []*types.Block) (*BlockTest, error) {
+	t := &BlockTest{json: btJSON{
+		Genesis:    newBtHeader(genesis.Header()),
+		Pre:        pre,
+		Post:       post,
+		BestBlock:  common.UnprefixedHash(blocks[len(blocks)-1].Hash()),
+		Network:    network,
+		SealEngine: "NoProof",
+	}}
+	for _, block := range blocks {
+		blob, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			return nil, err
+		}
+		header := newBtHeader(block.Header())
+		t.json.Blocks = append(t.json.Blocks, btBlock{BlockHeader: &header, Rlp: hexutil.Encode(blob)})
+	}
+	return t, nil
+}
+
+func newBtHeader(h *types.Header) btHeader {
+	return btHeader{
+		Bloom:            h.Bloom,
+		Coinbase:         h.Coinbase,
+		MixHash:          h.MixDigest,
+		Nonce:            h.Nonce,
+		Number:           h.Number,
+		Hash:             h.Hash(),
+		ParentHash:       h.ParentHash,
+		ReceiptTrie:      h.ReceiptHash,
+		StateRoot:        h.Root,
+		TransactionsTrie: h.TxHash,
+		UncleHash:        h.UncleHash,
+		ExtraData:        h.Extra,
+		Difficulty:       h.Difficulty,
+		GasLimit:         h.GasLimit,
+		GasUsed:          h.GasUsed,
+		Timestamp:        h.Time,
+	}
+}
+
 type btJSON struct {
 	Blocks     []btBlock             `json:"blocks"`
 	Genesis    btHeader              `json:"genesisBlockHeader"`
@@ -59,9 +111,9 @@ type btJSON struct {
 }
 
 type btBlock struct {
-	BlockHeader  *btHeader
-	Rlp          string
-	UncleHeaders []*btHeader
+	BlockHeader  *btHeader   `json:"blockHeader"`
+	Rlp          string      `json:"rlp"`
+	UncleHeaders []*btHeader `json:"uncleHeaders"`
 }
 
 //go:generate gencodec -type btHeader -field-override btHeaderMarshaling -out gen_btheader.go
@@ -95,10 +147,21 @@ type btHeaderMarshaling struct {
 }
 
 func (t *BlockTest) Run() error {
+	return t.RunWithPlugins(nil)
+}
+
+// RunWithPlugins runs the test with the SODA plugins registered with plg
+// watching the imported blocks. A nil plg runs the test without plugins.
+func (t *BlockTest) RunWithPlugins(plg *pluginManage.PluginManages) error {
 	config, ok := Forks[t.json.Network]
 	if !ok {
 		return UnsupportedForkError{t.json.Network}
 	}
+	if plg != nil {
+		forkConfig := *config
+		forkConfig.TransferDataPlg = plg
+		config = &forkConfig
+	}
 
 	// import pre accounts & construct test genesis block & state root
 	db := rawdb.NewMemoryDatabase()
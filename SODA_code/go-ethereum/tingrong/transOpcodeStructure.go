@@ -1,5 +1,7 @@
 package tingrong
 
+import "github.com/ethereum/collector"
+
 
 //add new file
 
@@ -9,6 +11,7 @@ var COUNT_ARRAY int
 
 //add new
 var TxHash 		string
+var BlockNumber	uint64		//block of the current transaction
 var CALL_LAYER	int
 var CALL_STACK 	[]string	//call contract
 var ALL_STACK   []string    //all contract
@@ -16,4 +19,6 @@ var BLOCKING_FLAG bool		//是否阻断交易
 var EXTERNAL_FLAG bool 		//external call/create
 var PLUGIN_SNAPSHOT_FLAG bool
 var PLUGIN_SNAPSHOT_ID int
-var CALLVALID_MAP map[int]bool
\ No newline at end of file
+var CALLVALID_MAP map[int]bool
+var CALL_TREE collector.CallTree	//call tree of the current transaction
+var PREIMAGES collector.Preimages	//keccak256 preimages of the current transaction
\ No newline at end of file
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/pluginManage/detectortest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	owner    = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bank     = common.HexToAddress("0x000000000000000000000000000000000000ba2c")
+	attacker = common.HexToAddress("0x000000000000000000000000000000000000da0a")
+
+	deposit = big.NewInt(1000)
+)
+
+// vulnerableBank keeps a balance per caller. A call with value deposits it, a
+// call without pays the caller's balance out and only then clears it, like
+// splitDAO did.
+const vulnerableBank = `
+	callvalue
+	jumpi @deposit
+
+	push 0
+	push 0
+	push 0
+	push 0
+	caller
+	sload
+	caller
+	gas
+	call
+	pop
+	push 0
+	caller
+	sstore
+	stop
+
+deposit:
+	callvalue
+	caller
+	sload
+	add
+	caller
+	sstore
+`
+
+// safeBank clears the caller's balance before paying it out, so a re-entrant
+// withdrawal gets nothing.
+const safeBank = `
+	callvalue
+	jumpi @deposit
+
+	caller
+	sload
+	push 0
+	caller
+	sstore
+	push 0
+	push 0
+	push 0
+	push 0
+	dup5
+	caller
+	gas
+	call
+	pop
+	stop
+
+deposit:
+	callvalue
+	caller
+	sload
+	add
+	caller
+	sstore
+`
+
+// thief withdraws from the bank when called by its owner and withdraws again
+// from its fallback whenever the bank pays it, twice at most.
+const thief = `
+	push 0x000000000000000000000000000000000000ba2c
+	caller
+	eq
+	jumpi @reenter
+	jump @withdraw
+
+reenter:
+	push 2
+	push 0
+	sload
+	lt
+	iszero
+	jumpi @done
+	push 0
+	sload
+	push 1
+	add
+	push 0
+	sstore
+
+withdraw:
+	push 0
+	push 0
+	push 0
+	push 0
+	push 0
+	push 0x000000000000000000000000000000000000ba2c
+	gas
+	call
+	pop
+
+done:
+	stop
+`
+
+// newHarness sets up a bank holding the attacker's deposit and that of two
+// other customers, and registers the P1 handlers.
+func newHarness(t *testing.T, bankSource string) *detectortest.Harness {
+	h := detectortest.New(t)
+	h.Register(map[string]interface{}{
+		"Register":                 Register,
+		"Handle_EXTERNALINFOSTART": Handle_EXTERNALINFOSTART,
+		"Handle_EXTERNALINFOEND":   Handle_EXTERNALINFOEND,
+		"Handle_CALLSTART":         Handle_CALLSTART,
+		"Handle_CALLEND":           Handle_CALLEND,
+	})
+	h.Deploy(bank, detectortest.Assemble(t, bankSource))
+	h.Deploy(attacker, detectortest.Assemble(t, thief))
+	h.Fund(bank, new(big.Int).Mul(deposit, big.NewInt(3)))
+	h.SetStorage(bank, attacker.Hash(), common.BigToHash(deposit))
+	return h
+}
+
+func TestReentrancy(t *testing.T) {
+	h := newHarness(t, vulnerableBank)
+	if _, err := h.Call(owner, attacker, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := h.State().GetBalance(attacker), new(big.Int).Mul(deposit, big.NewInt(3)); have.Cmp(want) != 0 {
+		t.Fatalf("attacker balance mismatch: have %v, want %v", have, want)
+	}
+	alert := h.ExpectAlert("P1", "warning")
+
+	var info DaoInfo
+	if err := json.Unmarshal([]byte(alert.Message), &info); err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.ToLower(bank.Hex()); info.Victim != want {
+		t.Errorf("victim mismatch: have %s, want %s", info.Victim, want)
+	}
+	if !strings.Contains(info.Cycle, strings.ToLower(attacker.Hex())) {
+		t.Errorf("cycle %s misses the attacker", info.Cycle)
+	}
+}
+
+func TestNoReentrancy(t *testing.T) {
+	h := newHarness(t, safeBank)
+	if _, err := h.Call(owner, attacker, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if have := h.State().GetBalance(attacker); have.Cmp(deposit) != 0 {
+		t.Fatalf("attacker balance mismatch: have %v, want %v", have, deposit)
+	}
+	h.ExpectNoAlert("P1")
+}
+
+func TestDeposit(t *testing.T) {
+	h := newHarness(t, vulnerableBank)
+	h.Fund(owner, deposit)
+	if _, err := h.Call(owner, bank, nil, deposit); err != nil {
+		t.Fatal(err)
+	}
+	h.ExpectNoAlert("P1")
+}
@@ -4,8 +4,7 @@ import (
 	// "../../pluginlog"
 	// "encoding/hex"
 	"github.com/ethereum/collector"
-	"math/big"
-	"fmt"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/json-iterator/go"
 	"strings"
 )
@@ -13,9 +12,9 @@ import (
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 // var logger pluginlog.ErrTxLog
 
-var	origin_map map[int]map[string]int   // store origin result
+var	origin_map map[int]map[collector.Word]int   // store origin result
 // var txhash string
-var sender_map map[int]string  //store sender of each layer
+var sender_map map[int]common.Address  //store sender of each layer
 
 type RegisterInfo struct {
 	PluginName string   `json:"pluginname"`
@@ -28,8 +27,8 @@ func Register() []byte {
 		PluginName: "P4",
 		OpCode: map[string]string{"EXTERNALINFOSTART":"Handle_EXTERNALINFOSTART", "EQ":"Handle_EQ", "ORIGIN":"Handle_ORIGIN","CALLSTART":"Handle_CALLINFO","CALLCODESTART":"Handle_CALLINFO","DELEGATECALLSTART":"Handle_CALLINFO","STATICCALLSTART":"Handle_CALLINFO"},
 	}
-	origin_map = make(map[int]map[string]int)
-	sender_map = make(map[int]string)
+	origin_map = make(map[int]map[collector.Word]int)
+	sender_map = make(map[int]common.Address)
 	// logger.InitialFileLog("./tx_err_log/detect_origin_new/detect_origin")
 	retInfo, err := json.Marshal(&data)
 	if err != nil {
@@ -40,36 +39,31 @@ func Register() []byte {
 }
 
 
-func Handle_EXTERNALINFOSTART(m *collector.AllCollector) (byte ,string){
-	origin_map = make(map[int]map[string]int)
-	sender_map = make(map[int]string)
-	current_layer := m.TransInfo.CallLayer
-	sender := m.TransInfo.From
-	sender_map[current_layer] = sender
+func Handle_EXTERNALINFOSTART(m *collector.Event) (byte ,string){
+	origin_map = make(map[int]map[collector.Word]int)
+	sender_map = make(map[int]common.Address)
+	sender_map[1] = m.TxStart.From  // the external transaction runs at layer 1
 	// txhash = m.ExternalInfo.TxHash
 	return 0x00,""
 }
 
-func Handle_CALLINFO(m *collector.AllCollector) (byte ,string){
-	current_layer := m.InsInfo.CallLayer
-	sender := m.InsInfo.AccountValue.FromAddr  //add tutu
+func Handle_CALLINFO(m *collector.Event) (byte ,string){
+	current_layer := m.Ins.CallLayer
+	sender := m.Ins.From
 	sender_map[current_layer] = sender
 	return 0x00,""
 }
 
-func Handle_EQ(m *collector.AllCollector) (byte ,string){
-	current_layer := m.InsInfo.CallLayer
+func Handle_EQ(m *collector.Event) (byte ,string){
+	current_layer := m.Ins.CallLayer
 	if _, ok := origin_map[current_layer]; ok{  // eq appear where origin appear
-		for _,i_str := range m.InsInfo.OpInOut.OpArgs{  //add tutu
-			if _, ok1 := origin_map[current_layer][i_str]; ok1{
-				origin_addr_big,_ := new(big.Int).SetString(i_str,10)
-				origin_addr_16 := "0x" + fmt.Sprintf("%040x",origin_addr_big)
-				origin_addr_16 = strings.ToLower(origin_addr_16)
-				write_str := origin_addr_16
-				if m.InsInfo.OpInOut.OpResult == "1"{  //add tutu
-					current_sender := strings.ToLower(sender_map[current_layer])
-					if origin_addr_16 != current_sender{
-						write_str = current_sender + "#" + origin_addr_16
+		for _,arg := range m.Ins.Args{
+			if _, ok1 := origin_map[current_layer][arg]; ok1{
+				origin_addr := arg.Address()
+				if m.Ins.HasResult && !m.Ins.Result.IsZero(){
+					current_sender := sender_map[current_layer]
+					if origin_addr != current_sender{
+						write_str := strings.ToLower(current_sender.Hex()) + "#" + strings.ToLower(origin_addr.Hex())
 						return 0x01,write_str
 					}
 				}
@@ -79,16 +73,13 @@ func Handle_EQ(m *collector.AllCollector) (byte ,string){
 	return 0x00,""
 }
 
-func Handle_ORIGIN(m *collector.AllCollector) (byte ,string){
-	current_layer := m.InsInfo.CallLayer
-	origin_addr := m.InsInfo.OpInOut.OpResult   //add tutu
-	// origin_addr_big,_ := new(big.Int).SetString(origin_addr,10)
-	// origin_addr_16 := "0x" + fmt.Sprintf("%040x",origin_addr_big)
-	// origin_addr_16 = strings.ToLower(origin_addr_16)
+func Handle_ORIGIN(m *collector.Event) (byte ,string){
+	current_layer := m.Ins.CallLayer
+	origin_addr := m.Ins.Result
 	if _,ok := origin_map[current_layer]; ok{
 		origin_map[current_layer][origin_addr] = 0
 	}else{
-		temp_map := make(map[string]int)
+		temp_map := make(map[collector.Word]int)
 		temp_map[origin_addr] = 0
 		origin_map[current_layer] = temp_map
 	}
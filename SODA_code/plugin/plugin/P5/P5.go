@@ -199,34 +199,47 @@ func PcInDict(pc uint64, bytecodeHash string) int {
 	return 0
 }
 
+// learn_bytecode records the call sites of the runtime code of contract and
+// returns the hash they are stored under.
+func learn_bytecode(contract string, runtimecode []byte) string {
+	bytecodeHash := Fnvhash(runtimecode)
+	if _, ok := bytecodeHash_map[bytecodeHash];!ok{
+		pc_dict := check_return_value(runtimecode)
+		bytecodeHash_map[bytecodeHash] = pc_dict
+	}
+	contract_map[contract] = bytecodeHash
+	return bytecodeHash
+}
+
 func Handle_BYTECODE(m *collector.AllCollector) (byte ,string){
 	if m.TransInfo.CallType == "CREATE" {
 		contract := m.TransInfo.To
 		contract = strings.ToLower(contract)
 		runtimecode := m.TransInfo.CreateInfo.ContractRuntimeCode
 		if len(runtimecode) > 0{
-			bytecodeHash := Fnvhash(runtimecode)
-			if _, ok := bytecodeHash_map[bytecodeHash];!ok{
-				pc_dict := check_return_value(runtimecode)
-				bytecodeHash_map[bytecodeHash] = pc_dict
-				contract_map[contract] = bytecodeHash
-			}else{
-				contract_map[contract] = bytecodeHash
-			}
+			learn_bytecode(contract, runtimecode)
 		}
 	}	
 	return 0X00,""
 }
 
 
-func Handle_CALLINFO(m *collector.AllCollector) (byte ,string){
+// Handle_CALLINFO reads the code of callers deployed before the plugin was
+// loaded from the host.
+func Handle_CALLINFO(ev *collector.Event, host collector.Host) (byte ,string){
+	m := ev.Compat()
 	if m.TransInfo.CallType == "CALL" {
 		contract := m.TransInfo.From 
 		toaddr := m.TransInfo.To
 		contract = strings.ToLower(contract)
 		pc := m.TransInfo.Pc 
 		layer := m.TransInfo.CallLayer
-		bytecodeHash := contract_map[contract]
+		bytecodeHash, ok := contract_map[contract]
+		if !ok && host != nil {
+			if runtimecode := host.GetCode(ev.Message.From); len(runtimecode) > 0 {
+				bytecodeHash = learn_bytecode(contract, runtimecode)
+			}
+		}
 		get_result := PcInDict(pc, bytecodeHash)
 		if get_result == 1 && m.TransInfo.IsSuccess==false && len(m.TransInfo.CallInfo.ContractCode)>0{
 			return 0x01 , contract + "#" + toaddr +"#"+fmt.Sprintf("%v", layer)+"#"+fmt.Sprintf("%v", pc)
@@ -234,8 +247,3 @@ func Handle_CALLINFO(m *collector.AllCollector) (byte ,string){
 	}
 	return 0x00,""
 }
-
-
-
-
-